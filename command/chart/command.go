@@ -0,0 +1,76 @@
+// Package chart implements the chart command, which bundles the commands
+// managing the chart dirs consumed by the CSV informer.
+package chart
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/spf13/cobra"
+
+	"github.com/xh3b4sd/wafer/command/chart/importer"
+)
+
+// Config represents the configuration used to create a new chart command.
+type Config struct {
+	// Settings.
+	GitCommit string
+}
+
+// DefaultConfig provides a default configuration to create a new chart command
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Settings.
+		GitCommit: "",
+	}
+}
+
+// New creates a new configured chart command.
+func New(config Config) (Command, error) {
+	var err error
+
+	var importCommand importer.Command
+	{
+		importConfig := importer.DefaultConfig()
+
+		importConfig.GitCommit = config.GitCommit
+
+		importCommand, err = importer.New(importConfig)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	newCommand := &command{
+		// Internals.
+		cobraCommand:  nil,
+		importCommand: importCommand,
+	}
+
+	newCommand.cobraCommand = &cobra.Command{
+		Use:   "chart",
+		Short: "Manage the chart dirs consumed by the CSV informer.",
+		Long:  "Manage the chart dirs consumed by the CSV informer.",
+		Run:   newCommand.Execute,
+	}
+	newCommand.cobraCommand.AddCommand(newCommand.importCommand.CobraCommand())
+
+	return newCommand, nil
+}
+
+type command struct {
+	// Internals.
+	cobraCommand  *cobra.Command
+	importCommand importer.Command
+}
+
+func (c *command) CobraCommand() *cobra.Command {
+	return c.cobraCommand
+}
+
+func (c *command) Execute(cmd *cobra.Command, args []string) {
+	cmd.HelpFunc()(cmd, nil)
+}
+
+func (c *command) ImportCommand() importer.Command {
+	return c.importCommand
+}
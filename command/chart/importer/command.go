@@ -0,0 +1,161 @@
+// Package importer implements the chart import command, which converts raw
+// chart exports into the chart dir layout consumed by the CSV informer.
+package importer
+
+import (
+	"fmt"
+	"os"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/spf13/cobra"
+
+	csvimporter "github.com/xh3b4sd/wafer/service/informer/csv/importer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/meta"
+)
+
+// Config represents the configuration used to create a new chart import
+// command.
+type Config struct {
+	// Settings.
+	GitCommit string
+}
+
+// DefaultConfig provides a default configuration to create a new chart import
+// command by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Settings.
+		GitCommit: "",
+	}
+}
+
+// New creates a new configured chart import command.
+func New(config Config) (Command, error) {
+	// Settings.
+	if config.GitCommit == "" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "git commit must not be empty")
+	}
+
+	newCommand := &command{
+		// Internals.
+		cobraCommand: nil,
+
+		// Settings.
+		gitCommit: config.GitCommit,
+	}
+
+	newCommand.cobraCommand = &cobra.Command{
+		Use:   "import",
+		Short: "Import raw chart exports into chart dirs.",
+		Long: `Import raw chart exports into chart dirs.
+
+The given input file is converted into the chart dir layout consumed by the CSV
+informer. The chart dir receives the normalized chart.csv, the header.yaml
+describing it and a provenance.yaml recording the origin of the chart data. The
+input is read from local files only, optionally gzip compressed. Supported
+formats are json, trades and candles.
+
+    wafer chart import --format json --path data --buy usd --time-format rfc3339 --input chart.json --output charts/001
+    wafer chart import --format trades --last 15000 --input coinbaseUSD.csv.gz --output charts/003
+    wafer chart import --format candles --volume volume --input candles.csv --output charts/004`,
+		Run: newCommand.Execute,
+	}
+
+	newCommand.cobraCommand.Flags().String("base", "", "The currency being traded, e.g. BTC.")
+	newCommand.cobraCommand.Flags().String("buy", "", "The column index or name, or the JSON path, of buy prices. Defaults to 1 for trades, close for candles and price for json.")
+	newCommand.cobraCommand.Flags().Int("first", 0, "The number of price events to keep from the start of the chart. 0 keeps all price events.")
+	newCommand.cobraCommand.Flags().String("format", "", "The format of the input. One of json, trades or candles.")
+	newCommand.cobraCommand.Flags().Bool("ignore", false, "Whether to ignore the first row of CSV inputs.")
+	newCommand.cobraCommand.Flags().String("input", "", "The path of the raw export to import.")
+	newCommand.cobraCommand.Flags().Int("last", 0, "The number of price events to keep from the end of the chart. 0 keeps all price events.")
+	newCommand.cobraCommand.Flags().Float64("lot-size", 0, "The smallest step traded volumes can be expressed in. 0 in case it is not declared.")
+	newCommand.cobraCommand.Flags().Float64("maker-fee", 0, "The fee charged for maker orders, in percent.")
+	newCommand.cobraCommand.Flags().String("output", "", "The path of the chart dir to create, e.g. charts/004.")
+	newCommand.cobraCommand.Flags().String("path", "", "The JSON path of the array holding the price events within JSON inputs. Empty in case the document itself is the array.")
+	newCommand.cobraCommand.Flags().String("quote", "", "The currency prices are expressed in, e.g. EUR.")
+	newCommand.cobraCommand.Flags().String("sell", "", "The column index or name, or the JSON path, of sell prices. Empty to use the buy prices.")
+	newCommand.cobraCommand.Flags().String("source", "", "The origin of the input recorded in the provenance, e.g. the address it was downloaded from.")
+	newCommand.cobraCommand.Flags().String("symbol", "", "The name of the market at the exchange, e.g. BTC-EUR.")
+	newCommand.cobraCommand.Flags().Float64("taker-fee", 0, "The fee charged for taker orders, in percent.")
+	newCommand.cobraCommand.Flags().Float64("tick-size", 0, "The smallest step prices can move. 0 in case it is not declared.")
+	newCommand.cobraCommand.Flags().String("time", "", "The column index or name, or the JSON path, of price times. Defaults to 0 for trades and time for candles and json.")
+	newCommand.cobraCommand.Flags().String("time-format", "unix", "The format of price times within the input. One of unix, unixmilli, unixnano, rfc3339 or a Go time layout.")
+	newCommand.cobraCommand.Flags().String("time-zone", "", "The name of the time zone price times within the input are interpreted in, e.g. UTC.")
+	newCommand.cobraCommand.Flags().String("volume", "", "The column index or name, or the JSON path, of traded volumes. Defaults to 2 for trades. Empty otherwise.")
+
+	return newCommand, nil
+}
+
+type command struct {
+	// Internals.
+	cobraCommand *cobra.Command
+
+	// Settings.
+	gitCommit string
+}
+
+func (c *command) CobraCommand() *cobra.Command {
+	return c.cobraCommand
+}
+
+func (c *command) Execute(cmd *cobra.Command, args []string) {
+	p, err := c.execute(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("Input:          %s\n", p.Input)
+	fmt.Printf("Checksum:       %s\n", p.Checksum)
+	fmt.Printf("Rows Read:      %d\n", p.Rows.Read)
+	fmt.Printf("Rows Written:   %d\n", p.Rows.Written)
+}
+
+func (c *command) execute(cmd *cobra.Command) (csvimporter.Provenance, error) {
+	importerConfig := csvimporter.DefaultConfig()
+	{
+		f := cmd.Flags()
+
+		importerConfig.Buy, _ = f.GetString("buy")
+		importerConfig.First, _ = f.GetInt("first")
+		importerConfig.Format, _ = f.GetString("format")
+		importerConfig.Ignore, _ = f.GetBool("ignore")
+		importerConfig.Input, _ = f.GetString("input")
+		importerConfig.Last, _ = f.GetInt("last")
+		importerConfig.Output, _ = f.GetString("output")
+		importerConfig.Path, _ = f.GetString("path")
+		importerConfig.Sell, _ = f.GetString("sell")
+		importerConfig.Source, _ = f.GetString("source")
+		importerConfig.Time, _ = f.GetString("time")
+		importerConfig.TimeFormat, _ = f.GetString("time-format")
+		importerConfig.TimeZone, _ = f.GetString("time-zone")
+		importerConfig.Version = c.gitCommit
+		importerConfig.Volume, _ = f.GetString("volume")
+
+		importerConfig.Meta.Base, _ = f.GetString("base")
+		importerConfig.Meta.LotSize, _ = f.GetFloat64("lot-size")
+		importerConfig.Meta.Quote, _ = f.GetString("quote")
+		importerConfig.Meta.Symbol, _ = f.GetString("symbol")
+		importerConfig.Meta.TickSize, _ = f.GetFloat64("tick-size")
+
+		// The fee schedule is only declared in case any fee is given. Otherwise
+		// consumers of the chart fall back to their own defaults.
+		if f.Changed("maker-fee") || f.Changed("taker-fee") {
+			importerConfig.Meta.Fee = &meta.Fee{}
+			importerConfig.Meta.Fee.Maker, _ = f.GetFloat64("maker-fee")
+			importerConfig.Meta.Fee.Taker, _ = f.GetFloat64("taker-fee")
+		}
+	}
+
+	newImporter, err := csvimporter.New(importerConfig)
+	if err != nil {
+		return csvimporter.Provenance{}, microerror.MaskAny(err)
+	}
+
+	p, err := newImporter.Import()
+	if err != nil {
+		return csvimporter.Provenance{}, microerror.MaskAny(err)
+	}
+
+	return p, nil
+}
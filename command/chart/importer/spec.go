@@ -0,0 +1,14 @@
+package importer
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Command represents the chart import command.
+type Command interface {
+	// CobraCommand returns the actual cobra command for the chart import
+	// command.
+	CobraCommand() *cobra.Command
+	// Execute represents the cobra run method.
+	Execute(cmd *cobra.Command, args []string)
+}
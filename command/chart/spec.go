@@ -0,0 +1,18 @@
+package chart
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/xh3b4sd/wafer/command/chart/importer"
+)
+
+// Command represents the chart command, which bundles the commands managing
+// the chart dirs consumed by the CSV informer.
+type Command interface {
+	// CobraCommand returns the actual cobra command for the chart command.
+	CobraCommand() *cobra.Command
+	// Execute represents the cobra run method.
+	Execute(cmd *cobra.Command, args []string)
+	// ImportCommand returns the import subcommand of the chart command.
+	ImportCommand() importer.Command
+}
@@ -0,0 +1,17 @@
+package analyzer
+
+import (
+	"github.com/xh3b4sd/wafer/flag/service/analyzer/buyer"
+	"github.com/xh3b4sd/wafer/flag/service/analyzer/seller"
+	"github.com/xh3b4sd/wafer/flag/service/analyzer/trace"
+)
+
+type Analyzer struct {
+	Buyer    buyer.Buyer
+	Features string
+	Seller   seller.Seller
+	Slice    string
+	Slices   string
+	Strategy string
+	Trace    trace.Trace
+}
@@ -0,0 +1,6 @@
+package bollinger
+
+type Bollinger struct {
+	K      string
+	Period string
+}
@@ -0,0 +1,11 @@
+package buyer
+
+import (
+	"github.com/xh3b4sd/wafer/flag/service/analyzer/buyer/bollinger"
+	"github.com/xh3b4sd/wafer/flag/service/analyzer/buyer/rsi"
+)
+
+type Buyer struct {
+	Bollinger bollinger.Bollinger
+	RSI       rsi.RSI
+}
@@ -0,0 +1,6 @@
+package rsi
+
+type RSI struct {
+	Max    string
+	Period string
+}
@@ -0,0 +1,9 @@
+package seller
+
+import (
+	"github.com/xh3b4sd/wafer/flag/service/analyzer/seller/rsi"
+)
+
+type Seller struct {
+	RSI rsi.RSI
+}
@@ -0,0 +1,9 @@
+package trace
+
+type Trace struct {
+	Check   string
+	Every   string
+	Export  string
+	Limit   string
+	Outcome string
+}
@@ -0,0 +1,6 @@
+package candle
+
+type Candle struct {
+	Enabled  string
+	Interval string
+}
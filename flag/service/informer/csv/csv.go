@@ -5,7 +5,10 @@ import (
 )
 
 type CSV struct {
-	Dir    string
-	File   string
-	Header header.Header
+	Cache      string
+	Dir        string
+	File       string
+	Header     header.Header
+	Watch      string
+	WatchDelay string
 }
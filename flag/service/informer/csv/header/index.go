@@ -1,8 +1,11 @@
 package header
 
 type Header struct {
-	Buy    string
-	Ignore string
-	Sell   string
-	Time   string
+	Buy        string
+	Ignore     string
+	Sell       string
+	Time       string
+	TimeFormat string
+	TimeZone   string
+	Volume     string
 }
@@ -1,10 +1,24 @@
 package informer
 
 import (
+	"github.com/xh3b4sd/wafer/flag/service/informer/candle"
 	"github.com/xh3b4sd/wafer/flag/service/informer/csv"
+	"github.com/xh3b4sd/wafer/flag/service/informer/jsonl"
+	"github.com/xh3b4sd/wafer/flag/service/informer/merge"
+	"github.com/xh3b4sd/wafer/flag/service/informer/poll"
+	"github.com/xh3b4sd/wafer/flag/service/informer/replay"
+	"github.com/xh3b4sd/wafer/flag/service/informer/stream"
+	"github.com/xh3b4sd/wafer/flag/service/informer/synthetic"
 )
 
 type Informer struct {
-	CSV  csv.CSV
-	Kind string
+	Candle    candle.Candle
+	CSV       csv.CSV
+	JSONL     jsonl.JSONL
+	Kind      string
+	Merge     merge.Merge
+	Poll      poll.Poll
+	Replay    replay.Replay
+	Stream    stream.Stream
+	Synthetic synthetic.Synthetic
 }
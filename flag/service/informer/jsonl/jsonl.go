@@ -0,0 +1,5 @@
+package jsonl
+
+type JSONL struct {
+	Dir string
+}
@@ -0,0 +1,6 @@
+package merge
+
+type Merge struct {
+	Enabled string
+	IDs     string
+}
@@ -0,0 +1,14 @@
+package poll
+
+type Poll struct {
+	Buy        string
+	Interval   string
+	MaxAge     string
+	Retries    string
+	Sell       string
+	Time       string
+	TimeFormat string
+	TimeZone   string
+	URL        string
+	Volume     string
+}
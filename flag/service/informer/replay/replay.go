@@ -0,0 +1,7 @@
+package replay
+
+type Replay struct {
+	Enabled string
+	Speed   string
+	Start   string
+}
@@ -0,0 +1,15 @@
+package stream
+
+type Stream struct {
+	Buy        string
+	Origin     string
+	Retries    string
+	Sell       string
+	Subscribe  string
+	Time       string
+	TimeFormat string
+	TimeZone   string
+	Timeout    string
+	URL        string
+	Volume     string
+}
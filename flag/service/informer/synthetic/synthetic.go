@@ -0,0 +1,13 @@
+package synthetic
+
+type Synthetic struct {
+	Charts     string
+	Drift      string
+	Interval   string
+	Length     string
+	Model      string
+	Price      string
+	Seed       string
+	Spread     string
+	Volatility string
+}
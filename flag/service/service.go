@@ -1,9 +1,11 @@
 package service
 
 import (
+	"github.com/xh3b4sd/wafer/flag/service/analyzer"
 	"github.com/xh3b4sd/wafer/flag/service/informer"
 )
 
 type Service struct {
+	Analyzer analyzer.Analyzer
 	Informer informer.Informer
 }
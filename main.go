@@ -2,12 +2,14 @@ package main
 
 import (
 	"os"
+	"time"
 
 	"github.com/giantswarm/microkit/command"
 	"github.com/giantswarm/microkit/logger"
 	microserver "github.com/giantswarm/microkit/server"
 	"github.com/spf13/viper"
 
+	"github.com/xh3b4sd/wafer/command/chart"
 	"github.com/xh3b4sd/wafer/flag"
 	"github.com/xh3b4sd/wafer/server"
 	"github.com/xh3b4sd/wafer/service"
@@ -96,15 +98,90 @@ func main() {
 		}
 	}
 
+	// Create a new chart command which manages the chart dirs consumed by the
+	// CSV informer.
+	var newChartCommand chart.Command
+	{
+		chartConfig := chart.DefaultConfig()
+
+		chartConfig.GitCommit = gitCommit
+
+		newChartCommand, err = chart.New(chartConfig)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	newCommand.CobraCommand().AddCommand(newChartCommand.CobraCommand())
+
 	daemonCommand := newCommand.DaemonCommand().CobraCommand()
 
+	daemonCommand.PersistentFlags().Float64(f.Service.Analyzer.Buyer.Bollinger.K, 2, "The number of standard deviations the Bollinger bands the buyer reads from are away from their middle band.")
+	daemonCommand.PersistentFlags().Int(f.Service.Analyzer.Buyer.Bollinger.Period, 0, "The number of price events the Bollinger bands the buyer reads from cover. 0 to not block buy events above the upper band.")
+	daemonCommand.PersistentFlags().Float64(f.Service.Analyzer.Buyer.RSI.Max, 70, "The maximum RSI allowed for buy events to happen.")
+	daemonCommand.PersistentFlags().Int(f.Service.Analyzer.Buyer.RSI.Period, 0, "The number of price events the RSI the buyer reads from covers. 0 to not block buy events above the maximum RSI.")
+	daemonCommand.PersistentFlags().String(f.Service.Analyzer.Features, "", "The comma separated list of optional buyer features whose parameters the analyzer permutes. Any of percentile or surge. Empty to analyze the buyer without these features.")
+	daemonCommand.PersistentFlags().Float64(f.Service.Analyzer.Seller.RSI.Min, 60, "The minimum RSI required for sell events to happen.")
+	daemonCommand.PersistentFlags().Int(f.Service.Analyzer.Seller.RSI.Period, 0, "The number of price events the RSI the seller reads from covers. 0 to not block sell events below the minimum RSI.")
+	daemonCommand.PersistentFlags().String(f.Service.Analyzer.Slice, "", "The name of the slice the analyzer restricts charts to. Empty to analyze full charts.")
+	daemonCommand.PersistentFlags().String(f.Service.Analyzer.Slices, "", "The comma separated list of named slices, e.g. train=0..0.7,test=0.7..1 or 2016=2016-01-01T00:00:00Z..2017-01-01T00:00:00Z.")
+	daemonCommand.PersistentFlags().String(f.Service.Analyzer.Strategy, "", "The path to a YAML or JSON strategy document declaring the strategies the analyzer permutes. Empty to analyze the default rules.")
+	daemonCommand.PersistentFlags().String(f.Service.Analyzer.Trace.Check, "", "The name of the check function decisions are only recorded for in case it blocked. Empty to record decisions regardless of check functions.")
+	daemonCommand.PersistentFlags().Int(f.Service.Analyzer.Trace.Every, 0, "The sample rate of recorded buy and sell decisions, e.g. 10 to record every 10th decision. 0 to not record decisions.")
+	daemonCommand.PersistentFlags().String(f.Service.Analyzer.Trace.Export, "", "The file path the decision records of the latest permutation are exported to as JSON lines. Empty to not export decision records.")
+	daemonCommand.PersistentFlags().Int(f.Service.Analyzer.Trace.Limit, 1000, "The maximum number of decision records kept for the latest permutation.")
+	daemonCommand.PersistentFlags().String(f.Service.Analyzer.Trace.Outcome, "", "The outcome decisions are only recorded for, either blocked or fired. Empty to record decisions regardless of their outcome.")
+	daemonCommand.PersistentFlags().Bool(f.Service.Informer.Candle.Enabled, false, "Whether to resample the price events of each chart into candles of a fixed interval.")
+	daemonCommand.PersistentFlags().Duration(f.Service.Informer.Candle.Interval, time.Minute, "The duration each candle covers, e.g. 1m, 5m or 1h.")
+	daemonCommand.PersistentFlags().Bool(f.Service.Informer.CSV.Cache, true, "Whether to maintain a binary cache beside each CSV file to speed up loading charts repeatedly.")
 	daemonCommand.PersistentFlags().String(f.Service.Informer.CSV.Dir, "", "The absolute dir path of CSV files containing chart data and their corresponding header options.")
 	daemonCommand.PersistentFlags().String(f.Service.Informer.CSV.File, "", "The absolute file path of a CSV file containing chart data.")
-	daemonCommand.PersistentFlags().Int(f.Service.Informer.CSV.Header.Buy, 0, "The index of the column within a CSV file representing buy prices.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.CSV.Header.Buy, "0", "The index or name of the column within a CSV file representing buy prices.")
 	daemonCommand.PersistentFlags().Bool(f.Service.Informer.CSV.Header.Ignore, false, "Whether to ignore the first row of the CSV file.")
-	daemonCommand.PersistentFlags().Int(f.Service.Informer.CSV.Header.Sell, 0, "The index of the column within a CSV file representing sell prices.")
-	daemonCommand.PersistentFlags().Int(f.Service.Informer.CSV.Header.Time, 0, "The index of the column within a CSV file representing price times.")
-	daemonCommand.PersistentFlags().String(f.Service.Informer.Kind, "csv", "The kind of the informer imlementation to use.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.CSV.Header.Sell, "0", "The index or name of the column within a CSV file representing sell prices.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.CSV.Header.Time, "0", "The index or name of the column within a CSV file representing price times.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.CSV.Header.TimeFormat, "unix", "The format of price times within a CSV file. One of unix, unixmilli, unixnano, rfc3339 or a Go time layout.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.CSV.Header.TimeZone, "", "The name of the time zone price times within a CSV file are interpreted in, e.g. UTC.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.CSV.Header.Volume, "", "The index or name of the column within a CSV file representing traded volumes. Empty in case there are no traded volumes.")
+	daemonCommand.PersistentFlags().Bool(f.Service.Informer.CSV.Watch, false, "Whether to watch the CSV dir and reload its charts as soon as they change.")
+	daemonCommand.PersistentFlags().Duration(f.Service.Informer.CSV.WatchDelay, time.Second, "The duration to wait for further changes of the watched CSV dir before its charts are reloaded.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.JSONL.Dir, "", "The absolute dir path of JSON Lines files containing chart data and their corresponding mapping options.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Kind, "csv", "The kind of the informer imlementation to use. One of csv, jsonl, poll, stream or synthetic. The endless kinds poll and stream are rejected, because the analyzer reads all charts to their end.")
+	daemonCommand.PersistentFlags().Bool(f.Service.Informer.Merge.Enabled, false, "Whether to merge all charts into a single time ordered stream of price events.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Merge.IDs, "", "The comma separated list of chart IDs merged price events are tagged with. Empty to identify charts by their index.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Poll.Buy, "", "The JSON path of the value representing buy prices within responses of the ticker endpoint.")
+	daemonCommand.PersistentFlags().Duration(f.Service.Informer.Poll.Interval, 10*time.Second, "The duration between two consecutive polls of the ticker endpoint.")
+	daemonCommand.PersistentFlags().Duration(f.Service.Informer.Poll.MaxAge, 0, "The maximum age of ticks at the time they are received. Older ticks are dropped. 0 disables the detection of stale ticks.")
+	daemonCommand.PersistentFlags().Int(f.Service.Informer.Poll.Retries, 5, "The number of retries of a failed poll of the ticker endpoint.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Poll.Sell, "", "The JSON path of the value representing sell prices within responses of the ticker endpoint.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Poll.Time, "", "The JSON path of the value representing price times within responses of the ticker endpoint.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Poll.TimeFormat, "unix", "The format of price times within responses of the ticker endpoint. One of unix, unixmilli, unixnano, rfc3339 or a Go time layout.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Poll.TimeZone, "", "The name of the time zone price times within responses of the ticker endpoint are interpreted in, e.g. UTC.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Poll.URL, "", "The absolute HTTP or HTTPS address of the ticker endpoint to poll.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Poll.Volume, "", "The JSON path of the value representing traded volumes within responses of the ticker endpoint. Empty in case there are no traded volumes.")
+	daemonCommand.PersistentFlags().Bool(f.Service.Informer.Replay.Enabled, false, "Whether to replay price events at wall clock pace.")
+	daemonCommand.PersistentFlags().Float64(f.Service.Informer.Replay.Speed, 1, "The factor by which the replay is faster than the original timing of price events. 0 replays as fast as possible.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Replay.Start, "", "The RFC3339 time the replay starts at. Empty to start at the first price event of each chart.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Stream.Buy, "", "The JSON path of the value representing buy prices within messages of the WebSocket endpoint.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Stream.Origin, "http://localhost/", "The origin sent with the handshake of the WebSocket endpoint.")
+	daemonCommand.PersistentFlags().Int(f.Service.Informer.Stream.Retries, 10, "The number of consecutive reconnects to the WebSocket endpoint tolerated without receiving a message in between.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Stream.Sell, "", "The JSON path of the value representing sell prices within messages of the WebSocket endpoint.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Stream.Subscribe, "", "The message sent to the WebSocket endpoint after each connect, e.g. to subscribe to a ticker channel.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Stream.Time, "", "The JSON path of the value representing price times within messages of the WebSocket endpoint.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Stream.TimeFormat, "unix", "The format of price times within messages of the WebSocket endpoint. One of unix, unixmilli, unixnano, rfc3339 or a Go time layout.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Stream.TimeZone, "", "The name of the time zone price times within messages of the WebSocket endpoint are interpreted in, e.g. UTC.")
+	daemonCommand.PersistentFlags().Duration(f.Service.Informer.Stream.Timeout, time.Minute, "The maximum duration to wait for a connect to or a message of the WebSocket endpoint.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Stream.URL, "", "The absolute WS or WSS address of the WebSocket endpoint to subscribe to.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Stream.Volume, "", "The JSON path of the value representing traded volumes within messages of the WebSocket endpoint. Empty in case there are no traded volumes.")
+	daemonCommand.PersistentFlags().Int(f.Service.Informer.Synthetic.Charts, 1, "The number of synthetic charts to generate.")
+	daemonCommand.PersistentFlags().Float64(f.Service.Informer.Synthetic.Drift, 0, "The expected change of the logarithmic buy price per tick of synthetic charts.")
+	daemonCommand.PersistentFlags().Duration(f.Service.Informer.Synthetic.Interval, time.Minute, "The duration between two consecutive price events of synthetic charts.")
+	daemonCommand.PersistentFlags().Int(f.Service.Informer.Synthetic.Length, 1000, "The number of price events of each synthetic chart.")
+	daemonCommand.PersistentFlags().String(f.Service.Informer.Synthetic.Model, "gbm", "The model used to generate synthetic charts. One of gbm, mean-reverting or regime-switching.")
+	daemonCommand.PersistentFlags().Float64(f.Service.Informer.Synthetic.Price, 100, "The buy price of the first price event of synthetic charts.")
+	daemonCommand.PersistentFlags().Int64(f.Service.Informer.Synthetic.Seed, 1, "The seed used to generate synthetic charts.")
+	daemonCommand.PersistentFlags().Float64(f.Service.Informer.Synthetic.Spread, 0.1, "The distance of the sell price below the buy price of synthetic charts, in percent.")
+	daemonCommand.PersistentFlags().Float64(f.Service.Informer.Synthetic.Volatility, 0.001, "The standard deviation of the logarithmic buy price change per tick of synthetic charts.")
 
 	newCommand.CobraCommand().Execute()
 }
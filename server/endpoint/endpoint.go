@@ -6,6 +6,7 @@ import (
 
 	"github.com/xh3b4sd/wafer/server/endpoint/analyze"
 	"github.com/xh3b4sd/wafer/server/endpoint/render"
+	"github.com/xh3b4sd/wafer/server/endpoint/replay"
 	"github.com/xh3b4sd/wafer/server/endpoint/version"
 	"github.com/xh3b4sd/wafer/server/middleware"
 	"github.com/xh3b4sd/wafer/service"
@@ -58,6 +59,18 @@ func New(config Config) (*Endpoint, error) {
 		}
 	}
 
+	var replayEndpoint *replay.Endpoint
+	{
+		replayConfig := replay.DefaultConfig()
+		replayConfig.Logger = config.Logger
+		replayConfig.Middleware = config.Middleware
+		replayConfig.Service = config.Service
+		replayEndpoint, err = replay.New(replayConfig)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
 	var versionEndpoint *version.Endpoint
 	{
 		versionConfig := version.DefaultConfig()
@@ -73,6 +86,7 @@ func New(config Config) (*Endpoint, error) {
 	newEndpoint := &Endpoint{
 		Analyze: analyzeEndpoint,
 		Render:  renderEndpoint,
+		Replay:  replayEndpoint,
 		Version: versionEndpoint,
 	}
 
@@ -83,5 +97,6 @@ func New(config Config) (*Endpoint, error) {
 type Endpoint struct {
 	Analyze *analyze.Endpoint
 	Render  *render.Endpoint
+	Replay  *replay.Endpoint
 	Version *version.Endpoint
 }
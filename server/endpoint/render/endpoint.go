@@ -20,6 +20,7 @@ import (
 	analyzerclient "github.com/xh3b4sd/wafer/service/client/analyzer"
 	"github.com/xh3b4sd/wafer/service/informer"
 	"github.com/xh3b4sd/wafer/service/informer/csv"
+	"github.com/xh3b4sd/wafer/service/informer/csv/column"
 	"github.com/xh3b4sd/wafer/service/seller"
 	v1seller "github.com/xh3b4sd/wafer/service/seller/v1"
 	"github.com/xh3b4sd/wafer/service/trader"
@@ -115,10 +116,10 @@ func (e *Endpoint) Endpoint() kitendpoint.Endpoint {
 		var newInformer informer.Informer
 		{
 			config := csv.DefaultConfig()
-			config.File.Header.Buy = 9
+			config.File.Header.Buy = column.Index(9)
 			config.File.Header.Ignore = true
-			config.File.Header.Sell = 10
-			config.File.Header.Time = 12
+			config.File.Header.Sell = column.Index(10)
+			config.File.Header.Time = column.Index(12)
 			config.File.Path = "/Users/xh3b4sd/Downloads/test.csv"
 			newInformer, err = csv.New(config)
 			if err != nil {
@@ -197,7 +198,7 @@ func (e *Endpoint) Endpoint() kitendpoint.Endpoint {
 		//		}()
 
 		e.logger.Log("debug", "trader started")
-		err = newTrader.Execute()
+		err = newTrader.Execute(ctx)
 		if err != nil {
 			return nil, microerror.MaskAny(err)
 		}
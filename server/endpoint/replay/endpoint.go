@@ -0,0 +1,72 @@
+package replay
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+	micrologger "github.com/giantswarm/microkit/logger"
+
+	"github.com/xh3b4sd/wafer/server/endpoint/replay/search"
+	"github.com/xh3b4sd/wafer/server/endpoint/replay/update"
+	"github.com/xh3b4sd/wafer/server/middleware"
+	"github.com/xh3b4sd/wafer/service"
+)
+
+// Config represents the configuration used to create an endpoint.
+type Config struct {
+	// Dependencies.
+	Logger     micrologger.Logger
+	Middleware *middleware.Middleware
+	Service    *service.Service
+}
+
+// DefaultConfig provides a default configuration to create a new endpoint by
+// best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Dependencies.
+		Logger:     nil,
+		Middleware: nil,
+		Service:    nil,
+	}
+}
+
+// New creates a new configured endpoint.
+func New(config Config) (*Endpoint, error) {
+	var err error
+
+	var searchEndpoint *search.Endpoint
+	{
+		searchConfig := search.DefaultConfig()
+		searchConfig.Logger = config.Logger
+		searchConfig.Middleware = config.Middleware
+		searchConfig.Service = config.Service
+		searchEndpoint, err = search.New(searchConfig)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	var updateEndpoint *update.Endpoint
+	{
+		updateConfig := update.DefaultConfig()
+		updateConfig.Logger = config.Logger
+		updateConfig.Middleware = config.Middleware
+		updateConfig.Service = config.Service
+		updateEndpoint, err = update.New(updateConfig)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	newEndpoint := &Endpoint{
+		Search: searchEndpoint,
+		Update: updateEndpoint,
+	}
+
+	return newEndpoint, nil
+}
+
+// Endpoint is the endpoint collection.
+type Endpoint struct {
+	Search *search.Endpoint
+	Update *update.Endpoint
+}
@@ -0,0 +1,19 @@
+package search
+
+import (
+	"github.com/juju/errgo"
+)
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var notEnabledError = errgo.New("not enabled")
+
+// IsNotEnabled asserts notEnabledError.
+func IsNotEnabled(err error) bool {
+	return errgo.Cause(err) == notEnabledError
+}
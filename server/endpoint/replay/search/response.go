@@ -0,0 +1,9 @@
+package search
+
+import (
+	"github.com/xh3b4sd/wafer/server/endpoint/replay/search/response"
+)
+
+type Response struct {
+	Replay response.Replay `json:"replay"`
+}
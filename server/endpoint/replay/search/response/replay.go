@@ -0,0 +1,9 @@
+package response
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer/replay"
+)
+
+type Replay struct {
+	State replay.State `json:"state"`
+}
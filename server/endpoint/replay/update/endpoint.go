@@ -0,0 +1,148 @@
+package update
+
+import (
+	"encoding/json"
+	"net/http"
+
+	microerror "github.com/giantswarm/microkit/error"
+	micrologger "github.com/giantswarm/microkit/logger"
+	microserver "github.com/giantswarm/microkit/server"
+	kitendpoint "github.com/go-kit/kit/endpoint"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/server/middleware"
+	"github.com/xh3b4sd/wafer/service"
+)
+
+const (
+	// Method is the HTTP method this endpoint is registered for.
+	Method = "PATCH"
+	// Name identifies the endpoint. It is aligned to the package path.
+	Name = "replay/update"
+	// Path is the HTTP request path this endpoint is registered for.
+	Path = "/v1/replay/"
+)
+
+// Config represents the configuration used to create an endpoint.
+type Config struct {
+	// Dependencies.
+	Logger     micrologger.Logger
+	Middleware *middleware.Middleware
+	Service    *service.Service
+}
+
+// DefaultConfig provides a default configuration to create a new endpoint by
+// best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Dependencies.
+		Logger:     nil,
+		Middleware: nil,
+		Service:    nil,
+	}
+}
+
+// New creates a new configured version endpoint.
+func New(config Config) (*Endpoint, error) {
+	// Dependencies.
+	if config.Logger == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Logger must not be empty")
+	}
+	if config.Middleware == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Middleware must not be empty")
+	}
+	if config.Service == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Service must not be empty")
+	}
+
+	newEndpoint := &Endpoint{
+		// Dependencies.
+		logger:     config.Logger,
+		middleware: config.Middleware,
+		service:    config.Service,
+	}
+
+	return newEndpoint, nil
+}
+
+type Endpoint struct {
+	// Dependencies.
+	logger     micrologger.Logger
+	middleware *middleware.Middleware
+	service    *service.Service
+}
+
+func (e *Endpoint) Decoder() kithttp.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		request := DefaultRequest()
+
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidRequestError, err.Error())
+		}
+
+		return request, nil
+	}
+}
+
+func (e *Endpoint) Encoder() kithttp.EncodeResponseFunc {
+	return func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+		w.Header().Set(http.CanonicalHeaderKey("Content-Type"), "application/json; charset=utf-8")
+
+		w.WriteHeader(http.StatusOK)
+
+		err := json.NewEncoder(w).Encode(response)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+
+		return nil
+	}
+}
+
+func (e *Endpoint) Endpoint() kitendpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		if e.service.Replay == nil {
+			return nil, microerror.MaskAnyf(notEnabledError, "replay must be enabled using --service.informer.replay.enabled")
+		}
+
+		endpointRequest := request.(Request)
+
+		// Seeking is applied before pausing or resuming. That way a replay can be
+		// moved and resumed with a single request.
+		if endpointRequest.Position != nil {
+			e.service.Replay.Seek(*endpointRequest.Position)
+		}
+		if endpointRequest.Paused != nil {
+			if *endpointRequest.Paused {
+				e.service.Replay.Pause()
+			} else {
+				e.service.Replay.Resume()
+			}
+		}
+
+		response := Response{}
+		response.Body.Code = microserver.CodeResourceUpdated
+		response.Body.Message = "The replay has been updated."
+		response.Replay.State = e.service.Replay.State()
+
+		return response, nil
+	}
+}
+
+func (e *Endpoint) Method() string {
+	return Method
+}
+
+func (e *Endpoint) Middlewares() []kitendpoint.Middleware {
+	return []kitendpoint.Middleware{}
+}
+
+func (e *Endpoint) Name() string {
+	return Name
+}
+
+func (e *Endpoint) Path() string {
+	return Path
+}
@@ -0,0 +1,26 @@
+package update
+
+import (
+	"github.com/juju/errgo"
+)
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var notEnabledError = errgo.New("not enabled")
+
+// IsNotEnabled asserts notEnabledError.
+func IsNotEnabled(err error) bool {
+	return errgo.Cause(err) == notEnabledError
+}
+
+var invalidRequestError = errgo.New("invalid request")
+
+// IsInvalidRequest asserts invalidRequestError.
+func IsInvalidRequest(err error) bool {
+	return errgo.Cause(err) == invalidRequestError
+}
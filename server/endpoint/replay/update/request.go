@@ -0,0 +1,20 @@
+package update
+
+import (
+	"time"
+)
+
+// Request is the configuration for the endpoint. Fields not given in the
+// request body leave the corresponding replay state untouched.
+type Request struct {
+	// Paused pauses the replay in case it is true and resumes the replay in
+	// case it is false.
+	Paused *bool `json:"paused"`
+	// Position moves the replay to the given time.
+	Position *time.Time `json:"position"`
+}
+
+// DefaultRequest provides a default request object by best effort.
+func DefaultRequest() Request {
+	return Request{}
+}
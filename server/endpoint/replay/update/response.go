@@ -0,0 +1,10 @@
+package update
+
+import (
+	"github.com/xh3b4sd/wafer/server/endpoint/replay/update/response"
+)
+
+type Response struct {
+	Body   response.Body   `json:"body"`
+	Replay response.Replay `json:"replay"`
+}
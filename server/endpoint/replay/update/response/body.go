@@ -0,0 +1,6 @@
+package response
+
+type Body struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
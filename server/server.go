@@ -80,6 +80,8 @@ func New(config Config) (microserver.Server, error) {
 		endpointCollection.Analyze.Create,
 		endpointCollection.Analyze.Search,
 		endpointCollection.Render,
+		endpointCollection.Replay.Search,
+		endpointCollection.Replay.Update,
 		endpointCollection.Version,
 	}
 	newServer.config.ErrorEncoder = newServer.newErrorEncoder()
@@ -9,21 +9,52 @@ import (
 	buyerconfig "github.com/xh3b4sd/wafer/service/buyer/runtime/config"
 	permutationconfig "github.com/xh3b4sd/wafer/service/permutation/runtime/config"
 	sellerconfig "github.com/xh3b4sd/wafer/service/seller/runtime/config"
+	"github.com/xh3b4sd/wafer/service/strategy"
 )
 
 const (
 	// Buyer.
-	PermIDBuyerTradeCorridorMax = "Buyer.Trade.Corridor.Max"
-	PermIDBuyerTradePauseMin    = "Buyer.Trade.Pause.Min"
+	PermIDBuyerTradeCorridorMax           = "Buyer.Trade.Corridor.Max"
+	PermIDBuyerTradeCorridorPercentileMax = "Buyer.Trade.Corridor.Percentile.Max"
+	PermIDBuyerTradeCorridorPercentileMin = "Buyer.Trade.Corridor.Percentile.Min"
+	PermIDBuyerTradeCorridorWindow        = "Buyer.Trade.Corridor.Window"
+	PermIDBuyerTradePauseMin              = "Buyer.Trade.Pause.Min"
+	PermIDBuyerTradeSurgeHigherHighs      = "Buyer.Trade.Surge.HigherHighs"
+	PermIDBuyerTradeSurgeMin              = "Buyer.Trade.Surge.Min"
+	PermIDBuyerTradeSurgeVolume           = "Buyer.Trade.Surge.Volume"
+	PermIDBuyerTradeSurgeWindow           = "Buyer.Trade.Surge.Window"
 
 	// Seller.
 	PermIDSellerTradeDurationMin = "Seller.Trade.Duration.Min"
 	PermIDSellerTradeRevenueMin  = "Seller.Trade.Revenue.Min"
+
+	// Strategy.
+	PermIDStrategy = "Strategy"
+)
+
+const (
+	// FeaturePercentile enables the permutation of the percentile corridor of
+	// the buyer and the window it is calculated from.
+	FeaturePercentile = "percentile"
+	// FeatureSurge enables the permutation of the surge the buyer requires buy
+	// events to ride.
+	FeatureSurge = "surge"
 )
 
 type Config struct {
-	Buyer  buyerconfig.Config  `json:"buyer"`
-	Seller sellerconfig.Config `json:"seller"`
+	Buyer buyerconfig.Config `json:"buyer"`
+	// Features are the optional features of the buyer whose parameters are
+	// permuted. The parameters of features not listed are not permuted, so
+	// these features stay disabled. See the Feature constants.
+	Features []string            `json:"features,omitempty"`
+	Seller   sellerconfig.Config `json:"seller"`
+	// Strategy is the name of the strategy the rules of the buyer and the
+	// seller are taken from. Strategy is empty in case no strategies are
+	// analyzed.
+	Strategy string `json:"strategy,omitempty"`
+	// Strategies are the strategies being permuted. The configuration history
+	// only records the strategy it was analyzed with.
+	Strategies []strategy.Strategy `json:"-"`
 }
 
 func (c *Config) GetPermConfigs() []permutationconfig.Config {
@@ -42,6 +73,31 @@ func (c *Config) GetPermConfigs() []permutationconfig.Config {
 	config.Step = 0.2
 	configs = append(configs, config)
 
+	// The percentile corridor is only permuted in case it is enabled, so that
+	// the buyer is analyzed without it by default.
+	if c.hasFeature(FeaturePercentile) {
+		config = permutationconfig.Config{}
+		config.ID = PermIDBuyerTradeCorridorPercentileMax
+		config.Min = 80.0
+		config.Max = 100.0
+		config.Step = 20.0
+		configs = append(configs, config)
+
+		config = permutationconfig.Config{}
+		config.ID = PermIDBuyerTradeCorridorPercentileMin
+		config.Min = 0.0
+		config.Max = 10.0
+		config.Step = 10.0
+		configs = append(configs, config)
+
+		config = permutationconfig.Config{}
+		config.ID = PermIDBuyerTradeCorridorWindow
+		config.Min = 24 * time.Hour
+		config.Max = 7 * 24 * time.Hour
+		config.Step = 6 * 24 * time.Hour
+		configs = append(configs, config)
+	}
+
 	//
 	config = permutationconfig.Config{}
 	config.ID = PermIDBuyerTradePauseMin
@@ -50,6 +106,38 @@ func (c *Config) GetPermConfigs() []permutationconfig.Config {
 	config.Step = 15 * time.Minute
 	configs = append(configs, config)
 
+	// The surge is only permuted in case it is enabled, so that the buyer is
+	// analyzed without it by default.
+	if c.hasFeature(FeatureSurge) {
+		config = permutationconfig.Config{}
+		config.ID = PermIDBuyerTradeSurgeHigherHighs
+		config.Min = 0.0
+		config.Max = 2.0
+		config.Step = 2.0
+		configs = append(configs, config)
+
+		config = permutationconfig.Config{}
+		config.ID = PermIDBuyerTradeSurgeMin
+		config.Min = 1.0
+		config.Max = 2.0
+		config.Step = 1.0
+		configs = append(configs, config)
+
+		config = permutationconfig.Config{}
+		config.ID = PermIDBuyerTradeSurgeVolume
+		config.Min = 0.0
+		config.Max = 1.5
+		config.Step = 1.5
+		configs = append(configs, config)
+
+		config = permutationconfig.Config{}
+		config.ID = PermIDBuyerTradeSurgeWindow
+		config.Min = 30 * time.Minute
+		config.Max = time.Hour
+		config.Step = 30 * time.Minute
+		configs = append(configs, config)
+	}
+
 	//
 	// Seller.
 	//
@@ -70,6 +158,20 @@ func (c *Config) GetPermConfigs() []permutationconfig.Config {
 	config.Step = 0.2
 	configs = append(configs, config)
 
+	//
+	// Strategy.
+	//
+
+	// A single strategy is not permuted. It is set once the analyzer is created.
+	if len(c.Strategies) > 1 {
+		config = permutationconfig.Config{}
+		config.ID = PermIDStrategy
+		config.Min = 0.0
+		config.Max = float64(len(c.Strategies) - 1)
+		config.Step = 1.0
+		configs = append(configs, config)
+	}
+
 	return configs
 }
 
@@ -84,12 +186,54 @@ func (c *Config) SetPermValue(permID string, permValue interface{}) error {
 			return microerror.MaskAny(err)
 		}
 		c.Buyer.Trade.Corridor.Max = f
+	case PermIDBuyerTradeCorridorPercentileMax:
+		f, err := cast.ToFloat64E(permValue)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		c.Buyer.Trade.Corridor.Percentile.Max = f
+	case PermIDBuyerTradeCorridorPercentileMin:
+		f, err := cast.ToFloat64E(permValue)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		c.Buyer.Trade.Corridor.Percentile.Min = f
+	case PermIDBuyerTradeCorridorWindow:
+		d, err := cast.ToDurationE(permValue)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		c.Buyer.Trade.Corridor.Window = d
 	case PermIDBuyerTradePauseMin:
 		d, err := cast.ToDurationE(permValue)
 		if err != nil {
 			return microerror.MaskAny(err)
 		}
 		c.Buyer.Trade.Pause.Min = d
+	case PermIDBuyerTradeSurgeHigherHighs:
+		i, err := cast.ToIntE(permValue)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		c.Buyer.Trade.Surge.HigherHighs = i
+	case PermIDBuyerTradeSurgeMin:
+		f, err := cast.ToFloat64E(permValue)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		c.Buyer.Trade.Surge.Min = f
+	case PermIDBuyerTradeSurgeVolume:
+		f, err := cast.ToFloat64E(permValue)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		c.Buyer.Trade.Surge.Volume = f
+	case PermIDBuyerTradeSurgeWindow:
+		d, err := cast.ToDurationE(permValue)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		c.Buyer.Trade.Surge.Window = d
 	//
 	// Seller.
 	//
@@ -105,6 +249,21 @@ func (c *Config) SetPermValue(permID string, permValue interface{}) error {
 			return microerror.MaskAny(err)
 		}
 		c.Seller.Trade.Revenue.Min = f
+	//
+	// Strategy.
+	//
+	case PermIDStrategy:
+		i, err := cast.ToIntE(permValue)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		if i < 0 || i >= len(c.Strategies) {
+			return microerror.MaskAnyf(invalidExecutionError, "strategy index %d out of range", i)
+		}
+		s := c.Strategies[i]
+		c.Strategy = s.Name
+		c.Buyer.Block = s.Buyer
+		c.Seller.Block = s.Seller
 	default:
 		return microerror.MaskAnyf(invalidExecutionError, "unknown permID '%s'", permID)
 	}
@@ -126,3 +285,13 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+func (c *Config) hasFeature(name string) bool {
+	for _, f := range c.Features {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	v1permutation "github.com/xh3b4sd/wafer/service/permutation/v1"
+)
+
+// Test_Config_GetPermConfigs_Features makes sure the parameters of optional
+// features are only permuted in case the features are enabled.
+func Test_Config_GetPermConfigs_Features(t *testing.T) {
+	testCases := []struct {
+		Features []string
+		Expected float64
+	}{
+		// Test case 1, the baseline is analyzed without optional features.
+		{
+			Features: nil,
+			Expected: 7200,
+		},
+		// Test case 2, the percentile corridor doubles the permutations.
+		{
+			Features: []string{FeaturePercentile},
+			Expected: 7200 * 2,
+		},
+		// Test case 3, the surge quadruples the permutations.
+		{
+			Features: []string{FeatureSurge},
+			Expected: 7200 * 4,
+		},
+	}
+
+	for i, testCase := range testCases {
+		c := &Config{Features: testCase.Features}
+		total := v1permutation.TotalFromMax(v1permutation.MaxFromConfigs(c.GetPermConfigs()))
+		if total != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", total)
+		}
+	}
+}
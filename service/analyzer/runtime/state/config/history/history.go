@@ -5,8 +5,11 @@ import (
 )
 
 type History struct {
-	Config   config.Config `json:"config"`
-	Cycles   []int64       `json:"cycles"`
-	Indizes  []int         `json:"indizes"`
-	Revenues []float64     `json:"revenues"`
+	Config config.Config `json:"config"`
+	Cycles []int64       `json:"cycles"`
+	// Generation is the reload generation of the charts the config was
+	// analyzed on.
+	Generation int       `json:"generation"`
+	Indizes    []int     `json:"indizes"`
+	Revenues   []float64 `json:"revenues"`
 }
@@ -2,8 +2,15 @@ package informer
 
 import (
 	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+	"github.com/xh3b4sd/wafer/service/informer/slice"
 )
 
 type Informer struct {
-	Prices []price.Price `json:"prices"`
+	// Generation is the reload generation of the charts analyzed most recently.
+	// It only changes during analysis in case the informer reloads its charts.
+	Generation int           `json:"generation"`
+	Prices     []price.Price `json:"prices"`
+	// Slice is the slice the charts are restricted to during analysis. Slice is
+	// empty in case full charts are analyzed.
+	Slice slice.Slice `json:"slice"`
 }
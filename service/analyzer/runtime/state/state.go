@@ -4,10 +4,14 @@ import (
 	"github.com/xh3b4sd/wafer/service/analyzer/runtime/state/config"
 	"github.com/xh3b4sd/wafer/service/analyzer/runtime/state/informer"
 	"github.com/xh3b4sd/wafer/service/analyzer/runtime/state/permutation"
+	"github.com/xh3b4sd/wafer/service/trace"
 )
 
 type State struct {
 	Config      config.Config           `json:"config"`
 	Informer    informer.Informer       `json:"informer"`
 	Permutation permutation.Permutation `json:"permutation"`
+	// Trace are the decision records of the latest permutation being analyzed.
+	// Trace is only set in case tracing is enabled.
+	Trace []trace.Record `json:"trace,omitempty"`
 }
@@ -2,12 +2,14 @@ package v1
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"sync"
 	"time"
 
 	microerror "github.com/giantswarm/microkit/error"
 	micrologger "github.com/giantswarm/microkit/logger"
+	"golang.org/x/net/context"
 
 	"github.com/xh3b4sd/wafer/service/analyzer"
 	"github.com/xh3b4sd/wafer/service/analyzer/runtime"
@@ -15,14 +17,19 @@ import (
 	runtimestate "github.com/xh3b4sd/wafer/service/analyzer/runtime/state"
 	statehistory "github.com/xh3b4sd/wafer/service/analyzer/runtime/state/config/history"
 	"github.com/xh3b4sd/wafer/service/buyer"
+	buyerconfig "github.com/xh3b4sd/wafer/service/buyer/runtime/config"
 	v1buyer "github.com/xh3b4sd/wafer/service/buyer/v1"
 	"github.com/xh3b4sd/wafer/service/client"
 	analyzerclient "github.com/xh3b4sd/wafer/service/client/analyzer"
 	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/slice"
 	"github.com/xh3b4sd/wafer/service/permutation"
 	v1permutation "github.com/xh3b4sd/wafer/service/permutation/v1"
 	"github.com/xh3b4sd/wafer/service/seller"
+	sellerconfig "github.com/xh3b4sd/wafer/service/seller/runtime/config"
 	v1seller "github.com/xh3b4sd/wafer/service/seller/v1"
+	"github.com/xh3b4sd/wafer/service/strategy"
+	"github.com/xh3b4sd/wafer/service/trace"
 	"github.com/xh3b4sd/wafer/service/trader"
 	v1trader "github.com/xh3b4sd/wafer/service/trader/v1"
 )
@@ -32,6 +39,40 @@ type Config struct {
 	// Dependencies.
 	Informer informer.Informer
 	Logger   micrologger.Logger
+
+	// Settings.
+
+	// Buyer is the configuration the buyer of each permutation starts from. The
+	// permuted parameters and the params of the strategy are applied on top of
+	// it, e.g. to enable the check functions reading from indicators.
+	Buyer buyerconfig.Config
+	// Features are the optional features of the buyer whose parameters are
+	// permuted, e.g. runtimeconfig.FeatureSurge. In case Features is empty,
+	// these features are not analyzed.
+	Features []string
+	// Seller is the configuration the seller of each permutation starts from.
+	// See Buyer.
+	Seller sellerconfig.Config
+	// Slice is the name of the slice each chart of the informer is restricted to
+	// during analysis. Slice must reference one of Slices. In case Slice is
+	// empty, full charts are analyzed.
+	Slice string
+	// Slices is the list of named slices Slice can reference, e.g. a train and
+	// a test split of the same charts.
+	Slices []slice.Slice
+	// Strategies are the strategies composing the check functions of the buyer
+	// and the seller. Strategies are permuted together with the parameters of
+	// the check functions. In case Strategies is empty, the default rules of
+	// the buyer and the seller are analyzed.
+	Strategies []strategy.Strategy
+	// Trace is the configuration of the tracer recording the decisions of the
+	// buyer and the seller of each permutation. In case Trace.Every is 0,
+	// decisions are not recorded.
+	Trace trace.Config
+	// TraceExport is the path of the file the decision records of the latest
+	// permutation are exported to as JSON lines. In case TraceExport is empty,
+	// decision records are not exported.
+	TraceExport string
 }
 
 // DefaultConfig returns the default configuration used to create a new analyzer
@@ -41,6 +82,16 @@ func DefaultConfig() Config {
 		// Dependencies.
 		Informer: nil,
 		Logger:   nil,
+
+		// Settings.
+		Buyer:       buyerconfig.Config{},
+		Features:    nil,
+		Seller:      sellerconfig.Config{},
+		Slice:       "",
+		Slices:      nil,
+		Strategies:  nil,
+		Trace:       trace.Config{},
+		TraceExport: "",
 	}
 }
 
@@ -53,16 +104,87 @@ func New(config Config) (analyzer.Analyzer, error) {
 	if config.Logger == nil {
 		return nil, microerror.MaskAnyf(invalidConfigError, "config.Logger must not be empty")
 	}
+	// Each permutation reads all charts to their end, which endless informers
+	// never reach.
+	if informer.IsEndless(config.Informer) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Informer must not be endless")
+	}
+
+	// Settings.
+	err := validateIndicators(config.Buyer, config.Seller)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	for _, f := range config.Features {
+		if f != runtimeconfig.FeaturePercentile && f != runtimeconfig.FeatureSurge {
+			return nil, microerror.MaskAnyf(invalidConfigError, "config.Features must not contain '%s'", f)
+		}
+	}
+
+	for _, s := range config.Slices {
+		err := s.Validate()
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	if config.Trace.Every != 0 {
+		err := config.Trace.Validate()
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, "config.Trace: %s", err.Error())
+		}
+	}
+	if config.TraceExport != "" && config.Trace.Every == 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.TraceExport requires config.Trace.Every")
+	}
+
+	// In case a slice is referenced, the analyzer only sees the price events of
+	// each chart within the slice.
+	newInformer := config.Informer
+	var newSlice slice.Slice
+	if config.Slice != "" {
+		newSlice, err = slice.Find(config.Slices, config.Slice)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, "config.Slice: %s", err.Error())
+		}
+
+		sliceConfig := slice.DefaultConfig()
+		sliceConfig.Informer = config.Informer
+		sliceConfig.Slice = newSlice
+		newInformer, err = slice.New(sliceConfig)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	// Strategies referring to unknown check functions or defining invalid
+	// params are rejected before any permutation is analyzed.
+	for _, s := range config.Strategies {
+		err := validateStrategy(s)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, "config.Strategies: %s", err.Error())
+		}
+	}
 
 	runtimeConfig := &runtimeconfig.Config{}
+	runtimeConfig.Buyer = config.Buyer
+	runtimeConfig.Features = config.Features
+	runtimeConfig.Seller = config.Seller
+	runtimeConfig.Strategies = config.Strategies
+	if len(config.Strategies) == 1 {
+		err := runtimeConfig.SetPermValue(runtimeconfig.PermIDStrategy, 0)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
 
 	var newPermutation permutation.Permutation
-	var err error
 	{
-		config := v1permutation.DefaultConfig()
-		config.Logger = config.Logger
-		config.Object = runtimeConfig
-		newPermutation, err = v1permutation.New(config)
+		permutationConfig := v1permutation.DefaultConfig()
+		permutationConfig.Logger = config.Logger
+		permutationConfig.Object = runtimeConfig
+		newPermutation, err = v1permutation.New(permutationConfig)
 		if err != nil {
 			return nil, microerror.MaskAny(err)
 		}
@@ -70,9 +192,13 @@ func New(config Config) (analyzer.Analyzer, error) {
 
 	newAnalyzer := &Analyzer{
 		// Dependencies.
-		informer: config.Informer,
+		informer: newInformer,
 		logger:   config.Logger,
 
+		// Settings.
+		trace:       config.Trace,
+		traceExport: config.TraceExport,
+
 		// Internals.
 		analyzeOnce: sync.Once{},
 		mutex:       sync.Mutex{},
@@ -82,6 +208,7 @@ func New(config Config) (analyzer.Analyzer, error) {
 			State:  runtimestate.State{},
 		},
 	}
+	newAnalyzer.runtime.State.Informer.Slice = newSlice
 
 	return newAnalyzer, nil
 }
@@ -92,6 +219,10 @@ type Analyzer struct {
 	informer informer.Informer
 	logger   micrologger.Logger
 
+	// Settings.
+	trace       trace.Config
+	traceExport string
+
 	// Internals.
 	analyzeOnce sync.Once
 	mutex       sync.Mutex
@@ -122,11 +253,15 @@ func (a *Analyzer) execute() error {
 	stepDuration := &Duration{}
 
 	var stepCurrent float64
-	a.runtime.State.Informer.Prices = a.informer.Runtime().State.Prices
 	a.runtime.State.Permutation.Max = max
 	a.runtime.State.Permutation.Start = time.Now()
 	a.runtime.State.Permutation.Step.Total = v1permutation.TotalFromMax(max)
 
+	// The context is canceled once the analyzer stops, regardless of whether
+	// the permutation process finished or failed.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	for {
 		var stepStart time.Time
 		{
@@ -147,10 +282,21 @@ func (a *Analyzer) execute() error {
 			return microerror.MaskAnyf(invalidExecutionError, "invalid type for runtime config")
 		}
 
+		// The buyer and the seller of each permutation share a new tracer, so
+		// that decision records of different permutations are not mixed up.
+		var newTracer *trace.Tracer
+		if a.trace.Every != 0 {
+			newTracer, err = trace.New(a.trace)
+			if err != nil {
+				return microerror.MaskAny(err)
+			}
+		}
+
 		var newBuyer buyer.Buyer
 		{
 			config := v1buyer.DefaultConfig()
 			config.Logger = a.logger
+			config.Tracer = newTracer
 
 			// We have to set some parts of the configuration to the runtime config of
 			// the analyzer to be able to track the configuration history properly for
@@ -178,6 +324,7 @@ func (a *Analyzer) execute() error {
 		{
 			config := v1seller.DefaultConfig()
 			config.Logger = a.logger
+			config.Tracer = newTracer
 
 			// We have to set some parts of the configuration to the runtime config of
 			// the analyzer to be able to track the configuration history properly for
@@ -191,6 +338,13 @@ func (a *Analyzer) execute() error {
 			}
 		}
 
+		// The params of the strategy are applied on top of the permuted config.
+		// The configuration history records the config the buyer and the seller
+		// actually trade with.
+		tradedConfig := *runtimeConfig
+		tradedConfig.Buyer = newBuyer.Runtime().Config
+		tradedConfig.Seller = newSeller.Runtime().Config
+
 		var newTrader trader.Trader
 		{
 			config := v1trader.DefaultConfig()
@@ -199,26 +353,53 @@ func (a *Analyzer) execute() error {
 			config.Informer = a.informer
 			config.Logger = a.logger
 			config.Seller = newSeller
+			config.Tracer = newTracer
 			newTrader, err = v1trader.New(config)
 			if err != nil {
 				return microerror.MaskAny(err)
 			}
 		}
 
-		err = newTrader.Execute()
+		err = newTrader.Execute(ctx)
 		if err != nil {
 			return microerror.MaskAny(err)
 		}
 
+		// The charts of the informer may be reloaded during analysis. Each
+		// permutation is tracked together with the reload generation of the charts
+		// the trader actually executed on.
+		informerState := newTrader.Runtime().State.Informer
+
+		a.mutex.Lock()
+		a.runtime.State.Informer.Generation = informerState.Generation
+		a.runtime.State.Informer.Prices = informerState.Prices
+		a.mutex.Unlock()
+
+		if newTracer != nil {
+			records := newTrader.Runtime().State.Trace
+
+			a.mutex.Lock()
+			a.runtime.State.Trace = records
+			a.mutex.Unlock()
+
+			if a.traceExport != "" {
+				err := exportTrace(a.traceExport, records)
+				if err != nil {
+					return microerror.MaskAny(err)
+				}
+			}
+		}
+
 		a.mutex.Lock()
 		cycles := newTrader.Runtime().State.Trade.Cycles
 		revenues := newTrader.Runtime().State.Trade.Revenues
 		if (len(a.runtime.State.Config.History) == 0 && sum(revenues) > 0) || (len(a.runtime.State.Config.History) > 0 && sum(a.runtime.State.Config.History[0].Revenues) < sum(revenues)) {
 			history := statehistory.History{
-				Config:   *runtimeConfig,
-				Cycles:   cycles,
-				Indizes:  append([]int{}, indizes...), // copy
-				Revenues: revenues,
+				Config:     tradedConfig,
+				Cycles:     cycles,
+				Generation: informerState.Generation,
+				Indizes:    append([]int{}, indizes...), // copy
+				Revenues:   revenues,
 			}
 			a.runtime.State.Config.History = append([]statehistory.History{history}, a.runtime.State.Config.History...) // prepend
 		}
@@ -254,6 +435,83 @@ func (a *Analyzer) eta(stepDuration time.Duration) time.Time {
 	return eta
 }
 
+// validateStrategy makes sure the rules of the given strategy only refer to
+// known check functions and only define params the check functions accept.
+func validateStrategy(s strategy.Strategy) error {
+	err := s.Validate()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	if s.Buyer != nil {
+		err := v1buyer.ValidateRule(*s.Buyer)
+		if err != nil {
+			return microerror.MaskAnyf(invalidConfigError, "strategy '%s': buyer: %s", s.Name, err.Error())
+		}
+	}
+	if s.Seller != nil {
+		err := v1seller.ValidateRule(*s.Seller)
+		if err != nil {
+			return microerror.MaskAnyf(invalidConfigError, "strategy '%s': seller: %s", s.Name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateIndicators makes sure the check functions reading from indicators
+// are configured properly, before any permutation is analyzed. These check
+// functions are not permuted, so their configuration is shared by all
+// permutations.
+func validateIndicators(b buyerconfig.Config, s sellerconfig.Config) error {
+	err := b.Trade.Bollinger.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, "config.Buyer: %s", err.Error())
+	}
+	err = b.Trade.RSI.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, "config.Buyer: %s", err.Error())
+	}
+	err = s.Trade.RSI.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, "config.Seller: %s", err.Error())
+	}
+
+	_, err = b.DeclareIndicators()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, "config.Buyer: %s", err.Error())
+	}
+	_, err = s.DeclareIndicators()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, "config.Seller: %s", err.Error())
+	}
+
+	return nil
+}
+
+// exportTrace writes the given decision records to the file at the given path.
+// The file is overwritten, so it always holds the records of the latest
+// permutation.
+func exportTrace(path string, records []trace.Record) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+	defer f.Close()
+
+	err = trace.Export(f, records)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
 func sum(list []float64) float64 {
 	var s float64
 
@@ -0,0 +1,84 @@
+package rolling
+
+import (
+	"time"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// Queue is a rolling time window of observed price events, kept in the order
+// they are observed. Other than Window, Queue does not answer order
+// statistics, but provides the price event the window starts with and the
+// average traded volume within the window.
+type Queue struct {
+	duration time.Duration
+	head     int
+	prices   []informer.Price
+	volume   float64
+}
+
+// NewQueue creates a new queue covering the given duration. Price events
+// observed more than the given duration before the latest observed price event
+// leave the queue.
+func NewQueue(duration time.Duration) *Queue {
+	return &Queue{
+		duration: duration,
+	}
+}
+
+// Add observes the given price event. Price events are expected to be
+// observed in time order. Price events having left the queue are evicted.
+func (q *Queue) Add(p informer.Price) {
+	q.prices = append(q.prices, p)
+	q.volume += p.Volume
+
+	start := p.Time.Add(-q.duration)
+	for q.head < len(q.prices) && q.prices[q.head].Time.Before(start) {
+		q.volume -= q.prices[q.head].Volume
+		q.head++
+	}
+
+	// The queue is compacted as soon as most of it got evicted. Each price event
+	// is moved at most once per compaction, so adding a price event takes
+	// amortized O(1).
+	if q.head > len(q.prices)/2 {
+		n := copy(q.prices, q.prices[q.head:])
+		q.prices = q.prices[:n]
+		q.head = 0
+	}
+}
+
+// First returns the oldest price event within the queue. First returns the
+// zero value in case the queue is empty.
+func (q *Queue) First() informer.Price {
+	if q.Len() == 0 {
+		return informer.Price{}
+	}
+
+	return q.prices[q.head]
+}
+
+// Last returns the latest price event within the queue. Last returns the zero
+// value in case the queue is empty.
+func (q *Queue) Last() informer.Price {
+	if q.Len() == 0 {
+		return informer.Price{}
+	}
+
+	return q.prices[len(q.prices)-1]
+}
+
+// Len returns the number of price events within the queue.
+func (q *Queue) Len() int {
+	return len(q.prices) - q.head
+}
+
+// Volume returns the average traded volume of the price events within the
+// queue. Volume returns 0 in case the queue is empty.
+func (q *Queue) Volume() float64 {
+	if q.Len() == 0 {
+		return 0
+	}
+
+	return q.volume / float64(q.Len())
+}
@@ -0,0 +1,139 @@
+// Package rolling provides rolling time windows of observed prices. Window
+// answers order statistics like percentiles of the prices observed within the
+// window, without sorting the observed prices over and over again. Queue keeps
+// the price events observed within the window in the order they are observed.
+//
+// The window keeps its prices in two forms. The prices are queued in a ring
+// buffer in the order they are observed, so that prices leaving the window are
+// evicted from the front without moving the remaining prices. At the same time
+// the prices are kept in an order statistic tree, so that order statistics are
+// found without sorting. Adding a price and evicting a price each take
+// O(log n) for a window of n prices, and answering order statistics takes
+// O(log n) as well.
+package rolling
+
+import (
+	"math"
+	"time"
+)
+
+type observation struct {
+	time  time.Time
+	value float64
+}
+
+// Window is a rolling time window of observed prices. A window must not be
+// used concurrently.
+type Window struct {
+	duration time.Duration
+	queue    ring
+	sorted   *tree
+}
+
+// New creates a new window covering the given duration. Prices observed more
+// than the given duration before the latest observed price leave the window.
+func New(duration time.Duration) *Window {
+	return &Window{
+		duration: duration,
+		sorted:   newTree(),
+	}
+}
+
+// Add observes the given price at the given time. Prices are expected to be
+// observed in time order. Prices having left the window are evicted. NaN
+// prices are ignored, because they cannot be ordered.
+func (w *Window) Add(t time.Time, v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+
+	w.queue.push(observation{time: t, value: v})
+	w.sorted.insert(v)
+
+	start := t.Add(-w.duration)
+	for w.queue.len > 0 && w.queue.front().time.Before(start) {
+		w.sorted.remove(w.queue.pop().value)
+	}
+}
+
+// Len returns the number of prices within the window.
+func (w *Window) Len() int {
+	return w.sorted.len()
+}
+
+// Max returns the highest price within the window. Max returns 0 in case the
+// window is empty.
+func (w *Window) Max() float64 {
+	if w.Len() == 0 {
+		return 0
+	}
+
+	return w.sorted.kth(w.Len() - 1)
+}
+
+// Min returns the lowest price within the window. Min returns 0 in case the
+// window is empty.
+func (w *Window) Min() float64 {
+	if w.Len() == 0 {
+		return 0
+	}
+
+	return w.sorted.kth(0)
+}
+
+// Percentile returns the p-th percentile of the prices within the window, with
+// p being within [0, 100]. Percentiles between two prices are interpolated
+// linearly. So the 0th percentile is the lowest price, the 100th percentile is
+// the highest price and the 50th percentile is the median. Percentile returns
+// 0 in case the window is empty.
+func (w *Window) Percentile(p float64) float64 {
+	n := w.Len()
+	if n == 0 {
+		return 0
+	}
+
+	rank := math.Max(0, math.Min(100, p)) / 100 * float64(n-1)
+	lower := int(math.Floor(rank))
+	if lower == n-1 {
+		return w.sorted.kth(lower)
+	}
+	frac := rank - float64(lower)
+	low := w.sorted.kth(lower)
+
+	return low + frac*(w.sorted.kth(lower+1)-low)
+}
+
+// ring is a ring buffer of observations. The buffer only grows in case it is
+// full, so that a window of steady size neither allocates nor moves its
+// observations.
+type ring struct {
+	head  int
+	items []observation
+	len   int
+}
+
+func (r *ring) front() observation {
+	return r.items[r.head]
+}
+
+func (r *ring) pop() observation {
+	o := r.items[r.head]
+	r.head = (r.head + 1) % len(r.items)
+	r.len--
+
+	return o
+}
+
+func (r *ring) push(o observation) {
+	if r.len == len(r.items) {
+		items := make([]observation, 2*len(r.items)+1)
+		for i := 0; i < r.len; i++ {
+			items[i] = r.items[(r.head+i)%len(r.items)]
+		}
+		r.head = 0
+		r.items = items
+	}
+
+	r.items[(r.head+r.len)%len(r.items)] = o
+	r.len++
+}
@@ -0,0 +1,164 @@
+package rolling
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+func Test_Window_Percentile(t *testing.T) {
+	testCases := []struct {
+		Values     []float64
+		Percentile float64
+		Expected   float64
+	}{
+		// Test case 1 makes sure empty windows provide 0.
+		{
+			Values:     nil,
+			Percentile: 50,
+			Expected:   0,
+		},
+		// Test case 2 makes sure the 0th percentile is the lowest price.
+		{
+			Values:     []float64{3, 1, 2},
+			Percentile: 0,
+			Expected:   1,
+		},
+		// Test case 3 makes sure the 100th percentile is the highest price.
+		{
+			Values:     []float64{3, 1, 2},
+			Percentile: 100,
+			Expected:   3,
+		},
+		// Test case 4 makes sure the median is found.
+		{
+			Values:     []float64{3, 1, 2},
+			Percentile: 50,
+			Expected:   2,
+		},
+		// Test case 5 makes sure percentiles between prices are interpolated.
+		{
+			Values:     []float64{10, 20, 30, 40, 50},
+			Percentile: 10,
+			Expected:   14,
+		},
+	}
+
+	for i, testCase := range testCases {
+		w := New(time.Hour)
+		for j, v := range testCase.Values {
+			w.Add(time.Unix(int64(j), 0), v)
+		}
+
+		p := w.Percentile(testCase.Percentile)
+		if p != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", p)
+		}
+	}
+}
+
+// Test_Window_Evict makes sure prices leave the window once they are older
+// than the window duration, by comparing the window against the prices being
+// sorted from scratch.
+func Test_Window_Evict(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	w := New(10 * time.Second)
+
+	var times []time.Time
+	var values []float64
+	for i := 0; i < 1000; i++ {
+		now := time.Unix(int64(i/3), 0)
+		v := float64(r.Intn(50))
+
+		w.Add(now, v)
+		times = append(times, now)
+		values = append(values, v)
+
+		var expected []float64
+		for j, t := range times {
+			if !t.Before(now.Add(-10 * time.Second)) {
+				expected = append(expected, values[j])
+			}
+		}
+		sort.Float64s(expected)
+
+		if w.Len() != len(expected) {
+			t.Fatal("index", i, "expected", len(expected), "got", w.Len())
+		}
+		if w.Min() != expected[0] {
+			t.Fatal("index", i, "expected", expected[0], "got", w.Min())
+		}
+		if w.Max() != expected[len(expected)-1] {
+			t.Fatal("index", i, "expected", expected[len(expected)-1], "got", w.Max())
+		}
+		if w.Percentile(50) != median(expected) {
+			t.Fatal("index", i, "expected", median(expected), "got", w.Percentile(50))
+		}
+	}
+}
+
+// median returns the median of the given sorted values.
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// Test_Queue_Evict makes sure price events leave the queue once they are older
+// than the queue duration.
+func Test_Queue_Evict(t *testing.T) {
+	q := NewQueue(2 * time.Second)
+
+	testCases := []struct {
+		Price          informer.Price
+		ExpectedFirst  float64
+		ExpectedLen    int
+		ExpectedVolume float64
+	}{
+		{Price: informer.Price{Buy: 1, Time: time.Unix(1, 0), Volume: 2}, ExpectedFirst: 1, ExpectedLen: 1, ExpectedVolume: 2},
+		{Price: informer.Price{Buy: 2, Time: time.Unix(2, 0), Volume: 4}, ExpectedFirst: 1, ExpectedLen: 2, ExpectedVolume: 3},
+		{Price: informer.Price{Buy: 3, Time: time.Unix(3, 0), Volume: 6}, ExpectedFirst: 1, ExpectedLen: 3, ExpectedVolume: 4},
+		{Price: informer.Price{Buy: 4, Time: time.Unix(4, 0), Volume: 8}, ExpectedFirst: 2, ExpectedLen: 3, ExpectedVolume: 6},
+		{Price: informer.Price{Buy: 5, Time: time.Unix(9, 0), Volume: 1}, ExpectedFirst: 5, ExpectedLen: 1, ExpectedVolume: 1},
+	}
+
+	for i, testCase := range testCases {
+		q.Add(testCase.Price)
+
+		if q.First().Buy != testCase.ExpectedFirst {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedFirst, "got", q.First().Buy)
+		}
+		if q.Last() != testCase.Price {
+			t.Fatal("case", i+1, "expected", testCase.Price, "got", q.Last())
+		}
+		if q.Len() != testCase.ExpectedLen {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedLen, "got", q.Len())
+		}
+		if q.Volume() != testCase.ExpectedVolume {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedVolume, "got", q.Volume())
+		}
+	}
+}
+
+// Benchmark_Window_Add measures adding a price to a window of 100000 prices,
+// which evicts the oldest price, and reading a percentile afterwards.
+func Benchmark_Window_Add(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	w := New(100000 * time.Second)
+
+	for i := 0; i < 100000; i++ {
+		w.Add(time.Unix(int64(i), 0), r.Float64())
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Add(time.Unix(int64(100000+i), 0), r.Float64())
+		w.Percentile(90)
+	}
+}
@@ -0,0 +1,184 @@
+package rolling
+
+// tree is an order statistic tree of prices, implemented as treap. Each node
+// holds a distinct price together with the number of times it was observed.
+// Nodes know the number of prices within their subtree, so that the k-th
+// lowest price is found by walking down a single path. The priorities of the
+// nodes keep the tree balanced in expectation, so that inserting, removing and
+// finding prices take O(log n).
+type tree struct {
+	// free are removed nodes being reused for inserted prices, so that a tree
+	// of steady size does not allocate.
+	free *node
+	root *node
+	seed uint64
+}
+
+type node struct {
+	count    int
+	left     *node
+	priority uint64
+	right    *node
+	size     int
+	value    float64
+}
+
+func newTree() *tree {
+	return &tree{
+		seed: 0x9e3779b97f4a7c15,
+	}
+}
+
+// insert adds the given price to the tree.
+func (t *tree) insert(v float64) {
+	t.root = t.insertAt(t.root, v)
+}
+
+// kth returns the k-th lowest price of the tree, with k being within [0, n).
+func (t *tree) kth(k int) float64 {
+	n := t.root
+	for n != nil {
+		l := sizeOf(n.left)
+		if k < l {
+			n = n.left
+			continue
+		}
+		if k < l+n.count {
+			return n.value
+		}
+		k -= l + n.count
+		n = n.right
+	}
+
+	return 0
+}
+
+// len returns the number of prices within the tree.
+func (t *tree) len() int {
+	return sizeOf(t.root)
+}
+
+// remove removes one occurrence of the given price from the tree. Prices not
+// being within the tree are ignored.
+func (t *tree) remove(v float64) {
+	t.root = t.removeAt(t.root, v)
+}
+
+func (t *tree) insertAt(n *node, v float64) *node {
+	if n == nil {
+		return t.newNode(v)
+	}
+
+	switch {
+	case v < n.value:
+		n.left = t.insertAt(n.left, v)
+		if n.left.priority > n.priority {
+			n = rotateRight(n)
+		}
+	case v > n.value:
+		n.right = t.insertAt(n.right, v)
+		if n.right.priority > n.priority {
+			n = rotateLeft(n)
+		}
+	default:
+		n.count++
+	}
+	n.update()
+
+	return n
+}
+
+func (t *tree) removeAt(n *node, v float64) *node {
+	if n == nil {
+		return nil
+	}
+
+	switch {
+	case v < n.value:
+		n.left = t.removeAt(n.left, v)
+	case v > n.value:
+		n.right = t.removeAt(n.right, v)
+	case n.count > 1:
+		n.count--
+	default:
+		joined := join(n.left, n.right)
+		*n = node{right: t.free}
+		t.free = n
+		return joined
+	}
+	n.update()
+
+	return n
+}
+
+func (t *tree) newNode(v float64) *node {
+	// xorshift64 provides the priorities of new nodes. Priorities only need to
+	// be spread evenly, not to be unpredictable.
+	t.seed ^= t.seed << 13
+	t.seed ^= t.seed >> 7
+	t.seed ^= t.seed << 17
+
+	n := t.free
+	if n != nil {
+		t.free = n.right
+	} else {
+		n = &node{}
+	}
+	*n = node{count: 1, priority: t.seed, size: 1, value: v}
+
+	return n
+}
+
+func (n *node) update() {
+	n.size = sizeOf(n.left) + sizeOf(n.right) + n.count
+}
+
+// join joins the given subtrees, with all prices of a being lower than all
+// prices of b.
+func join(a, b *node) *node {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	if a.priority > b.priority {
+		a.right = join(a.right, b)
+		a.update()
+		return a
+	}
+
+	b.left = join(a, b.left)
+	b.update()
+
+	return b
+}
+
+func rotateLeft(n *node) *node {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+
+	return r
+}
+
+func rotateRight(n *node) *node {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+
+	return l
+}
+
+func sizeOf(n *node) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
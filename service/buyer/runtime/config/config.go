@@ -4,17 +4,97 @@ import (
 	microerror "github.com/giantswarm/microkit/error"
 
 	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade"
+	"github.com/xh3b4sd/wafer/service/indicator"
+	"github.com/xh3b4sd/wafer/service/strategy"
 )
 
 type Config struct {
-	Trade trade.Trade `json:"trade"`
+	// Block is the rule composing the check functions which block buy events.
+	// In case Block is nil, the default rule of the buyer version is used.
+	Block *strategy.Rule `json:"block,omitempty"`
+	// Indicators are the named indicators the buyer tracks. Check functions
+	// refer to them by name.
+	Indicators map[string]indicator.Config `json:"indicators"`
+	Trade      trade.Trade                 `json:"trade"`
+}
+
+// DeclareIndicators returns the config having the indicators declared which
+// the check functions describe by their period. See indicator.Declare.
+func (c Config) DeclareIndicators() (Config, error) {
+	var err error
+
+	if c.Trade.Bollinger.Period != 0 {
+		i := indicator.Config{
+			K:      c.Trade.Bollinger.K,
+			Kind:   indicator.KindBollinger,
+			Period: c.Trade.Bollinger.Period,
+		}
+		c.Indicators, c.Trade.Bollinger.Indicator, err = indicator.Declare(c.Indicators, c.Trade.Bollinger.Indicator, i)
+		if err != nil {
+			return Config{}, microerror.MaskAnyf(invalidConfigError, "Trade.Bollinger: %s", err.Error())
+		}
+	}
+	if c.Trade.RSI.Period != 0 {
+		i := indicator.Config{
+			Kind:   indicator.KindRSI,
+			Period: c.Trade.RSI.Period,
+		}
+		c.Indicators, c.Trade.RSI.Indicator, err = indicator.Declare(c.Indicators, c.Trade.RSI.Indicator, i)
+		if err != nil {
+			return Config{}, microerror.MaskAnyf(invalidConfigError, "Trade.RSI: %s", err.Error())
+		}
+	}
+
+	return c, nil
 }
 
 func (c Config) Validate() error {
+	if c.Block != nil {
+		err := c.Block.Validate()
+		if err != nil {
+			return microerror.MaskAnyf(invalidConfigError, "Block: %s", err.Error())
+		}
+	}
+
+	for name, i := range c.Indicators {
+		err := i.Validate()
+		if err != nil {
+			return microerror.MaskAnyf(invalidConfigError, "Indicators.%s: %s", name, err.Error())
+		}
+	}
+
 	err := c.Trade.Validate()
 	if err != nil {
 		return microerror.MaskAnyf(invalidConfigError, err.Error())
 	}
 
+	err = c.indicator(c.Trade.Bollinger.Indicator, indicator.KindBollinger)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+	err = c.indicator(c.Trade.RSI.Indicator, indicator.KindRSI)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+// indicator makes sure the indicator having the given name is configured and
+// is of the given kind. Empty names are accepted, because they disable the
+// check functions referring to them.
+func (c Config) indicator(name, kind string) error {
+	if name == "" {
+		return nil
+	}
+
+	i, ok := c.Indicators[name]
+	if !ok {
+		return microerror.MaskAnyf(invalidConfigError, "indicator '%s' must be configured", name)
+	}
+	if i.Kind != kind {
+		return microerror.MaskAnyf(invalidConfigError, "indicator '%s' must be of kind '%s'", name, kind)
+	}
+
 	return nil
 }
@@ -0,0 +1,43 @@
+package bollinger
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Bollinger describes the configuration of the Bollinger bands buy events are
+// allowed to happen within. Consider the following configuration.
+//
+//     Indicator     bollinger20
+//
+// This configuration means that buy events are not allowed to happen in case
+// the buy price is above the upper band of the indicator named bollinger20.
+// Instead of naming an indicator, the indicator can be described by its period
+// and the number of standard deviations its bands are away from the middle
+// band.
+//
+//     K             2
+//     Period        20
+//
+type Bollinger struct {
+	// Indicator is the name of the Bollinger indicator to read from. Empty
+	// disables the check, unless Period is given.
+	Indicator string `json:"indicator"`
+	// K is the number of standard deviations the bands of the Bollinger
+	// indicator are away from the middle band. K is only used together with
+	// Period.
+	K float64 `json:"k"`
+	// Period is the number of price events the Bollinger indicator covers. In
+	// case Period is given, the indicator is declared on behalf of the check.
+	Period int `json:"period"`
+}
+
+func (b Bollinger) Validate() error {
+	if b.Period < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Bollinger.Period must not be negative")
+	}
+	if b.Period != 0 && b.K <= 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Bollinger.K must be greater than 0")
+	}
+
+	return nil
+}
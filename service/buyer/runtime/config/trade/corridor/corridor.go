@@ -1,6 +1,8 @@
 package corridor
 
 import (
+	"time"
+
 	microerror "github.com/giantswarm/microkit/error"
 )
 
@@ -12,15 +14,57 @@ import (
 //
 // This configuration means that buy events are not allowed to happen in case
 // the inspected price is above 90% of the highest known price. The known price
-// is taken from the observed chart window.
+// is taken from the observed chart window. Without Window the observed chart
+// window is the whole lifetime of the buyer, so a single spike dominates the
+// corridor forever. Consider the following configuration.
+//
+//     Max                90
+//     Percentile.Min     10
+//     Percentile.Max     80
+//     Window             168h
+//
+// This configuration means that buy events are only allowed to happen in case
+// the inspected price is between the 10th and the 80th percentile of the prices
+// observed within the last 7 days. The highest known price is taken from the
+// last 7 days as well.
 type Corridor struct {
 	// Max is the maximum value within the allowed corridor.
 	Max float64 `json:"max"`
+	// Percentile are the percentile bounds of the allowed corridor. The zero
+	// value disables the percentile bounds.
+	Percentile Percentile `json:"percentile"`
+	// Window is the duration of the rolling window of observed prices the
+	// corridor is calculated from. Zero observes prices over the whole lifetime
+	// of the buyer. Percentile bounds require a window.
+	Window time.Duration `json:"window"`
+}
+
+// Percentile describes the percentile bounds of the allowed corridor. Buy
+// events are only allowed for prices between the Min-th and the Max-th
+// percentile of the prices observed within the window. Values are within
+// [0, 100].
+type Percentile struct {
+	// Max is the percentile of the upper bound of the allowed corridor.
+	Max float64 `json:"max"`
+	// Min is the percentile of the lower bound of the allowed corridor.
+	Min float64 `json:"min"`
 }
 
 func (c Corridor) Validate() error {
 	if c.Max == 0 {
-		return microerror.MaskAnyf(invalidConfigError, "Surge.Max must not be empty")
+		return microerror.MaskAnyf(invalidConfigError, "Corridor.Max must not be empty")
+	}
+	if c.Window < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Corridor.Window must not be negative")
+	}
+
+	if c.Percentile != (Percentile{}) {
+		if c.Window == 0 {
+			return microerror.MaskAnyf(invalidConfigError, "Corridor.Percentile requires Corridor.Window")
+		}
+		if c.Percentile.Min < 0 || c.Percentile.Max > 100 || c.Percentile.Min >= c.Percentile.Max {
+			return microerror.MaskAnyf(invalidConfigError, "Corridor.Percentile must satisfy 0 <= Min < Max <= 100")
+		}
 	}
 
 	return nil
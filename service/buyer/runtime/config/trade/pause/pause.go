@@ -13,7 +13,7 @@ type Pause struct {
 
 func (p Pause) Validate() error {
 	if p.Min.Seconds() == 0 {
-		return microerror.MaskAnyf(invalidConfigError, "Surge.Min must not be empty")
+		return microerror.MaskAnyf(invalidConfigError, "Pause.Min must not be empty")
 	}
 
 	return nil
@@ -0,0 +1,41 @@
+package rsi
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// RSI describes the configuration of the relative strength index allowed for
+// buy events to happen. Consider the following configuration.
+//
+//     Indicator     rsi14
+//     Max           70
+//
+// This configuration means that buy events are not allowed to happen in case
+// the indicator named rsi14 is above 70, which means the market is considered
+// overbought. Instead of naming an indicator, the indicator can be described
+// by its period.
+//
+//     Max           70
+//     Period        14
+//
+type RSI struct {
+	// Indicator is the name of the RSI indicator to read from. Empty disables
+	// the check, unless Period is given.
+	Indicator string `json:"indicator"`
+	// Max is the maximum RSI allowed.
+	Max float64 `json:"max"`
+	// Period is the number of price events the RSI indicator covers. In case
+	// Period is given, the indicator is declared on behalf of the check.
+	Period int `json:"period"`
+}
+
+func (r RSI) Validate() error {
+	if r.Period < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "RSI.Period must not be negative")
+	}
+	if (r.Indicator != "" || r.Period != 0) && (r.Max <= 0 || r.Max > 100) {
+		return microerror.MaskAnyf(invalidConfigError, "RSI.Max must be within (0, 100]")
+	}
+
+	return nil
+}
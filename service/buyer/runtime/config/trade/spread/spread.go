@@ -0,0 +1,26 @@
+package spread
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Spread describes the configuration of the difference between buy and sell
+// prices allowed for buy events to happen. The values are provided in percent
+// of the buy price. Consider the following configuration.
+//
+//     Max     2
+//
+// This configuration means that buy events are not allowed to happen in case
+// the sell price is more than 2% below the buy price.
+type Spread struct {
+	// Max is the maximum spread allowed. Zero disables the check.
+	Max float64 `json:"max"`
+}
+
+func (s Spread) Validate() error {
+	if s.Max < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Spread.Max must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package surge
+
+import (
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Surge describes the configuration of the surge buy events are required to
+// ride. A surge is a rate of change of the buy price above a threshold within
+// a window of time, optionally confirmed by rising volume or consecutive
+// higher highs. Consider the following configuration.
+//
+//     HigherHighs     3
+//     Min             2
+//     Volume          1.5
+//     Window          1h
+//
+// This configuration means that buy events are only allowed to happen in case
+// the buy price rose by at least 2% within the last hour, the last 3 price
+// events each had a higher high than the one in front of them, and the traded
+// volume of the current price event is at least 1.5 times the average volume
+// within the last hour.
+type Surge struct {
+	// HigherHighs is the number of consecutive higher highs required to confirm
+	// a surge. Zero disables the confirmation.
+	HigherHighs int `json:"higherhighs"`
+	// Min is the minimum rate of change of the buy price within the window, in
+	// percent. Zero disables the check.
+	Min float64 `json:"min"`
+	// Volume is the factor by which the traded volume of the current price event
+	// has to exceed the average traded volume within the window to confirm a
+	// surge. Zero disables the confirmation.
+	Volume float64 `json:"volume"`
+	// Window is the duration within which the rate of change is measured.
+	Window time.Duration `json:"window"`
+}
+
+func (s Surge) Validate() error {
+	if s.HigherHighs < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Surge.HigherHighs must not be negative")
+	}
+	if s.Min < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Surge.Min must not be negative")
+	}
+	if s.Volume < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Surge.Volume must not be negative")
+	}
+	if s.Min != 0 && s.Window <= 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Surge.Window must be greater than 0")
+	}
+
+	return nil
+}
@@ -3,15 +3,25 @@ package trade
 import (
 	microerror "github.com/giantswarm/microkit/error"
 
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/bollinger"
 	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/corridor"
 	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/pause"
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/rsi"
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/spread"
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/surge"
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/volume"
 )
 
 type Trade struct {
+	Bollinger bollinger.Bollinger `json:"bollinger"`
 	// Concurrent is the maximum number of allowed parallel buy events.
 	Concurrent int               `json:"concurrent"`
 	Corridor   corridor.Corridor `json:"corridor"`
 	Pause      pause.Pause       `json:"pause"`
+	RSI        rsi.RSI           `json:"rsi"`
+	Spread     spread.Spread     `json:"spread"`
+	Surge      surge.Surge       `json:"surge"`
+	Volume     volume.Volume     `json:"volume"`
 }
 
 func (t Trade) Validate() error {
@@ -21,6 +31,10 @@ func (t Trade) Validate() error {
 
 	var err error
 
+	err = t.Bollinger.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
 	err = t.Corridor.Validate()
 	if err != nil {
 		return microerror.MaskAnyf(invalidConfigError, err.Error())
@@ -29,6 +43,22 @@ func (t Trade) Validate() error {
 	if err != nil {
 		return microerror.MaskAnyf(invalidConfigError, err.Error())
 	}
+	err = t.RSI.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+	err = t.Spread.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+	err = t.Surge.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+	err = t.Volume.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
 
 	return nil
 }
@@ -0,0 +1,21 @@
+package volume
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Volume describes the configuration of traded volumes required for buy events
+// to happen. Note that this requires an informer providing traded volumes.
+type Volume struct {
+	// Min is the minimum volume a price event must have been traded with to
+	// allow a buy event. Zero disables the check.
+	Min float64 `json:"min"`
+}
+
+func (v Volume) Validate() error {
+	if v.Min < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Volume.Min must not be negative")
+	}
+
+	return nil
+}
@@ -2,8 +2,13 @@ package state
 
 import (
 	"github.com/xh3b4sd/wafer/service/buyer/runtime/state/trade"
+	"github.com/xh3b4sd/wafer/service/indicator"
 )
 
 type State struct {
-	Trade trade.Trade
+	// Indicators holds the configured indicators. Indicators is nil as long as
+	// the buyer did not track any price event. Note that copies of the runtime
+	// share the indicators.
+	Indicators *indicator.Set
+	Trade      trade.Trade
 }
@@ -1,8 +1,27 @@
 package corridor
 
-// Corridor describes the state of a price range observed over the lifetime of
-// the buyer.
+import (
+	"github.com/xh3b4sd/wafer/service/buyer/rolling"
+)
+
+// Corridor describes the state of a price range observed by the buyer.
 type Corridor struct {
-	// Max is the maximum price ever observed by the buyer.
+	// Max is the maximum price observed within the configured window. Without
+	// window, Max is the maximum price ever observed by the buyer.
+	Max float64
+	// Percentile are the prices at the configured percentile bounds of the
+	// prices observed within the configured window.
+	Percentile Percentile
+	// Window holds the prices observed within the configured window. Window is
+	// nil as long as no window is configured. Note that copies of the runtime
+	// share the window.
+	Window *rolling.Window
+}
+
+// Percentile describes the prices at the configured percentile bounds.
+type Percentile struct {
+	// Max is the price at the upper percentile bound.
 	Max float64
+	// Min is the price at the lower percentile bound.
+	Min float64
 }
@@ -0,0 +1,22 @@
+package surge
+
+import (
+	"github.com/xh3b4sd/wafer/service/buyer/rolling"
+)
+
+// Surge describes the state of the surge observed by the buyer.
+type Surge struct {
+	// HigherHighs is the number of consecutive price events each having a higher
+	// high than the price event in front of it.
+	HigherHighs int
+	// Queue holds the price events observed within the configured window. Queue
+	// is nil as long as no surge is configured. Note that copies of the runtime
+	// share the queue.
+	Queue *rolling.Queue
+	// ROC is the rate of change of the buy price within the configured window,
+	// in percent.
+	ROC float64
+	// Volume is the average traded volume of the price events observed within
+	// the configured window before the current price event.
+	Volume float64
+}
@@ -3,11 +3,13 @@ package trade
 import (
 	"github.com/xh3b4sd/wafer/service/buyer/runtime/state/trade/corridor"
 	"github.com/xh3b4sd/wafer/service/buyer/runtime/state/trade/price"
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/state/trade/surge"
 )
 
 type Trade struct {
 	Corridor corridor.Corridor
 	Price    price.Price
+	Surge    surge.Surge
 	// Concurrent is the number of concurrent buys emitted by the buyer.
 	Concurrent int
 }
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"github.com/xh3b4sd/wafer/service/buyer/runtime"
+	"github.com/xh3b4sd/wafer/service/indicator"
 )
 
 type CheckFunc func(r runtime.Runtime) (bool, error)
@@ -18,10 +19,8 @@ func IsAboveMaxBuys(r runtime.Runtime) (bool, error) {
 // IsOutsideMaxCorridor implements CheckFunc to make sure buy events do not
 // happen outside a configured price range. E.g. when the price is higher than
 // ever seen, it is not likely to rise even more. Then we do not want to buy.
-//
-// TODO check effectiveness of corridor percentiles. For now we only have a
-// static absolute number which is not very representative. Percentiles may
-// perform better.
+// The highest price seen is taken from the configured window, if any. See also
+// IsOutsidePercentileCorridor.
 func IsOutsideMaxCorridor(r runtime.Runtime) (bool, error) {
 	currentPrice := r.State.Trade.Price.Current.Buy
 	maxPercAllowed := r.Config.Trade.Corridor.Max
@@ -33,6 +32,23 @@ func IsOutsideMaxCorridor(r runtime.Runtime) (bool, error) {
 	return isAboveMaxTradeLimit, nil
 }
 
+// IsOutsidePercentileCorridor implements CheckFunc to make sure buy events do
+// not happen outside the configured percentile bounds of the prices observed
+// within the configured window. E.g. we want to buy only between the 10th and
+// the 80th percentile of the last 7 days.
+func IsOutsidePercentileCorridor(r runtime.Runtime) (bool, error) {
+	if r.Config.Trade.Corridor.Percentile.Max == 0 {
+		return false, nil
+	}
+
+	currentPrice := r.State.Trade.Price.Current.Buy
+	percentile := r.State.Trade.Corridor.Percentile
+
+	isOutsidePercentileCorridor := currentPrice < percentile.Min || currentPrice > percentile.Max
+
+	return isOutsidePercentileCorridor, nil
+}
+
 // IsInsideMinTradePause implements CheckFunc to make sure buy events do not
 // happen under a configured trade pause. E.g. we want to wait some time after
 // buying commodities before we buy again.
@@ -45,3 +61,109 @@ func IsInsideMinTradePause(r runtime.Runtime) (bool, error) {
 
 	return isInsideMinTradePause, nil
 }
+
+// IsAboveMaxSpread implements CheckFunc to make sure buy events do not happen
+// when the difference between buy and sell price is too big. E.g. when the sell
+// price is far below the buy price, the market is not liquid enough and a trade
+// would need a lot of revenue just to compensate the spread.
+func IsAboveMaxSpread(r runtime.Runtime) (bool, error) {
+	if r.Config.Trade.Spread.Max == 0 {
+		return false, nil
+	}
+
+	currentPrice := r.State.Trade.Price.Current
+	if currentPrice.Buy == 0 {
+		return false, nil
+	}
+
+	spreadPerc := currentPrice.Spread() * 100 / currentPrice.Buy
+	isAboveMaxSpread := spreadPerc > r.Config.Trade.Spread.Max
+
+	return isAboveMaxSpread, nil
+}
+
+// IsBelowMinVolume implements CheckFunc to make sure buy events do not happen
+// on illiquid price events. E.g. when only a tiny volume was traded at the
+// current price, the price is not representative for the market.
+func IsBelowMinVolume(r runtime.Runtime) (bool, error) {
+	if r.Config.Trade.Volume.Min == 0 {
+		return false, nil
+	}
+
+	isBelowMinVolume := r.State.Trade.Price.Current.Volume < r.Config.Trade.Volume.Min
+
+	return isBelowMinVolume, nil
+}
+
+// IsAboveMaxRSI implements CheckFunc to make sure buy events do not happen in
+// overbought markets. E.g. when prices rose steadily, the relative strength
+// index is high and prices are likely to fall back.
+func IsAboveMaxRSI(r runtime.Runtime) (bool, error) {
+	i, ok := readyIndicator(r, r.Config.Trade.RSI.Indicator)
+	if !ok {
+		return false, nil
+	}
+
+	isAboveMaxRSI := i.Value() > r.Config.Trade.RSI.Max
+
+	return isAboveMaxRSI, nil
+}
+
+// IsAboveBollingerUpper implements CheckFunc to make sure buy events do not
+// happen when the price broke out above the upper Bollinger band. E.g. when
+// the price is far above its moving average, it is likely to fall back.
+func IsAboveBollingerUpper(r runtime.Runtime) (bool, error) {
+	i, ok := readyIndicator(r, r.Config.Trade.Bollinger.Indicator)
+	if !ok {
+		return false, nil
+	}
+	b, ok := i.(*indicator.Bollinger)
+	if !ok {
+		return false, nil
+	}
+
+	isAboveBollingerUpper := r.State.Trade.Price.Current.Buy > b.Upper()
+
+	return isAboveBollingerUpper, nil
+}
+
+// readyIndicator returns the indicator having the given name, in case it is
+// ready. Check functions reading from indicators which are not yet ready do
+// not prevent buy events.
+func readyIndicator(r runtime.Runtime, name string) (indicator.Indicator, bool) {
+	if name == "" || r.State.Indicators == nil {
+		return nil, false
+	}
+
+	i, ok := r.State.Indicators.Get(name)
+	if !ok || !i.Ready() {
+		return nil, false
+	}
+
+	return i, true
+}
+
+// IsBelowMinSurge implements CheckFunc to make sure buy events only happen
+// when the market surges. E.g. when the buy price did not rise fast enough
+// within the configured window, there is no wave to ride. Surges may be
+// required to be confirmed by consecutive higher highs and rising volume.
+func IsBelowMinSurge(r runtime.Runtime) (bool, error) {
+	c := r.Config.Trade.Surge
+	if c.Min == 0 {
+		return false, nil
+	}
+
+	s := r.State.Trade.Surge
+
+	if s.ROC < c.Min {
+		return true, nil
+	}
+	if c.HigherHighs > 0 && s.HigherHighs < c.HigherHighs {
+		return true, nil
+	}
+	if c.Volume > 0 && r.State.Trade.Price.Current.Volume < c.Volume*s.Volume {
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -5,6 +5,11 @@ import (
 	"time"
 
 	"github.com/xh3b4sd/wafer/service/buyer/runtime"
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/bollinger"
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/corridor"
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/rsi"
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config/trade/surge"
+	"github.com/xh3b4sd/wafer/service/indicator"
 	"github.com/xh3b4sd/wafer/service/informer"
 )
 
@@ -61,3 +66,376 @@ func Test_IsInsideMinTradePause(t *testing.T) {
 		}
 	}
 }
+
+func Test_IsBelowMinVolume(t *testing.T) {
+	testCases := []struct {
+		Volume   float64
+		Min      float64
+		Expected bool
+	}{
+		// Test case 1 makes sure the check is disabled by default.
+		{
+			Volume:   0,
+			Min:      0,
+			Expected: false,
+		},
+		// Test case 2 makes sure illiquid price events are detected.
+		{
+			Volume:   0.5,
+			Min:      1,
+			Expected: true,
+		},
+		// Test case 3 makes sure liquid price events pass.
+		{
+			Volume:   1,
+			Min:      1,
+			Expected: false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		r := runtime.Runtime{}
+		r.State.Trade.Price.Current = informer.Price{Volume: testCase.Volume}
+		r.Config.Trade.Volume.Min = testCase.Min
+
+		ok, err := IsBelowMinVolume(r)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ok)
+		}
+	}
+}
+
+func Test_IsAboveMaxSpread(t *testing.T) {
+	testCases := []struct {
+		Price    informer.Price
+		Max      float64
+		Expected bool
+	}{
+		// Test case 1 makes sure the check is disabled by default.
+		{
+			Price:    informer.Price{Buy: 100, Sell: 50},
+			Max:      0,
+			Expected: false,
+		},
+		// Test case 2 makes sure a spread of 5% is above a maximum of 2%.
+		{
+			Price:    informer.Price{Buy: 100, Sell: 95},
+			Max:      2,
+			Expected: true,
+		},
+		// Test case 3 makes sure a spread of 1% is below a maximum of 2%.
+		{
+			Price:    informer.Price{Buy: 100, Sell: 99},
+			Max:      2,
+			Expected: false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		r := runtime.Runtime{}
+		r.State.Trade.Price.Current = testCase.Price
+		r.Config.Trade.Spread.Max = testCase.Max
+
+		ok, err := IsAboveMaxSpread(r)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ok)
+		}
+	}
+}
+
+// Test_Corridor makes sure the corridor checks judge the last of the given
+// prices against the prices observed within the configured window.
+func Test_Corridor(t *testing.T) {
+	testCases := []struct {
+		Prices             []float64
+		Corridor           corridor.Corridor
+		ExpectedMax        bool
+		ExpectedPercentile bool
+	}{
+		// Test case 1 makes sure the highest price ever observed is used without
+		// window.
+		{
+			Prices:             []float64{200, 100, 100, 100},
+			Corridor:           corridor.Corridor{Max: 95},
+			ExpectedMax:        false,
+			ExpectedPercentile: false,
+		},
+		// Test case 2 makes sure the highest price observed within the window is
+		// used, once older prices left the window.
+		{
+			Prices:             []float64{200, 100, 100, 100},
+			Corridor:           corridor.Corridor{Max: 95, Window: time.Hour},
+			ExpectedMax:        true,
+			ExpectedPercentile: false,
+		},
+		// Test case 3 makes sure prices above the upper percentile bound are
+		// detected.
+		{
+			Prices:             []float64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
+			Corridor:           corridor.Corridor{Max: 100, Percentile: corridor.Percentile{Min: 10, Max: 80}, Window: 24 * time.Hour},
+			ExpectedMax:        false,
+			ExpectedPercentile: true,
+		},
+		// Test case 4 makes sure prices below the lower percentile bound are
+		// detected.
+		{
+			Prices:             []float64{50, 60, 70, 80, 90, 100, 20},
+			Corridor:           corridor.Corridor{Max: 100, Percentile: corridor.Percentile{Min: 10, Max: 80}, Window: 24 * time.Hour},
+			ExpectedMax:        false,
+			ExpectedPercentile: true,
+		},
+		// Test case 5 makes sure prices within the percentile bounds are allowed.
+		{
+			Prices:             []float64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 50},
+			Corridor:           corridor.Corridor{Max: 100, Percentile: corridor.Percentile{Min: 10, Max: 80}, Window: 24 * time.Hour},
+			ExpectedMax:        false,
+			ExpectedPercentile: false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		r := runtime.Runtime{}
+		r.Config.Trade.Corridor = testCase.Corridor
+
+		for j, p := range testCase.Prices {
+			trackFuncs := []TrackFunc{
+				NewSetCurrentPrice(informer.Price{Buy: p, Time: time.Unix(int64(j)*3600, 0)}),
+				SetCorridorWindow,
+				SetMaxCorridor,
+				SetPercentileCorridor,
+			}
+			for _, f := range trackFuncs {
+				var err error
+				r, err = f(r)
+				if err != nil {
+					t.Fatal("case", i+1, "expected", nil, "got", err)
+				}
+			}
+		}
+
+		ok, err := IsOutsideMaxCorridor(r)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.ExpectedMax {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedMax, "got", ok)
+		}
+
+		ok, err = IsOutsidePercentileCorridor(r)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.ExpectedPercentile {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedPercentile, "got", ok)
+		}
+	}
+}
+
+// Test_Indicators makes sure the indicator checks read from the configured
+// indicators once they are ready.
+func Test_Indicators(t *testing.T) {
+	testCases := []struct {
+		Prices            []float64
+		RSI               rsi.RSI
+		Bollinger         bollinger.Bollinger
+		ExpectedRSI       bool
+		ExpectedBollinger bool
+	}{
+		// Test case 1 makes sure the checks are disabled by default.
+		{
+			Prices:            []float64{1, 2, 3, 4, 5},
+			ExpectedRSI:       false,
+			ExpectedBollinger: false,
+		},
+		// Test case 2 makes sure indicators which are not ready do not prevent
+		// buy events.
+		{
+			Prices:            []float64{1, 2},
+			RSI:               rsi.RSI{Indicator: "rsi", Max: 70},
+			Bollinger:         bollinger.Bollinger{Indicator: "bollinger"},
+			ExpectedRSI:       false,
+			ExpectedBollinger: false,
+		},
+		// Test case 3 makes sure steadily rising prices are overbought and break
+		// out above the upper band.
+		{
+			Prices:            []float64{1, 1, 1, 1, 2, 3, 4, 8},
+			RSI:               rsi.RSI{Indicator: "rsi", Max: 70},
+			Bollinger:         bollinger.Bollinger{Indicator: "bollinger"},
+			ExpectedRSI:       true,
+			ExpectedBollinger: true,
+		},
+		// Test case 4 makes sure falling prices are neither overbought nor above
+		// the upper band.
+		{
+			Prices:            []float64{8, 7, 6, 5, 4, 4, 3, 2},
+			RSI:               rsi.RSI{Indicator: "rsi", Max: 70},
+			Bollinger:         bollinger.Bollinger{Indicator: "bollinger"},
+			ExpectedRSI:       false,
+			ExpectedBollinger: false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		r := runtime.Runtime{}
+		r.Config.Indicators = map[string]indicator.Config{
+			"bollinger": {Kind: indicator.KindBollinger, Period: 4, K: 1},
+			"rsi":       {Kind: indicator.KindRSI, Period: 3},
+		}
+		r.Config.Trade.Bollinger = testCase.Bollinger
+		r.Config.Trade.RSI = testCase.RSI
+
+		for j, p := range testCase.Prices {
+			var err error
+			r, err = NewSetCurrentPrice(informer.Price{Buy: p, Time: time.Unix(int64(j), 0)})(r)
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+			r, err = UpdateIndicators(r)
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+		}
+
+		ok, err := IsAboveMaxRSI(r)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.ExpectedRSI {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedRSI, "got", ok)
+		}
+
+		ok, err = IsAboveBollingerUpper(r)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.ExpectedBollinger {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedBollinger, "got", ok)
+		}
+	}
+}
+
+// Test_IsBelowMinSurge makes sure buy events are only allowed once the buy
+// price surged within the window, confirmed as configured.
+func Test_IsBelowMinSurge(t *testing.T) {
+	testCases := []struct {
+		Prices   []informer.Price
+		Surge    surge.Surge
+		Expected bool
+	}{
+		// Test case 1 makes sure the check is disabled by default.
+		{
+			Prices: []informer.Price{
+				{Buy: 100, Time: time.Unix(0, 0)},
+				{Buy: 99, Time: time.Unix(60, 0)},
+			},
+			Surge:    surge.Surge{},
+			Expected: false,
+		},
+		// Test case 2 makes sure a rise of 3% within the window is a surge.
+		{
+			Prices: []informer.Price{
+				{Buy: 100, Time: time.Unix(0, 0)},
+				{Buy: 101, Time: time.Unix(60, 0)},
+				{Buy: 103, Time: time.Unix(120, 0)},
+			},
+			Surge:    surge.Surge{Min: 2, Window: time.Hour},
+			Expected: false,
+		},
+		// Test case 3 makes sure a rise of 1% within the window is not a surge.
+		{
+			Prices: []informer.Price{
+				{Buy: 100, Time: time.Unix(0, 0)},
+				{Buy: 101, Time: time.Unix(60, 0)},
+			},
+			Surge:    surge.Surge{Min: 2, Window: time.Hour},
+			Expected: true,
+		},
+		// Test case 4 makes sure rises which happened before the window are not
+		// a surge.
+		{
+			Prices: []informer.Price{
+				{Buy: 100, Time: time.Unix(0, 0)},
+				{Buy: 103, Time: time.Unix(7200, 0)},
+				{Buy: 104, Time: time.Unix(7260, 0)},
+			},
+			Surge:    surge.Surge{Min: 2, Window: time.Hour},
+			Expected: true,
+		},
+		// Test case 5 makes sure surges lacking consecutive higher highs are not
+		// confirmed.
+		{
+			Prices: []informer.Price{
+				{Buy: 100, Time: time.Unix(0, 0)},
+				{Buy: 104, Time: time.Unix(60, 0)},
+				{Buy: 103, Time: time.Unix(120, 0)},
+				{Buy: 105, Time: time.Unix(180, 0)},
+			},
+			Surge:    surge.Surge{HigherHighs: 2, Min: 2, Window: time.Hour},
+			Expected: true,
+		},
+		// Test case 6 makes sure surges having consecutive higher highs are
+		// confirmed.
+		{
+			Prices: []informer.Price{
+				{Buy: 100, Time: time.Unix(0, 0)},
+				{Buy: 101, Time: time.Unix(60, 0)},
+				{Buy: 103, Time: time.Unix(120, 0)},
+				{Buy: 105, Time: time.Unix(180, 0)},
+			},
+			Surge:    surge.Surge{HigherHighs: 2, Min: 2, Window: time.Hour},
+			Expected: false,
+		},
+		// Test case 7 makes sure surges lacking rising volume are not confirmed.
+		{
+			Prices: []informer.Price{
+				{Buy: 100, Time: time.Unix(0, 0), Volume: 10},
+				{Buy: 101, Time: time.Unix(60, 0), Volume: 10},
+				{Buy: 103, Time: time.Unix(120, 0), Volume: 12},
+			},
+			Surge:    surge.Surge{Min: 2, Volume: 1.5, Window: time.Hour},
+			Expected: true,
+		},
+		// Test case 8 makes sure surges having rising volume are confirmed.
+		{
+			Prices: []informer.Price{
+				{Buy: 100, Time: time.Unix(0, 0), Volume: 10},
+				{Buy: 101, Time: time.Unix(60, 0), Volume: 10},
+				{Buy: 103, Time: time.Unix(120, 0), Volume: 20},
+			},
+			Surge:    surge.Surge{Min: 2, Volume: 1.5, Window: time.Hour},
+			Expected: false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		r := runtime.Runtime{}
+		r.Config.Trade.Surge = testCase.Surge
+
+		for _, p := range testCase.Prices {
+			var err error
+			r, err = NewSetCurrentPrice(p)(r)
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+			r, err = SetSurge(r)
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+		}
+
+		ok, err := IsBelowMinSurge(r)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ok)
+		}
+	}
+}
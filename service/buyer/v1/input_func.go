@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"github.com/xh3b4sd/wafer/service/buyer/runtime"
+	"github.com/xh3b4sd/wafer/service/indicator"
+)
+
+// InputFunc returns the values a check function bases its verdict on. Input
+// functions are only executed for decisions being traced.
+type InputFunc func(r runtime.Runtime) map[string]interface{}
+
+func aboveBollingerUpperInputs(r runtime.Runtime) map[string]interface{} {
+	inputs := map[string]interface{}{
+		"indicator": r.Config.Trade.Bollinger.Indicator,
+		"price":     r.State.Trade.Price.Current.Buy,
+		"ready":     false,
+	}
+
+	i, ok := readyIndicator(r, r.Config.Trade.Bollinger.Indicator)
+	if !ok {
+		return inputs
+	}
+	b, ok := i.(*indicator.Bollinger)
+	if !ok {
+		return inputs
+	}
+	inputs["ready"] = true
+	inputs["upper"] = b.Upper()
+
+	return inputs
+}
+
+func aboveMaxBuysInputs(r runtime.Runtime) map[string]interface{} {
+	return map[string]interface{}{
+		"concurrent": r.State.Trade.Concurrent,
+		"max":        r.Config.Trade.Concurrent,
+	}
+}
+
+func aboveMaxRSIInputs(r runtime.Runtime) map[string]interface{} {
+	inputs := map[string]interface{}{
+		"indicator": r.Config.Trade.RSI.Indicator,
+		"max":       r.Config.Trade.RSI.Max,
+		"ready":     false,
+	}
+
+	i, ok := readyIndicator(r, r.Config.Trade.RSI.Indicator)
+	if !ok {
+		return inputs
+	}
+	inputs["ready"] = true
+	inputs["rsi"] = i.Value()
+
+	return inputs
+}
+
+func aboveMaxSpreadInputs(r runtime.Runtime) map[string]interface{} {
+	inputs := map[string]interface{}{
+		"max": r.Config.Trade.Spread.Max,
+	}
+
+	currentPrice := r.State.Trade.Price.Current
+	if currentPrice.Buy != 0 {
+		inputs["spread"] = currentPrice.Spread() * 100 / currentPrice.Buy
+	}
+
+	return inputs
+}
+
+func belowMinSurgeInputs(r runtime.Runtime) map[string]interface{} {
+	return map[string]interface{}{
+		"averagevolume":  r.State.Trade.Surge.Volume,
+		"higherhighs":    r.State.Trade.Surge.HigherHighs,
+		"min":            r.Config.Trade.Surge.Min,
+		"minhigherhighs": r.Config.Trade.Surge.HigherHighs,
+		"minvolume":      r.Config.Trade.Surge.Volume,
+		"roc":            r.State.Trade.Surge.ROC,
+		"volume":         r.State.Trade.Price.Current.Volume,
+	}
+}
+
+func belowMinVolumeInputs(r runtime.Runtime) map[string]interface{} {
+	return map[string]interface{}{
+		"min":    r.Config.Trade.Volume.Min,
+		"volume": r.State.Trade.Price.Current.Volume,
+	}
+}
+
+func insideMinTradePauseInputs(r runtime.Runtime) map[string]interface{} {
+	return map[string]interface{}{
+		"last": r.State.Trade.Price.Last.Time,
+		"min":  r.Config.Trade.Pause.Min.String(),
+		"time": r.State.Trade.Price.Current.Time,
+	}
+}
+
+func outsideMaxCorridorInputs(r runtime.Runtime) map[string]interface{} {
+	return map[string]interface{}{
+		"max":      r.Config.Trade.Corridor.Max,
+		"maxprice": r.State.Trade.Corridor.Max,
+		"price":    r.State.Trade.Price.Current.Buy,
+	}
+}
+
+func outsidePercentileCorridorInputs(r runtime.Runtime) map[string]interface{} {
+	return map[string]interface{}{
+		"max":      r.Config.Trade.Corridor.Percentile.Max,
+		"maxprice": r.State.Trade.Corridor.Percentile.Max,
+		"min":      r.Config.Trade.Corridor.Percentile.Min,
+		"minprice": r.State.Trade.Corridor.Percentile.Min,
+		"price":    r.State.Trade.Price.Current.Buy,
+	}
+}
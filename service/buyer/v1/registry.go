@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"reflect"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/buyer/runtime/config"
+	"github.com/xh3b4sd/wafer/service/strategy"
+)
+
+// Check is a check function strategies can refer to by name.
+type Check struct {
+	// Func is the check function.
+	Func CheckFunc
+	// Inputs returns the values the check function bases its verdict on.
+	Inputs InputFunc
+	// Params returns a pointer to the section of the given config the check
+	// function reads its parameters from. Params is nil in case the check
+	// function does not accept params.
+	Params func(c *config.Config) interface{}
+	// Track are the names of the track functions the check function depends
+	// on.
+	Track []string
+}
+
+// Track is a track function check functions can depend on by name.
+type Track struct {
+	Func TrackFunc
+	Name string
+}
+
+// Checks are the check functions strategies can refer to by name.
+var Checks = map[string]Check{
+	"IsAboveBollingerUpper": {
+		Func:   IsAboveBollingerUpper,
+		Inputs: aboveBollingerUpperInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.Bollinger },
+		Track:  []string{"UpdateIndicators"},
+	},
+	"IsAboveMaxBuys": {
+		Func:   IsAboveMaxBuys,
+		Inputs: aboveMaxBuysInputs,
+	},
+	"IsAboveMaxRSI": {
+		Func:   IsAboveMaxRSI,
+		Inputs: aboveMaxRSIInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.RSI },
+		Track:  []string{"UpdateIndicators"},
+	},
+	"IsAboveMaxSpread": {
+		Func:   IsAboveMaxSpread,
+		Inputs: aboveMaxSpreadInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.Spread },
+	},
+	"IsBelowMinSurge": {
+		Func:   IsBelowMinSurge,
+		Inputs: belowMinSurgeInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.Surge },
+		Track:  []string{"SetSurge"},
+	},
+	"IsBelowMinVolume": {
+		Func:   IsBelowMinVolume,
+		Inputs: belowMinVolumeInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.Volume },
+	},
+	"IsInsideMinTradePause": {
+		Func:   IsInsideMinTradePause,
+		Inputs: insideMinTradePauseInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.Pause },
+	},
+	"IsOutsideMaxCorridor": {
+		Func:   IsOutsideMaxCorridor,
+		Inputs: outsideMaxCorridorInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.Corridor },
+		Track:  []string{"SetCorridorWindow", "SetMaxCorridor"},
+	},
+	"IsOutsidePercentileCorridor": {
+		Func:   IsOutsidePercentileCorridor,
+		Inputs: outsidePercentileCorridorInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.Corridor },
+		Track:  []string{"SetCorridorWindow", "SetPercentileCorridor"},
+	},
+}
+
+// Tracks are the track functions check functions can depend on. Note that the
+// order of the track functions is important, because the track functions
+// partially depend on each other.
+var Tracks = []Track{
+	{Func: UpdateIndicators, Name: "UpdateIndicators"},
+	{Func: SetCorridorWindow, Name: "SetCorridorWindow"},
+	{Func: SetMaxCorridor, Name: "SetMaxCorridor"},
+	{Func: SetPercentileCorridor, Name: "SetPercentileCorridor"},
+	{Func: SetSurge, Name: "SetSurge"},
+}
+
+// DefaultRule returns the rule blocking buy events in case no strategy is
+// configured. The default rule blocks buy events as soon as any of the check
+// functions blocks them.
+func DefaultRule() strategy.Rule {
+	return strategy.Rule{
+		Any: []strategy.Rule{
+			{Check: "IsAboveMaxBuys"},
+			{Check: "IsBelowMinVolume"},
+			{Check: "IsAboveMaxSpread"},
+			{Check: "IsOutsideMaxCorridor"},
+			{Check: "IsOutsidePercentileCorridor"},
+			{Check: "IsAboveMaxRSI"},
+			{Check: "IsAboveBollingerUpper"},
+			{Check: "IsBelowMinSurge"},
+			{Check: "IsInsideMinTradePause"},
+		},
+	}
+}
+
+// ValidateRule returns an error in case the given rule refers to unknown check
+// functions or defines params the check functions do not accept.
+func ValidateRule(rule strategy.Rule) error {
+	_, _, err := newPipeline(rule, config.Config{})
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+// newPipeline returns the track functions the check functions of the given
+// rule depend on, in the order of Tracks. The returned config is the given
+// config having the params of the rule applied and the indicators described
+// by the params declared. Check functions sharing a config section must not be
+// given different params.
+func newPipeline(rule strategy.Rule, c config.Config) ([]TrackFunc, config.Config, error) {
+	names := map[string]bool{}
+	params := map[interface{}]map[string]interface{}{}
+
+	for _, l := range rule.Leaves() {
+		check, ok := Checks[l.Check]
+		if !ok {
+			return nil, config.Config{}, microerror.MaskAnyf(invalidConfigError, "unknown check '%s'", l.Check)
+		}
+		for _, t := range check.Track {
+			names[t] = true
+		}
+
+		if l.Params == nil {
+			continue
+		}
+		if check.Params == nil {
+			return nil, config.Config{}, microerror.MaskAnyf(invalidConfigError, "check '%s' must not define params", l.Check)
+		}
+		section := check.Params(&c)
+		if p, ok := params[section]; ok {
+			if !reflect.DeepEqual(p, l.Params) {
+				return nil, config.Config{}, microerror.MaskAnyf(invalidConfigError, "check '%s' must not define params conflicting with other checks", l.Check)
+			}
+			continue
+		}
+		err := strategy.Decode(l.Params, section)
+		if err != nil {
+			return nil, config.Config{}, microerror.MaskAnyf(invalidConfigError, "check '%s': %s", l.Check, err.Error())
+		}
+		params[section] = l.Params
+	}
+
+	c, err := c.DeclareIndicators()
+	if err != nil {
+		return nil, config.Config{}, microerror.MaskAny(err)
+	}
+
+	var trackFuncs []TrackFunc
+	for _, t := range Tracks {
+		if names[t.Name] {
+			trackFuncs = append(trackFuncs, t.Func)
+		}
+	}
+
+	return trackFuncs, c, nil
+}
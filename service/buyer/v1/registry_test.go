@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	micrologger "github.com/giantswarm/microkit/logger"
+
+	"github.com/xh3b4sd/wafer/service/indicator"
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/strategy"
+)
+
+// Test_Buyer_Block makes sure buy events are blocked according to the
+// configured rule and the params of the rule are applied.
+func Test_Buyer_Block(t *testing.T) {
+	testCases := []struct {
+		Price    informer.Price
+		Expected bool
+	}{
+		// Test case 1, the volume is not below the configured minimum, so the
+		// negated check blocks the buy event.
+		{
+			Price:    informer.Price{Buy: 100, Time: time.Unix(60, 0), Volume: 10},
+			Expected: false,
+		},
+		// Test case 2, the volume is below the configured minimum, so the negated
+		// check does not block the buy event.
+		{
+			Price:    informer.Price{Buy: 100, Time: time.Unix(60, 0), Volume: 1},
+			Expected: true,
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := testConfig(t)
+		config.Runtime.Block = &strategy.Rule{
+			Any: []strategy.Rule{
+				{Check: "IsAboveMaxBuys"},
+				{Not: &strategy.Rule{Check: "IsBelowMinVolume", Params: map[string]interface{}{"min": 5}}},
+			},
+		}
+
+		newBuyer, err := New(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if newBuyer.Runtime().Config.Trade.Volume.Min != 5 {
+			t.Fatal("case", i+1, "expected", 5, "got", newBuyer.Runtime().Config.Trade.Volume.Min)
+		}
+
+		ok, err := newBuyer.Buy(testCase.Price)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ok)
+		}
+	}
+}
+
+// Test_Buyer_Block_Track makes sure only the track functions the check
+// functions of the configured rule depend on are executed.
+func Test_Buyer_Block_Track(t *testing.T) {
+	config := testConfig(t)
+	config.Runtime.Block = &strategy.Rule{Check: "IsAboveMaxBuys"}
+	config.Runtime.Trade.Corridor.Window = time.Hour
+
+	newBuyer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	_, err = newBuyer.Buy(informer.Price{Buy: 100, Time: time.Unix(60, 0)})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if newBuyer.Runtime().State.Trade.Corridor.Window != nil {
+		t.Fatal("expected", nil, "got", newBuyer.Runtime().State.Trade.Corridor.Window)
+	}
+}
+
+// Test_Buyer_Block_Indicators makes sure the check functions reading from
+// indicators can be configured by params, without declaring the indicators
+// upfront.
+func Test_Buyer_Block_Indicators(t *testing.T) {
+	config := testConfig(t)
+	config.Runtime.Block = &strategy.Rule{
+		Any: []strategy.Rule{
+			{Check: "IsAboveMaxRSI", Params: map[string]interface{}{"max": 70, "period": 14}},
+			{Check: "IsAboveBollingerUpper", Params: map[string]interface{}{"k": 2, "period": 20}},
+		},
+	}
+
+	newBuyer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	c := newBuyer.Runtime().Config
+	if c.Trade.RSI.Indicator != "rsi14" {
+		t.Fatal("expected", "rsi14", "got", c.Trade.RSI.Indicator)
+	}
+	if c.Indicators["rsi14"].Kind != indicator.KindRSI {
+		t.Fatal("expected", indicator.KindRSI, "got", c.Indicators["rsi14"].Kind)
+	}
+	if c.Trade.Bollinger.Indicator != "bollinger20" {
+		t.Fatal("expected", "bollinger20", "got", c.Trade.Bollinger.Indicator)
+	}
+	if c.Indicators["bollinger20"].K != 2 {
+		t.Fatal("expected", 2, "got", c.Indicators["bollinger20"].K)
+	}
+}
+
+// Test_Buyer_Block_Invalid makes sure rules referring to unknown check
+// functions or defining invalid params are rejected.
+func Test_Buyer_Block_Invalid(t *testing.T) {
+	testCases := []strategy.Rule{
+		// Test case 1, the check function is unknown.
+		{Check: "IsUnknown"},
+		// Test case 2, the check function does not accept params.
+		{Check: "IsAboveMaxBuys", Params: map[string]interface{}{"max": 1}},
+		// Test case 3, the param is unknown.
+		{Check: "IsBelowMinVolume", Params: map[string]interface{}{"max": 1}},
+		// Test case 4, the params of the shared config section conflict.
+		{
+			Any: []strategy.Rule{
+				{Check: "IsOutsideMaxCorridor", Params: map[string]interface{}{"max": 95}},
+				{Check: "IsOutsidePercentileCorridor", Params: map[string]interface{}{"max": 90}},
+			},
+		},
+		// Test case 5, the params result in an invalid config.
+		{Check: "IsBelowMinSurge", Params: map[string]interface{}{"min": 2}},
+		// Test case 6, the described indicator misses its bands.
+		{Check: "IsAboveBollingerUpper", Params: map[string]interface{}{"period": 20}},
+	}
+
+	for i, testCase := range testCases {
+		config := testConfig(t)
+		config.Runtime.Block = &testCase
+
+		_, err := New(config)
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
+
+func testConfig(t *testing.T) Config {
+	newLogger, err := micrologger.New(micrologger.DefaultConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	config := DefaultConfig()
+	config.Logger = newLogger
+	config.Runtime.Trade.Corridor.Max = 100
+	config.Runtime.Trade.Pause.Min = time.Hour
+
+	return config
+}
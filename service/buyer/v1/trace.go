@@ -0,0 +1,41 @@
+package v1
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/buyer/runtime"
+	"github.com/xh3b4sd/wafer/service/strategy"
+	"github.com/xh3b4sd/wafer/service/trace"
+)
+
+// traceChecks evaluates each check function of the given rule once, even if
+// the rule refers to it multiple times. traceChecks returns the descriptions of
+// the check functions in the order they appear in the rule, together with
+// their verdicts by name. Other than the evaluation of the rule itself, all
+// check functions are evaluated, so that traced decisions show every reason
+// for blocking.
+func traceChecks(rule strategy.Rule, r runtime.Runtime) ([]trace.Check, map[string]bool, error) {
+	var checks []trace.Check
+	verdicts := map[string]bool{}
+
+	for _, l := range rule.Leaves() {
+		if _, ok := verdicts[l.Check]; ok {
+			continue
+		}
+
+		c := Checks[l.Check]
+		blocked, err := c.Func(r)
+		if err != nil {
+			return nil, nil, microerror.MaskAny(err)
+		}
+		verdicts[l.Check] = blocked
+
+		checks = append(checks, trace.Check{
+			Blocked: blocked,
+			Inputs:  c.Inputs(r),
+			Name:    l.Check,
+		})
+	}
+
+	return checks, verdicts, nil
+}
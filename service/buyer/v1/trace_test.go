@@ -0,0 +1,70 @@
+package v1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/strategy"
+	"github.com/xh3b4sd/wafer/service/trace"
+)
+
+// Test_Buyer_Trace makes sure traced decisions record the verdicts and inputs
+// of all check functions of the rule.
+func Test_Buyer_Trace(t *testing.T) {
+	newTracer, err := trace.New(trace.DefaultConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	config := testConfig(t)
+	config.Runtime.Block = &strategy.Rule{
+		Any: []strategy.Rule{
+			{Check: "IsBelowMinVolume", Params: map[string]interface{}{"min": 5}},
+			{Check: "IsAboveMaxBuys"},
+		},
+	}
+	config.Tracer = newTracer
+
+	newBuyer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	prices := []informer.Price{
+		{Buy: 100, Time: time.Unix(60, 0), Volume: 1},
+		{Buy: 100, Time: time.Unix(120, 0), Volume: 10},
+	}
+	for _, p := range prices {
+		_, err := newBuyer.Buy(p)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	expected := []trace.Record{
+		{
+			Checks: []trace.Check{
+				{Blocked: true, Inputs: map[string]interface{}{"min": 5.0, "volume": 1.0}, Name: "IsBelowMinVolume"},
+				{Blocked: false, Inputs: map[string]interface{}{"concurrent": 0, "max": 3}, Name: "IsAboveMaxBuys"},
+			},
+			Fired: false,
+			Kind:  trace.KindBuy,
+			Price: prices[0],
+		},
+		{
+			Checks: []trace.Check{
+				{Blocked: false, Inputs: map[string]interface{}{"min": 5.0, "volume": 10.0}, Name: "IsBelowMinVolume"},
+				{Blocked: false, Inputs: map[string]interface{}{"concurrent": 0, "max": 3}, Name: "IsAboveMaxBuys"},
+			},
+			Fired: true,
+			Kind:  trace.KindBuy,
+			Price: prices[1],
+		},
+	}
+	records := newTracer.Records()
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatal("expected", expected, "got", records)
+	}
+}
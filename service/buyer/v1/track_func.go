@@ -1,7 +1,11 @@
 package v1
 
 import (
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/buyer/rolling"
 	"github.com/xh3b4sd/wafer/service/buyer/runtime"
+	"github.com/xh3b4sd/wafer/service/indicator"
 	"github.com/xh3b4sd/wafer/service/informer"
 )
 
@@ -17,10 +21,111 @@ func NewSetCurrentPrice(p informer.Price) TrackFunc {
 	}
 }
 
+// SetCorridorWindow implements TrackFunc to add the current price to the
+// rolling window of observed prices, in case a window is configured.
+func SetCorridorWindow(r runtime.Runtime) (runtime.Runtime, error) {
+	if r.Config.Trade.Corridor.Window == 0 {
+		return r, nil
+	}
+
+	if r.State.Trade.Corridor.Window == nil {
+		r.State.Trade.Corridor.Window = rolling.New(r.Config.Trade.Corridor.Window)
+	}
+
+	current := r.State.Trade.Price.Current
+	r.State.Trade.Corridor.Window.Add(current.Time, current.Buy)
+
+	return r, nil
+}
+
+// SetMaxCorridor implements TrackFunc to set the highest price observed within
+// the configured window. Without window, the highest price ever observed is
+// set.
 func SetMaxCorridor(r runtime.Runtime) (runtime.Runtime, error) {
+	if r.State.Trade.Corridor.Window != nil {
+		r.State.Trade.Corridor.Max = r.State.Trade.Corridor.Window.Max()
+		return r, nil
+	}
+
 	if r.State.Trade.Corridor.Max < r.State.Trade.Price.Current.Buy {
 		r.State.Trade.Corridor.Max = r.State.Trade.Price.Current.Buy
 	}
 
 	return r, nil
 }
+
+// SetPercentileCorridor implements TrackFunc to set the prices at the
+// configured percentile bounds of the prices observed within the configured
+// window.
+func SetPercentileCorridor(r runtime.Runtime) (runtime.Runtime, error) {
+	w := r.State.Trade.Corridor.Window
+	if w == nil {
+		return r, nil
+	}
+
+	r.State.Trade.Corridor.Percentile.Max = w.Percentile(r.Config.Trade.Corridor.Percentile.Max)
+	r.State.Trade.Corridor.Percentile.Min = w.Percentile(r.Config.Trade.Corridor.Percentile.Min)
+
+	return r, nil
+}
+
+// UpdateIndicators implements TrackFunc to update the configured indicators
+// with the current price.
+func UpdateIndicators(r runtime.Runtime) (runtime.Runtime, error) {
+	if r.State.Indicators == nil {
+		s, err := indicator.NewSet(r.Config.Indicators)
+		if err != nil {
+			return runtime.Runtime{}, microerror.MaskAny(err)
+		}
+		r.State.Indicators = s
+	}
+
+	r.State.Indicators.Update(r.State.Trade.Price.Current)
+
+	return r, nil
+}
+
+// SetSurge implements TrackFunc to track the rate of change of the buy price
+// within the configured window, the consecutive higher highs and the average
+// traded volume, in case a surge is configured. The highs of candles are their
+// high prices. The highs of raw price events are their buy prices.
+func SetSurge(r runtime.Runtime) (runtime.Runtime, error) {
+	if r.Config.Trade.Surge.Min == 0 {
+		return r, nil
+	}
+
+	if r.State.Trade.Surge.Queue == nil {
+		r.State.Trade.Surge.Queue = rolling.NewQueue(r.Config.Trade.Surge.Window)
+	}
+
+	q := r.State.Trade.Surge.Queue
+	current := r.State.Trade.Price.Current
+
+	if q.Len() != 0 && high(current) > high(q.Last()) {
+		r.State.Trade.Surge.HigherHighs++
+	} else {
+		r.State.Trade.Surge.HigherHighs = 0
+	}
+
+	// The average volume is taken before the current price event joins the
+	// window, so that the current volume is compared against the volumes in
+	// front of it.
+	r.State.Trade.Surge.Volume = q.Volume()
+
+	q.Add(current)
+
+	r.State.Trade.Surge.ROC = 0
+	if first := q.First(); first.Buy != 0 {
+		r.State.Trade.Surge.ROC = (current.Buy - first.Buy) / first.Buy * 100
+	}
+
+	return r, nil
+}
+
+func high(p informer.Price) float64 {
+	if p.Candle.Ticks > 0 {
+		return p.Candle.High
+	}
+
+	return p.Buy
+}
@@ -9,12 +9,16 @@ import (
 	"github.com/xh3b4sd/wafer/service/buyer/runtime/config"
 	"github.com/xh3b4sd/wafer/service/buyer/runtime/state"
 	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/strategy"
+	"github.com/xh3b4sd/wafer/service/trace"
 )
 
 // Config is the configuration used to create a new buyer.
 type Config struct {
 	// Dependencies.
 	Logger micrologger.Logger
+	// Tracer is optional. In case Tracer is nil, decisions are not recorded.
+	Tracer *trace.Tracer
 
 	// Settings.
 	Runtime config.Config
@@ -29,6 +33,7 @@ func DefaultConfig() Config {
 	config := Config{
 		// Dependencies.
 		Logger: nil,
+		Tracer: nil,
 
 		// Settings.
 		Runtime: runtimeConfig,
@@ -50,15 +55,36 @@ func New(config Config) (buyer.Buyer, error) {
 		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
 	}
 
+	// The check functions of the rule blocking buy events decide which track
+	// functions have to be executed. The params of the rule are applied to the
+	// runtime config, so they are validated like any other configuration.
+	block := DefaultRule()
+	if config.Runtime.Block != nil {
+		block = *config.Runtime.Block
+	}
+	trackFuncs, runtimeConfig, err := newPipeline(block, config.Runtime)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	err = runtimeConfig.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+
 	newBuyer := &Buyer{
 		// Dependencies.
 		logger: config.Logger,
+		tracer: config.Tracer,
 
 		// Internals.
+		block:  block,
+		chart:  "",
+		charts: map[string]state.State{},
 		runtime: runtime.Runtime{
-			Config: config.Runtime,
+			Config: runtimeConfig,
 			State:  state.State{},
 		},
+		trackFuncs: trackFuncs,
 	}
 
 	return newBuyer, nil
@@ -68,19 +94,25 @@ func New(config Config) (buyer.Buyer, error) {
 type Buyer struct {
 	// Dependencies.
 	logger micrologger.Logger
+	tracer *trace.Tracer
 
 	// Internals.
-	runtime runtime.Runtime
+	block strategy.Rule
+	// chart is the ID of the chart the state of the runtime belongs to.
+	chart string
+	// charts holds the states of the charts other than chart.
+	charts     map[string]state.State
+	runtime    runtime.Runtime
+	trackFuncs []TrackFunc
 }
 
 func (b *Buyer) Buy(price informer.Price) (bool, error) {
+	b.switchChart(price.Chart)
+
 	// Here we want to track the state of the current situation before we execute
-	// the check functions. Note that the order of these functions is important.
-	// because the track functions partially on each other.
-	beforeTrackFuncs := []TrackFunc{
-		NewSetCurrentPrice(price),
-		SetMaxCorridor,
-	}
+	// the check functions. The current price is always tracked. The other track
+	// functions are the ones the check functions of the rule depend on.
+	beforeTrackFuncs := append([]TrackFunc{NewSetCurrentPrice(price)}, b.trackFuncs...)
 
 	for _, t := range beforeTrackFuncs {
 		r, err := t(b.runtime)
@@ -90,22 +122,43 @@ func (b *Buyer) Buy(price informer.Price) (bool, error) {
 		b.runtime = r
 	}
 
-	checkFuns := []CheckFunc{
-		IsAboveMaxBuys,
-		IsOutsideMaxCorridor,
-		IsInsideMinTradePause,
+	check := func(name string) (bool, error) {
+		return Checks[name].Func(b.runtime)
 	}
 
-	for _, c := range checkFuns {
-		ok, err := c(b.runtime)
+	// In case the decision is traced, all check functions of the rule are
+	// evaluated upfront to record their verdicts. The rule is then evaluated
+	// on the recorded verdicts.
+	var checks []trace.Check
+	traced := b.tracer != nil && b.tracer.Sample(trace.KindBuy)
+	if traced {
+		var verdicts map[string]bool
+		var err error
+		checks, verdicts, err = traceChecks(b.block, b.runtime)
 		if err != nil {
 			return false, microerror.MaskAny(err)
 		}
-		if ok {
-			return false, nil
+		check = func(name string) (bool, error) {
+			return verdicts[name], nil
 		}
 	}
 
+	ok, err := b.block.Eval(check)
+	if err != nil {
+		return false, microerror.MaskAny(err)
+	}
+	if traced {
+		b.tracer.Add(trace.Record{
+			Checks: checks,
+			Fired:  !ok,
+			Kind:   trace.KindBuy,
+			Price:  price,
+		})
+	}
+	if ok {
+		return false, nil
+	}
+
 	// state tracking
 	b.runtime.State.Trade.Concurrent++
 	b.runtime.State.Trade.Price.Last = b.runtime.State.Trade.Price.Current
@@ -117,6 +170,30 @@ func (b *Buyer) DecrTradeConcurrent() {
 	b.runtime.State.Trade.Concurrent--
 }
 
+// Runtime returns the runtime of the buyer. The state of the runtime is the
+// state of the chart of the price event the buyer judged last.
 func (b *Buyer) Runtime() runtime.Runtime {
 	return b.runtime
 }
+
+// switchChart makes the state of the runtime the state of the chart having the
+// given ID. The state of each chart is tracked on its own, so that the price
+// events of charts merged into a single stream do not affect each other. E.g.
+// the corridor of one market must not block buy events on another market.
+// Only the number of concurrent buy events is shared across charts, because
+// all charts share the same budget.
+func (b *Buyer) switchChart(chart string) {
+	if chart == b.chart {
+		return
+	}
+
+	concurrent := b.runtime.State.Trade.Concurrent
+	b.charts[b.chart] = b.runtime.State
+
+	s := b.charts[chart]
+	delete(b.charts, chart)
+	s.Trade.Concurrent = concurrent
+
+	b.chart = chart
+	b.runtime.State = s
+}
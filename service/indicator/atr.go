@@ -0,0 +1,62 @@
+package indicator
+
+import (
+	"math"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// ATR is the average true range of prices, using Wilder's smoothing. The true
+// range of a candle is its high minus its low, extended to the close of the
+// previous candle. Raw price events are treated as candles whose high, low and
+// close are their buy price. The average is seeded with the simple average of
+// the first period true ranges.
+type ATR struct {
+	close   float64
+	count   int
+	period  int
+	started bool
+	value   float64
+}
+
+// NewATR creates a new average true range covering the given period, e.g. 14.
+func NewATR(period int) *ATR {
+	return &ATR{
+		period: period,
+	}
+}
+
+func (a *ATR) Ready() bool {
+	return a.count >= a.period
+}
+
+func (a *ATR) Update(p informer.Price) {
+	high, low := p.Buy, p.Buy
+	if p.Candle.Ticks > 0 {
+		high, low = p.Candle.High, p.Candle.Low
+	}
+
+	tr := high - low
+	if a.started {
+		tr = math.Max(tr, math.Max(math.Abs(high-a.close), math.Abs(low-a.close)))
+	}
+	a.close = p.Buy
+	a.started = true
+	a.count++
+
+	if a.count <= a.period {
+		a.value += (tr - a.value) / float64(a.count)
+		return
+	}
+
+	n := float64(a.period)
+	a.value = (a.value*(n-1) + tr) / n
+}
+
+func (a *ATR) Value() float64 {
+	if !a.Ready() {
+		return 0
+	}
+
+	return a.value
+}
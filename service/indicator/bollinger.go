@@ -0,0 +1,45 @@
+package indicator
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// Bollinger are the Bollinger bands of prices. The middle band is the simple
+// moving average of the latest period prices. The upper and lower bands are k
+// standard deviations above and below the middle band.
+type Bollinger struct {
+	k      float64
+	stddev *StdDev
+}
+
+// NewBollinger creates new Bollinger bands covering the given period, having
+// their upper and lower bands k standard deviations away from the middle band.
+func NewBollinger(period int, k float64) *Bollinger {
+	return &Bollinger{
+		k:      k,
+		stddev: NewStdDev(period),
+	}
+}
+
+// Lower returns the lower band.
+func (b *Bollinger) Lower() float64 {
+	return b.stddev.Mean() - b.k*b.stddev.Value()
+}
+
+func (b *Bollinger) Ready() bool {
+	return b.stddev.Ready()
+}
+
+func (b *Bollinger) Update(p informer.Price) {
+	b.stddev.Update(p)
+}
+
+// Upper returns the upper band.
+func (b *Bollinger) Upper() float64 {
+	return b.stddev.Mean() + b.k*b.stddev.Value()
+}
+
+// Value returns the middle band.
+func (b *Bollinger) Value() float64 {
+	return b.stddev.Mean()
+}
@@ -0,0 +1,126 @@
+package indicator
+
+import (
+	"fmt"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+const (
+	// KindATR is the kind of the average true range.
+	KindATR = "atr"
+	// KindBollinger is the kind of the Bollinger bands.
+	KindBollinger = "bollinger"
+	// KindEMA is the kind of the exponential moving average.
+	KindEMA = "ema"
+	// KindMACD is the kind of the moving average convergence divergence.
+	KindMACD = "macd"
+	// KindROC is the kind of the rate of change.
+	KindROC = "roc"
+	// KindRSI is the kind of the relative strength index.
+	KindRSI = "rsi"
+	// KindSMA is the kind of the simple moving average.
+	KindSMA = "sma"
+	// KindStdDev is the kind of the rolling standard deviation.
+	KindStdDev = "stddev"
+)
+
+// Config describes an indicator to create. Consider the following
+// configurations.
+//
+//     {"kind": "rsi", "period": 14}
+//     {"kind": "bollinger", "period": 20, "k": 2}
+//     {"kind": "macd", "fast": 12, "slow": 26, "signal": 9}
+//
+type Config struct {
+	// Fast is the period of the fast moving average of KindMACD.
+	Fast int `json:"fast"`
+	// K is the number of standard deviations the bands of KindBollinger are
+	// away from the middle band.
+	K float64 `json:"k"`
+	// Kind is the kind of the indicator. One of the Kind constants.
+	Kind string `json:"kind"`
+	// Period is the number of price events the indicator covers. Period is
+	// used by all kinds except KindMACD.
+	Period int `json:"period"`
+	// Signal is the period of the signal line of KindMACD.
+	Signal int `json:"signal"`
+	// Slow is the period of the slow moving average of KindMACD.
+	Slow int `json:"slow"`
+}
+
+func (c Config) Validate() error {
+	switch c.Kind {
+	case KindMACD:
+		if c.Fast <= 0 || c.Slow <= 0 || c.Signal <= 0 {
+			return microerror.MaskAnyf(invalidConfigError, "Config.Fast, Config.Slow and Config.Signal must be greater than 0")
+		}
+		if c.Fast >= c.Slow {
+			return microerror.MaskAnyf(invalidConfigError, "Config.Fast must be less than Config.Slow")
+		}
+	case KindBollinger:
+		if c.K <= 0 {
+			return microerror.MaskAnyf(invalidConfigError, "Config.K must be greater than 0")
+		}
+		fallthrough
+	case KindATR, KindEMA, KindROC, KindRSI, KindSMA, KindStdDev:
+		if c.Period <= 0 {
+			return microerror.MaskAnyf(invalidConfigError, "Config.Period must be greater than 0")
+		}
+	default:
+		return microerror.MaskAnyf(invalidConfigError, "Config.Kind must not be '%s'", c.Kind)
+	}
+
+	return nil
+}
+
+// Declare returns a copy of the given indicators having the given indicator
+// declared. In case name is empty, the indicator is named after its kind and
+// period, e.g. rsi14. The name of the declared indicator is returned as well.
+// Declare returns an error in case another indicator is already declared
+// under the same name.
+func Declare(indicators map[string]Config, name string, config Config) (map[string]Config, string, error) {
+	if name == "" {
+		name = fmt.Sprintf("%s%d", config.Kind, config.Period)
+	}
+
+	i, ok := indicators[name]
+	if ok && i != config {
+		return nil, "", microerror.MaskAnyf(invalidConfigError, "indicator '%s' must not be declared differently", name)
+	}
+
+	declared := map[string]Config{}
+	for n, i := range indicators {
+		declared[n] = i
+	}
+	declared[name] = config
+
+	return declared, name, nil
+}
+
+// New creates a new indicator as described by the given config.
+func New(config Config) (Indicator, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	switch config.Kind {
+	case KindATR:
+		return NewATR(config.Period), nil
+	case KindBollinger:
+		return NewBollinger(config.Period, config.K), nil
+	case KindEMA:
+		return NewEMA(config.Period), nil
+	case KindMACD:
+		return NewMACD(config.Fast, config.Slow, config.Signal), nil
+	case KindROC:
+		return NewROC(config.Period), nil
+	case KindRSI:
+		return NewRSI(config.Period), nil
+	case KindSMA:
+		return NewSMA(config.Period), nil
+	default:
+		return NewStdDev(config.Period), nil
+	}
+}
@@ -0,0 +1,52 @@
+package indicator
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// EMA is the exponential moving average of prices. The smoothing factor is
+// 2/(period+1). The average is seeded with the simple moving average of the
+// first period prices.
+type EMA struct {
+	alpha  float64
+	count  int
+	period int
+	value  float64
+}
+
+// NewEMA creates a new exponential moving average covering the given period.
+func NewEMA(period int) *EMA {
+	return &EMA{
+		alpha:  2 / float64(period+1),
+		period: period,
+	}
+}
+
+func (e *EMA) Ready() bool {
+	return e.count >= e.period
+}
+
+func (e *EMA) Update(p informer.Price) {
+	e.add(p.Buy)
+}
+
+func (e *EMA) Value() float64 {
+	if !e.Ready() {
+		return 0
+	}
+
+	return e.value
+}
+
+// add updates the average with the given value. add is used by indicators
+// averaging values other than prices, like MACD.
+func (e *EMA) add(v float64) {
+	e.count++
+
+	if e.count <= e.period {
+		e.value += (v - e.value) / float64(e.count)
+		return
+	}
+
+	e.value += e.alpha * (v - e.value)
+}
@@ -0,0 +1,317 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// testChart is the chart used by StockCharts to explain the calculation of the
+// RSI. The expected RSI values below are the ones published there.
+var testChart = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+	45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64,
+}
+
+func testPrices(values []float64) []informer.Price {
+	var prices []informer.Price
+	for _, v := range values {
+		prices = append(prices, informer.Price{Buy: v})
+	}
+
+	return prices
+}
+
+func equal(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// Test_Indicator_Value makes sure indicators become ready after the expected
+// number of price events and provide the expected values from then on.
+func Test_Indicator_Value(t *testing.T) {
+	testCases := []struct {
+		Indicator Indicator
+		Prices    []informer.Price
+		Ready     int
+		Expected  []float64
+	}{
+		// Test case 1, SMA.
+		{
+			Indicator: NewSMA(5),
+			Prices:    testPrices(testChart[:8]),
+			Ready:     4,
+			Expected:  []float64{44.104, 44.202, 44.404, 44.658},
+		},
+		// Test case 2, EMA seeded with the SMA.
+		{
+			Indicator: NewEMA(5),
+			Prices:    testPrices(testChart[:8]),
+			Ready:     4,
+			Expected:  []float64{44.104, 44.346, 44.59733333333333, 44.87155555555555},
+		},
+		// Test case 3, RSI as published by StockCharts.
+		{
+			Indicator: NewRSI(14),
+			Prices:    testPrices(testChart),
+			Ready:     14,
+			Expected:  []float64{70.46413502109704, 66.24961855355505, 66.48094183471265, 69.34685316290866, 66.29471265892624, 57.91502067008556},
+		},
+		// Test case 4, RSI of rising prices only.
+		{
+			Indicator: NewRSI(2),
+			Prices:    testPrices([]float64{1, 2, 3}),
+			Ready:     2,
+			Expected:  []float64{100},
+		},
+		// Test case 5, MACD line.
+		{
+			Indicator: NewMACD(3, 6, 4),
+			Prices:    testPrices(testChart[:11]),
+			Ready:     8,
+			Expected:  []float64{0.41375744047618923, 0.4259093324829948, 0.32869081784499343},
+		},
+		// Test case 6, rolling standard deviation.
+		{
+			Indicator: NewStdDev(5),
+			Prices:    testPrices(testChart[:8]),
+			Ready:     4,
+			Expected:  []float64{0.2657517638699699, 0.39407613477600933, 0.5227465925283497, 0.6342680821230098},
+		},
+		// Test case 7, the middle band of the Bollinger bands.
+		{
+			Indicator: NewBollinger(8, 2),
+			Prices:    testPrices([]float64{2, 4, 4, 4, 5, 5, 7, 9}),
+			Ready:     7,
+			Expected:  []float64{5},
+		},
+		// Test case 8, rate of change.
+		{
+			Indicator: NewROC(3),
+			Prices:    testPrices(testChart[:6]),
+			Ready:     3,
+			Expected:  []float64{-1.6463689670726294, 0.5443411204354612, 1.5402038505096256},
+		},
+		// Test case 9, ATR of candles respecting the previous close.
+		{
+			Indicator: NewATR(3),
+			Prices: []informer.Price{
+				{Buy: 9, Candle: informer.Candle{Close: 9, High: 10, Low: 8, Ticks: 1}},
+				{Buy: 10, Candle: informer.Candle{Close: 10, High: 11, Low: 9, Ticks: 1}},
+				{Buy: 11, Candle: informer.Candle{Close: 11, High: 12, Low: 9, Ticks: 1}},
+				{Buy: 8, Candle: informer.Candle{Close: 8, High: 11, Low: 8, Ticks: 1}},
+				{Buy: 8, Candle: informer.Candle{Close: 8, High: 9, Low: 7, Ticks: 1}},
+			},
+			Ready:    2,
+			Expected: []float64{7.0 / 3.0, 23.0 / 9.0, 64.0 / 27.0},
+		},
+		// Test case 10, ATR of raw price events.
+		{
+			Indicator: NewATR(2),
+			Prices:    testPrices([]float64{10, 12, 11, 15}),
+			Ready:     1,
+			Expected:  []float64{1, 1, 2.5},
+		},
+	}
+
+	for i, testCase := range testCases {
+		for j, p := range testCase.Prices {
+			testCase.Indicator.Update(p)
+
+			if testCase.Indicator.Ready() != (j >= testCase.Ready) {
+				t.Fatal("case", i+1, "index", j, "expected", j >= testCase.Ready, "got", testCase.Indicator.Ready())
+			}
+			if j < testCase.Ready {
+				if testCase.Indicator.Value() != 0 {
+					t.Fatal("case", i+1, "index", j, "expected", 0, "got", testCase.Indicator.Value())
+				}
+				continue
+			}
+
+			e := testCase.Expected[j-testCase.Ready]
+			if !equal(testCase.Indicator.Value(), e) {
+				t.Fatal("case", i+1, "index", j, "expected", e, "got", testCase.Indicator.Value())
+			}
+		}
+	}
+}
+
+func Test_MACD_Signal(t *testing.T) {
+	testCases := []struct {
+		Signal    float64
+		Histogram float64
+	}{
+		{Signal: 0.3328404017857096, Histogram: 0.08091703869047961},
+		{Signal: 0.37006797406462366, Histogram: 0.05584135841837112},
+		{Signal: 0.35351711157677157, Histogram: -0.024826293731778137},
+	}
+
+	m := NewMACD(3, 6, 4)
+	for _, p := range testPrices(testChart[:9]) {
+		m.Update(p)
+	}
+
+	for i, testCase := range testCases {
+		if i > 0 {
+			m.Update(informer.Price{Buy: testChart[8+i]})
+		}
+
+		if !equal(m.Signal(), testCase.Signal) {
+			t.Fatal("case", i+1, "expected", testCase.Signal, "got", m.Signal())
+		}
+		if !equal(m.Histogram(), testCase.Histogram) {
+			t.Fatal("case", i+1, "expected", testCase.Histogram, "got", m.Histogram())
+		}
+	}
+}
+
+func Test_Bollinger_Bands(t *testing.T) {
+	b := NewBollinger(8, 2)
+	for _, p := range testPrices([]float64{2, 4, 4, 4, 5, 5, 7, 9}) {
+		b.Update(p)
+	}
+
+	if !equal(b.Upper(), 9) {
+		t.Fatal("expected", 9, "got", b.Upper())
+	}
+	if !equal(b.Lower(), 1) {
+		t.Fatal("expected", 1, "got", b.Lower())
+	}
+}
+
+// Test_StdDev_Long makes sure the incremental standard deviation does not drift
+// away from the standard deviation calculated from scratch on long charts.
+func Test_StdDev_Long(t *testing.T) {
+	s := NewStdDev(20)
+
+	var values []float64
+	for i := 0; i < 100000; i++ {
+		v := 10000 + math.Sin(float64(i))*5
+		values = append(values, v)
+		s.Update(informer.Price{Buy: v})
+	}
+
+	window := values[len(values)-20:]
+	var mean, variance float64
+	for _, v := range window {
+		mean += v / 20
+	}
+	for _, v := range window {
+		variance += (v - mean) * (v - mean) / 20
+	}
+
+	if math.Abs(s.Value()-math.Sqrt(variance)) > 1e-6 {
+		t.Fatal("expected", math.Sqrt(variance), "got", s.Value())
+	}
+}
+
+// Test_Set_Update makes sure sets update their indicators only once per price
+// event.
+func Test_Set_Update(t *testing.T) {
+	s, err := NewSet(map[string]Config{
+		"sma": {Kind: KindSMA, Period: 2},
+	})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for _, p := range testPrices([]float64{1, 3, 3, 5}) {
+		s.Update(p)
+		s.Update(p)
+	}
+
+	i, ok := s.Get("sma")
+	if !ok {
+		t.Fatal("expected", true, "got", false)
+	}
+	if i.Value() != 4 {
+		t.Fatal("expected", 4, "got", i.Value())
+	}
+}
+
+func Test_Config_Validate(t *testing.T) {
+	testCases := []struct {
+		Config   Config
+		Expected bool
+	}{
+		{Config: Config{Kind: KindRSI, Period: 14}, Expected: true},
+		{Config: Config{Kind: KindRSI}, Expected: false},
+		{Config: Config{Kind: KindBollinger, Period: 20}, Expected: false},
+		{Config: Config{Kind: KindBollinger, Period: 20, K: 2}, Expected: true},
+		{Config: Config{Kind: KindMACD, Fast: 26, Slow: 12, Signal: 9}, Expected: false},
+		{Config: Config{Kind: KindMACD, Fast: 12, Slow: 26, Signal: 9}, Expected: true},
+		{Config: Config{Kind: "foo", Period: 1}, Expected: false},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.Config.Validate()
+		if (err == nil) != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", err)
+		}
+	}
+}
+
+func Test_Declare(t *testing.T) {
+	rsi14 := Config{Kind: KindRSI, Period: 14}
+	rsi7 := Config{Kind: KindRSI, Period: 7}
+
+	testCases := []struct {
+		Indicators   map[string]Config
+		Name         string
+		Config       Config
+		ExpectedName string
+		ErrorMatcher func(error) bool
+	}{
+		// Test case 1, unnamed indicators are named after their kind and period.
+		{
+			Indicators:   nil,
+			Name:         "",
+			Config:       rsi14,
+			ExpectedName: "rsi14",
+			ErrorMatcher: nil,
+		},
+		// Test case 2, indicators declared the same way are shared.
+		{
+			Indicators:   map[string]Config{"rsi14": rsi14},
+			Name:         "",
+			Config:       rsi14,
+			ExpectedName: "rsi14",
+			ErrorMatcher: nil,
+		},
+		// Test case 3, named indicators keep their name.
+		{
+			Indicators:   map[string]Config{"rsi14": rsi14},
+			Name:         "fast",
+			Config:       rsi7,
+			ExpectedName: "fast",
+			ErrorMatcher: nil,
+		},
+		// Test case 4, indicators must not be declared differently.
+		{
+			Indicators:   map[string]Config{"fast": rsi14},
+			Name:         "fast",
+			Config:       rsi7,
+			ExpectedName: "",
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		indicators, name, err := Declare(testCase.Indicators, testCase.Name, testCase.Config)
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if name != testCase.ExpectedName {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedName, "got", name)
+		}
+		if indicators[name] != testCase.Config {
+			t.Fatal("case", i+1, "expected", testCase.Config, "got", indicators[name])
+		}
+	}
+}
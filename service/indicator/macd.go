@@ -0,0 +1,60 @@
+package indicator
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// MACD is the moving average convergence divergence of prices. The MACD line
+// is the difference between the fast and the slow exponential moving average.
+// The signal line is the exponential moving average of the MACD line.
+type MACD struct {
+	fast   *EMA
+	signal *EMA
+	slow   *EMA
+}
+
+// NewMACD creates a new MACD using the given periods, e.g. 12, 26 and 9.
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{
+		fast:   NewEMA(fast),
+		signal: NewEMA(signal),
+		slow:   NewEMA(slow),
+	}
+}
+
+// Histogram returns the difference between the MACD line and the signal line.
+func (m *MACD) Histogram() float64 {
+	if !m.Ready() {
+		return 0
+	}
+
+	return m.Value() - m.Signal()
+}
+
+// Ready returns true as soon as the signal line is available.
+func (m *MACD) Ready() bool {
+	return m.signal.Ready()
+}
+
+// Signal returns the signal line.
+func (m *MACD) Signal() float64 {
+	return m.signal.Value()
+}
+
+func (m *MACD) Update(p informer.Price) {
+	m.fast.Update(p)
+	m.slow.Update(p)
+
+	if m.slow.Ready() {
+		m.signal.add(m.fast.Value() - m.slow.Value())
+	}
+}
+
+// Value returns the MACD line.
+func (m *MACD) Value() float64 {
+	if !m.Ready() {
+		return 0
+	}
+
+	return m.fast.Value() - m.slow.Value()
+}
@@ -0,0 +1,48 @@
+package indicator
+
+// ring is a fixed size ring buffer of the latest values added.
+type ring struct {
+	index  int
+	len    int
+	values []float64
+}
+
+func newRing(size int) *ring {
+	return &ring{
+		values: make([]float64, size),
+	}
+}
+
+// Add adds the given value. In case the ring is full, the oldest value is
+// replaced and returned together with true.
+func (r *ring) Add(v float64) (float64, bool) {
+	old := r.values[r.index]
+	full := r.Full()
+
+	r.values[r.index] = v
+	r.index = (r.index + 1) % len(r.values)
+	if !full {
+		r.len++
+	}
+
+	return old, full
+}
+
+// Full returns true in case the ring holds as many values as it can.
+func (r *ring) Full() bool {
+	return r.len == len(r.values)
+}
+
+// Len returns the number of values the ring holds.
+func (r *ring) Len() int {
+	return r.len
+}
+
+// Oldest returns the oldest value the ring holds.
+func (r *ring) Oldest() float64 {
+	if !r.Full() {
+		return r.values[0]
+	}
+
+	return r.values[r.index]
+}
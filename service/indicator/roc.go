@@ -0,0 +1,36 @@
+package indicator
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// ROC is the rate of change of prices. It is the change of the current price
+// relative to the price period price events ago, in percent.
+type ROC struct {
+	ring *ring
+	last float64
+}
+
+// NewROC creates a new rate of change covering the given period.
+func NewROC(period int) *ROC {
+	return &ROC{
+		ring: newRing(period + 1),
+	}
+}
+
+func (r *ROC) Ready() bool {
+	return r.ring.Full()
+}
+
+func (r *ROC) Update(p informer.Price) {
+	r.ring.Add(p.Buy)
+	r.last = p.Buy
+}
+
+func (r *ROC) Value() float64 {
+	if !r.Ready() || r.ring.Oldest() == 0 {
+		return 0
+	}
+
+	return (r.last - r.ring.Oldest()) / r.ring.Oldest() * 100
+}
@@ -0,0 +1,68 @@
+package indicator
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// RSI is the relative strength index of prices, using Wilder's smoothing. The
+// average gain and loss are seeded with the simple averages of the first
+// period price changes.
+type RSI struct {
+	count   int
+	gain    float64
+	last    float64
+	loss    float64
+	period  int
+	started bool
+}
+
+// NewRSI creates a new relative strength index covering the given period,
+// e.g. 14.
+func NewRSI(period int) *RSI {
+	return &RSI{
+		period: period,
+	}
+}
+
+func (r *RSI) Ready() bool {
+	return r.count >= r.period
+}
+
+func (r *RSI) Update(p informer.Price) {
+	if !r.started {
+		r.last = p.Buy
+		r.started = true
+		return
+	}
+
+	var gain, loss float64
+	if change := p.Buy - r.last; change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+	r.last = p.Buy
+	r.count++
+
+	if r.count <= r.period {
+		r.gain += gain / float64(r.period)
+		r.loss += loss / float64(r.period)
+		return
+	}
+
+	n := float64(r.period)
+	r.gain = (r.gain*(n-1) + gain) / n
+	r.loss = (r.loss*(n-1) + loss) / n
+}
+
+// Value returns the relative strength index within [0, 100].
+func (r *RSI) Value() float64 {
+	if !r.Ready() {
+		return 0
+	}
+	if r.loss == 0 {
+		return 100
+	}
+
+	return 100 - 100/(1+r.gain/r.loss)
+}
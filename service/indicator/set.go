@@ -0,0 +1,55 @@
+package indicator
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// Set holds named indicators which are updated together.
+type Set struct {
+	indicators map[string]Indicator
+	last       informer.Price
+	updated    bool
+}
+
+// NewSet creates a new set holding an indicator for each of the given named
+// configs.
+func NewSet(configs map[string]Config) (*Set, error) {
+	s := &Set{
+		indicators: map[string]Indicator{},
+	}
+
+	for name, c := range configs {
+		i, err := New(c)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, "indicator '%s': %s", name, err.Error())
+		}
+		s.indicators[name] = i
+	}
+
+	return s, nil
+}
+
+// Get returns the indicator having the given name. Get returns false in case
+// the set does not hold such an indicator.
+func (s *Set) Get(name string) (Indicator, bool) {
+	i, ok := s.indicators[name]
+	return i, ok
+}
+
+// Update updates all indicators of the set with the given price event. The
+// price event the set was updated with last is ignored, so that consumers
+// judging the same price event multiple times update the set only once.
+func (s *Set) Update(p informer.Price) {
+	if s.updated && p == s.last {
+		return
+	}
+
+	for _, i := range s.indicators {
+		i.Update(p)
+	}
+
+	s.last = p
+	s.updated = true
+}
@@ -0,0 +1,39 @@
+package indicator
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// SMA is the simple moving average of the prices of the latest period price
+// events.
+type SMA struct {
+	ring *ring
+	sum  float64
+}
+
+// NewSMA creates a new simple moving average covering the given period.
+func NewSMA(period int) *SMA {
+	return &SMA{
+		ring: newRing(period),
+	}
+}
+
+func (s *SMA) Ready() bool {
+	return s.ring.Full()
+}
+
+func (s *SMA) Update(p informer.Price) {
+	old, full := s.ring.Add(p.Buy)
+	if full {
+		s.sum -= old
+	}
+	s.sum += p.Buy
+}
+
+func (s *SMA) Value() float64 {
+	if !s.Ready() {
+		return 0
+	}
+
+	return s.sum / float64(s.ring.Len())
+}
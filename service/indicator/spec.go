@@ -0,0 +1,28 @@
+// Package indicator provides streaming technical indicators. Each indicator is
+// updated with one price event after the other and keeps only the state it
+// needs to provide its current value. Updating an indicator takes constant
+// time, regardless of the period it covers, so indicators can be tracked along
+// charts of any length.
+//
+// Indicators are calculated from the buy prices of price events. Price events
+// resampled into candles provide their close price as buy price, and their high
+// and low prices are respected by indicators measuring price ranges, like ATR.
+package indicator
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// Indicator is a technical indicator being updated with one price event after
+// the other. An indicator must not be used concurrently.
+type Indicator interface {
+	// Ready returns true as soon as the indicator was updated with enough price
+	// events to provide meaningful values.
+	Ready() bool
+	// Update updates the indicator with the given price event. Price events
+	// are expected in time order.
+	Update(p informer.Price)
+	// Value returns the current value of the indicator. Value returns 0 as long
+	// as the indicator is not ready.
+	Value() float64
+}
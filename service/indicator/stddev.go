@@ -0,0 +1,61 @@
+package indicator
+
+import (
+	"math"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// StdDev is the rolling population standard deviation of the prices of the
+// latest period price events. Mean and variance are updated incrementally
+// using Welford's method, which stays numerically stable on long charts.
+type StdDev struct {
+	m2   float64
+	mean float64
+	ring *ring
+}
+
+// NewStdDev creates a new rolling standard deviation covering the given
+// period.
+func NewStdDev(period int) *StdDev {
+	return &StdDev{
+		ring: newRing(period),
+	}
+}
+
+// Mean returns the mean of the prices within the period.
+func (s *StdDev) Mean() float64 {
+	if !s.Ready() {
+		return 0
+	}
+
+	return s.mean
+}
+
+func (s *StdDev) Ready() bool {
+	return s.ring.Full()
+}
+
+func (s *StdDev) Update(p informer.Price) {
+	v := p.Buy
+
+	old, full := s.ring.Add(v)
+	if !full {
+		delta := v - s.mean
+		s.mean += delta / float64(s.ring.Len())
+		s.m2 += delta * (v - s.mean)
+		return
+	}
+
+	mean := s.mean + (v-old)/float64(s.ring.Len())
+	s.m2 += (v - old) * (v - mean + old - s.mean)
+	s.mean = mean
+}
+
+func (s *StdDev) Value() float64 {
+	if !s.Ready() {
+		return 0
+	}
+
+	return math.Sqrt(math.Max(0, s.m2/float64(s.ring.Len())))
+}
@@ -0,0 +1,53 @@
+// Package backoff provides the description of how failed operations of
+// informers talking to live sources are retried, e.g. polling a ticker endpoint
+// or connecting to a WebSocket endpoint.
+package backoff
+
+import (
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Backoff describes how failed operations are retried. The delay between two
+// retries starts at Initial and doubles with each retry, until it reaches Max.
+type Backoff struct {
+	// Initial is the delay before the first retry.
+	Initial time.Duration
+	// Max is the maximum delay between two retries.
+	Max time.Duration
+	// Retries is the number of consecutive retries of a failed operation. In
+	// case the operation still fails after all retries, it fails with the error
+	// of the last attempt. Zero disables retries.
+	Retries int
+}
+
+// Delay returns the delay before the retry following the given attempt. The
+// first attempt is 0.
+func (b Backoff) Delay(attempt int) time.Duration {
+	d := b.Initial
+	for i := 0; i < attempt && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+
+	return d
+}
+
+// Validate returns an error in case the delays are not positive or the number
+// of retries is negative.
+func (b Backoff) Validate() error {
+	if b.Initial <= 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Backoff.Initial must be greater than 0")
+	}
+	if b.Max < b.Initial {
+		return microerror.MaskAnyf(invalidConfigError, "Backoff.Max must not be less than Backoff.Initial")
+	}
+	if b.Retries < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Backoff.Retries must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Backoff_Delay(t *testing.T) {
+	b := Backoff{
+		Initial: time.Second,
+		Max:     5 * time.Second,
+		Retries: 10,
+	}
+
+	testCases := []struct {
+		Attempt  int
+		Expected time.Duration
+	}{
+		{Attempt: 0, Expected: time.Second},
+		{Attempt: 1, Expected: 2 * time.Second},
+		{Attempt: 2, Expected: 4 * time.Second},
+		{Attempt: 3, Expected: 5 * time.Second},
+		{Attempt: 100, Expected: 5 * time.Second},
+	}
+
+	for i, testCase := range testCases {
+		d := b.Delay(testCase.Attempt)
+		if d != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", d)
+		}
+	}
+}
+
+func Test_Backoff_Validate(t *testing.T) {
+	testCases := []struct {
+		Backoff      Backoff
+		ErrorMatcher func(error) bool
+	}{
+		{
+			Backoff:      Backoff{Initial: time.Second, Max: time.Second, Retries: 0},
+			ErrorMatcher: nil,
+		},
+		{
+			Backoff:      Backoff{Initial: 0, Max: time.Second, Retries: 1},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Backoff:      Backoff{Initial: time.Second, Max: time.Millisecond, Retries: 1},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Backoff:      Backoff{Initial: time.Second, Max: time.Second, Retries: -1},
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.Backoff.Validate()
+		if testCase.ErrorMatcher == nil && err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if testCase.ErrorMatcher != nil && !testCase.ErrorMatcher(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+}
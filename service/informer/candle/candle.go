@@ -0,0 +1,102 @@
+// Package candle provides the implementation of an informer which wraps
+// another informer to resample its price events into candles of a fixed
+// interval. That way buyers and sellers can judge e.g. 5 minute candles instead
+// of every single raw price event of noisy stock markets.
+package candle
+
+import (
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+)
+
+// Config is the configuration used to create a new informer.
+type Config struct {
+	// Dependencies.
+	Informer informer.Informer
+
+	// Settings.
+
+	// Interval is the duration each candle covers, e.g. 1m, 5m or 1h.
+	Interval time.Duration
+}
+
+// DefaultConfig returns the default configuration used to create a new informer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Dependencies.
+		Informer: nil,
+
+		// Settings.
+		Interval: time.Minute,
+	}
+}
+
+// New creates a new configured informer.
+func New(config Config) (informer.Informer, error) {
+	// Dependencies.
+	if config.Informer == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Informer must not be empty")
+	}
+
+	// Settings.
+	if config.Interval <= 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Interval must be greater than 0")
+	}
+
+	newInformer := &Informer{
+		// Dependencies.
+		informer: config.Informer,
+
+		// Settings.
+		interval: config.Interval,
+	}
+
+	return newInformer, nil
+}
+
+// Informer implements informer.Informer.
+type Informer struct {
+	// Dependencies.
+	informer informer.Informer
+
+	// Settings.
+	interval time.Duration
+}
+
+// Endless returns true in case the wrapped informer is endless.
+func (i *Informer) Endless() bool {
+	return informer.IsEndless(i.informer)
+}
+
+// Prices returns a list of iterators providing candles instead of raw price
+// events. Each candle aggregates the buy prices of all price events within its
+// interval. The buy and sell prices of a candle are the prices of the last
+// price event within its interval. The volume of a candle is the sum of the
+// volumes of all price events within its interval. The time of a candle is the
+// time of the last price event within its interval, because that is when the
+// candle is known. Intervals without any price event do not produce candles.
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	iterators, err := i.informer.Prices(ctx)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	var candles []informer.Iterator
+	for _, it := range iterators {
+		candles = append(candles, newIterator(it, i.interval))
+	}
+
+	return candles, nil
+}
+
+// Runtime returns the runtime of the wrapped informer. Note that the number of
+// events reported there refers to the raw price events, not to the candles.
+func (i *Informer) Runtime() runtime.Runtime {
+	return i.informer.Runtime()
+}
@@ -0,0 +1,125 @@
+package candle
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/memory"
+)
+
+func Test_Informer_Prices(t *testing.T) {
+	testCases := []struct {
+		Interval time.Duration
+		Chart    []informer.Price
+		Expected []informer.Price
+	}{
+		// Test case 1 makes sure raw price events are aggregated into candles and
+		// intervals without price events are skipped. Candles are stamped with
+		// the time of their last price event and keep their chart.
+		{
+			Interval: time.Minute,
+			Chart: []informer.Price{
+				{Buy: 10, Chart: "btc", Sell: 9, Time: time.Unix(60, 0), Volume: 1},
+				{Buy: 12, Chart: "btc", Sell: 11, Time: time.Unix(70, 0), Volume: 2},
+				{Buy: 8, Chart: "btc", Sell: 7, Time: time.Unix(80, 0), Volume: 3},
+				{Buy: 11, Chart: "btc", Sell: 10, Time: time.Unix(119, 0), Volume: 4},
+				{Buy: 20, Chart: "btc", Sell: 19, Time: time.Unix(240, 0), Volume: 5},
+			},
+			Expected: []informer.Price{
+				{
+					Buy:    11,
+					Candle: informer.Candle{Close: 11, High: 12, Low: 8, Open: 10, Ticks: 4},
+					Chart:  "btc",
+					Sell:   10,
+					Time:   time.Unix(119, 0),
+					Volume: 10,
+				},
+				{
+					Buy:    20,
+					Candle: informer.Candle{Close: 20, High: 20, Low: 20, Open: 20, Ticks: 1},
+					Chart:  "btc",
+					Sell:   19,
+					Time:   time.Unix(240, 0),
+					Volume: 5,
+				},
+			},
+		},
+		// Test case 2 makes sure candles can be resampled into bigger candles.
+		{
+			Interval: 2 * time.Minute,
+			Chart: []informer.Price{
+				{
+					Buy:    11,
+					Candle: informer.Candle{Close: 11, High: 12, Low: 8, Open: 10, Ticks: 4},
+					Sell:   10,
+					Time:   time.Unix(120, 0),
+				},
+				{
+					Buy:    14,
+					Candle: informer.Candle{Close: 14, High: 15, Low: 9, Open: 11, Ticks: 3},
+					Sell:   13,
+					Time:   time.Unix(180, 0),
+				},
+			},
+			Expected: []informer.Price{
+				{
+					Buy:    14,
+					Candle: informer.Candle{Close: 14, High: 15, Low: 8, Open: 10, Ticks: 7},
+					Sell:   13,
+					Time:   time.Unix(180, 0),
+				},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		var newInformer informer.Informer
+		{
+			config := memory.DefaultConfig()
+			config.Charts = [][]informer.Price{testCase.Chart}
+			memoryInformer, err := memory.New(config)
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+
+			candleConfig := DefaultConfig()
+			candleConfig.Informer = memoryInformer
+			candleConfig.Interval = testCase.Interval
+			newInformer, err = New(candleConfig)
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+		}
+
+		iterators, err := newInformer.Prices(context.Background())
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		var prices []informer.Price
+		for iterators[0].Next() {
+			prices = append(prices, iterators[0].Price())
+		}
+		if iterators[0].Err() != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", iterators[0].Err())
+		}
+
+		if len(prices) != len(testCase.Expected) {
+			t.Fatal("case", i+1, "expected", len(testCase.Expected), "got", len(prices))
+		}
+		for j, p := range prices {
+			e := testCase.Expected[j]
+			if !p.Time.Equal(e.Time) {
+				t.Fatal("case", i+1, "expected", e.Time, "got", p.Time)
+			}
+			p.Time = e.Time
+			if !reflect.DeepEqual(p, e) {
+				t.Fatal("case", i+1, "expected", e, "got", p)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package candle
+
+import (
+	"math"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// iterator implements informer.Iterator to aggregate the price events of
+// another iterator into candles.
+type iterator struct {
+	err      error
+	interval time.Duration
+	iterator informer.Iterator
+	price    informer.Price
+
+	// current is the candle being aggregated, but not yet handed out.
+	current    informer.Price
+	hasCurrent bool
+	// start is the start of the interval of the current candle.
+	start time.Time
+}
+
+func newIterator(it informer.Iterator, interval time.Duration) *iterator {
+	return &iterator{
+		interval: interval,
+		iterator: it,
+	}
+}
+
+func (i *iterator) Close() error {
+	err := i.iterator.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+func (i *iterator) Err() error {
+	return i.err
+}
+
+func (i *iterator) Next() bool {
+	for i.iterator.Next() {
+		p := i.iterator.Price()
+		start := p.Time.Truncate(i.interval)
+
+		if !i.hasCurrent {
+			i.current = newCandle(p)
+			i.hasCurrent = true
+			i.start = start
+			continue
+		}
+
+		// Price events belonging to the current interval, or arriving late, are
+		// aggregated into the current candle.
+		if !start.After(i.start) {
+			i.current = mergeCandle(i.current, p)
+			continue
+		}
+
+		i.price = i.current
+		i.current = newCandle(p)
+		i.start = start
+
+		return true
+	}
+
+	err := i.iterator.Err()
+	if err != nil {
+		i.err = microerror.MaskAny(err)
+		i.hasCurrent = false
+		return false
+	}
+
+	// Once the wrapped iterator is exhausted, the last candle is handed out,
+	// even if its interval might not be complete.
+	if i.hasCurrent {
+		i.price = i.current
+		i.hasCurrent = false
+		return true
+	}
+
+	return false
+}
+
+func (i *iterator) Price() informer.Price {
+	return i.price
+}
+
+// candleOf returns the candle the given price event represents. In case the
+// price event is not already a candle, it is treated as a candle of a single
+// tick.
+func candleOf(p informer.Price) informer.Candle {
+	if p.Candle.Ticks != 0 {
+		return p.Candle
+	}
+
+	c := informer.Candle{
+		Close: p.Buy,
+		High:  p.Buy,
+		Low:   p.Buy,
+		Open:  p.Buy,
+		Ticks: 1,
+	}
+
+	return c
+}
+
+func mergeCandle(current, p informer.Price) informer.Price {
+	c := candleOf(p)
+
+	current.Buy = p.Buy
+	current.Candle.Close = c.Close
+	current.Candle.High = math.Max(current.Candle.High, c.High)
+	current.Candle.Low = math.Min(current.Candle.Low, c.Low)
+	current.Candle.Ticks += c.Ticks
+	current.Sell = p.Sell
+	current.Volume += p.Volume
+
+	// The candle is known as soon as its last price event is known. Price
+	// events arriving late do not move the candle back in time.
+	if p.Time.After(current.Time) {
+		current.Time = p.Time
+	}
+
+	return current
+}
+
+func newCandle(p informer.Price) informer.Price {
+	current := informer.Price{
+		Buy:    p.Buy,
+		Candle: candleOf(p),
+		Chart:  p.Chart,
+		Sell:   p.Sell,
+		Time:   p.Time,
+		Volume: p.Volume,
+	}
+
+	return current
+}
@@ -0,0 +1,401 @@
+// Package chartcache provides a compact binary cache of the price events of
+// chart files. Parsing the rows of large CSV files takes considerably longer
+// than reading their values from a cache, which matters when the same charts
+// are loaded over and over again. The cache is stored beside its chart file,
+// e.g. chart.bin beside chart.csv, and memory mapped while being read.
+//
+// The cache is laid out in rows. A fixed size header is followed by one row per
+// price event, holding the price time as unix nanoseconds, the buy price, the
+// sell price and optionally the traded volume. All values are little endian
+// encoded 64 bit values. Rows are written while the chart file is read, so
+// that building a cache does not hold the whole chart in memory.
+//
+//     | header | time | buy | sell | volume | time | buy | sell | volume | ...
+//
+// The header records the size and modification time of the chart file the
+// cache was built from, together with a fingerprint of the settings the chart
+// file was parsed with. A cache is stale as soon as any of them changed.
+package chartcache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/chartfile"
+)
+
+const (
+	// Ext is the file extension of cache files.
+	Ext = ".bin"
+)
+
+const (
+	// magic identifies cache files.
+	magic = "WAFERBIN"
+	// version is the version of the cache layout. Caches of other versions are
+	// considered stale.
+	version uint32 = 2
+)
+
+const (
+	// flagVolume marks caches providing traded volumes.
+	flagVolume uint32 = 1 << iota
+)
+
+// headerSize is the size of the encoded header in bytes.
+const headerSize = 8 + 4 + 4 + 8 + 8 + 8 + sha256.Size + checksumSize
+
+// checksumSize is the size of the hex encoded SHA-256 checksum of the content
+// of the chart file the cache was built from.
+const checksumSize = 2 * sha256.Size
+
+// IsTemp returns true in case the given file name is the name of a temporary
+// file a cache is written to before it replaces the cache file, e.g.
+// .chart.bin.123456.
+func IsTemp(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, Ext+".")
+}
+
+// Path returns the location of the cache of the given chart file. The cache
+// of chart.csv and chart.csv.gz is chart.bin within the same dir.
+func Path(chart string) string {
+	base := chartfile.Base(chart)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+
+	return filepath.Join(filepath.Dir(chart), base+Ext)
+}
+
+// Stamp identifies the state of a chart file a cache is built from.
+type Stamp struct {
+	// Fingerprint is the SHA-256 checksum of the settings the chart file is
+	// parsed with.
+	Fingerprint [sha256.Size]byte
+	// ModTime is the modification time of the chart file.
+	ModTime time.Time
+	// Size is the size of the chart file in bytes.
+	Size int64
+}
+
+// NewStamp returns the stamp of the chart file at the given path. The given
+// settings are JSON encoded to calculate the fingerprint of the stamp.
+func NewStamp(path string, settings interface{}) (Stamp, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return Stamp{}, microerror.MaskAny(err)
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return Stamp{}, microerror.MaskAny(err)
+	}
+
+	s := Stamp{
+		Fingerprint: sha256.Sum256(b),
+		ModTime:     fi.ModTime(),
+		Size:        fi.Size(),
+	}
+
+	return s, nil
+}
+
+// header is the decoded header of a cache file.
+type header struct {
+	Checksum string
+	Flags    uint32
+	Rows     int
+	Stamp    Stamp
+}
+
+func (h header) encode() []byte {
+	b := make([]byte, headerSize)
+
+	o := copy(b, magic)
+	binary.LittleEndian.PutUint32(b[o:], version)
+	o += 4
+	binary.LittleEndian.PutUint32(b[o:], h.Flags)
+	o += 4
+	binary.LittleEndian.PutUint64(b[o:], uint64(h.Rows))
+	o += 8
+	binary.LittleEndian.PutUint64(b[o:], uint64(h.Stamp.Size))
+	o += 8
+	binary.LittleEndian.PutUint64(b[o:], uint64(h.Stamp.ModTime.UnixNano()))
+	o += 8
+	o += copy(b[o:], h.Stamp.Fingerprint[:])
+	copy(b[o:], h.Checksum)
+
+	return b
+}
+
+func decodeHeader(path string, b []byte) (header, error) {
+	if len(b) < headerSize || string(b[:len(magic)]) != magic {
+		return header{}, microerror.MaskAnyf(malformedCacheError, "%s: missing cache header", path)
+	}
+
+	o := len(magic)
+	if binary.LittleEndian.Uint32(b[o:]) != version {
+		return header{}, microerror.MaskAnyf(staleCacheError, "%s: cache version %d not supported", path, binary.LittleEndian.Uint32(b[o:]))
+	}
+	o += 4
+
+	var h header
+	h.Flags = binary.LittleEndian.Uint32(b[o:])
+	o += 4
+	h.Rows = int(binary.LittleEndian.Uint64(b[o:]))
+	o += 8
+	h.Stamp.Size = int64(binary.LittleEndian.Uint64(b[o:]))
+	o += 8
+	h.Stamp.ModTime = time.Unix(0, int64(binary.LittleEndian.Uint64(b[o:])))
+	o += 8
+	o += copy(h.Stamp.Fingerprint[:], b[o:])
+	h.Checksum = string(bytes.TrimRight(b[o:o+checksumSize], "\x00"))
+
+	return h, nil
+}
+
+// columns returns the number of columns of caches having the given flags.
+func columns(flags uint32) int {
+	if flags&flagVolume != 0 {
+		return 4
+	}
+
+	return 3
+}
+
+// Writer streams price events into a temporary file, which replaces the cache
+// file once all price events are written.
+type Writer struct {
+	buffer  *bufio.Writer
+	file    *os.File
+	path    string
+	rows    int
+	volume  bool
+	written bool
+}
+
+// NewWriter creates a new writer for the cache file at the given path. Volume
+// decides whether the traded volumes of price events are written as well. Note
+// that the temporary file of the returned writer has to be removed by calling
+// Close in case Write is not called.
+func NewWriter(path string, volume bool) (*Writer, error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	newWriter := &Writer{
+		buffer: bufio.NewWriter(f),
+		file:   f,
+		path:   path,
+		volume: volume,
+	}
+
+	// The header is written once all price events are written, because it
+	// records their number. Until then its space is reserved.
+	_, err = newWriter.buffer.Write(make([]byte, headerSize))
+	if err != nil {
+		newWriter.Close()
+		return nil, microerror.MaskAny(err)
+	}
+
+	return newWriter, nil
+}
+
+// Add writes the given price event to the temporary file.
+func (w *Writer) Add(p informer.Price) error {
+	values := []uint64{
+		uint64(p.Time.UnixNano()),
+		math.Float64bits(p.Buy),
+		math.Float64bits(p.Sell),
+	}
+	if w.volume {
+		values = append(values, math.Float64bits(p.Volume))
+	}
+
+	var v [8]byte
+	for _, u := range values {
+		binary.LittleEndian.PutUint64(v[:], u)
+		_, err := w.buffer.Write(v[:])
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+	}
+	w.rows++
+
+	return nil
+}
+
+// Write writes the header of the cache and replaces the cache file by the
+// temporary file. The given stamp and checksum describe the chart file the
+// price events are read from. The cache file is replaced atomically, so that
+// caches being read concurrently are never seen partially written.
+func (w *Writer) Write(stamp Stamp, checksum string) error {
+	h := header{
+		Checksum: checksum,
+		Rows:     w.rows,
+		Stamp:    stamp,
+	}
+	if w.volume {
+		h.Flags |= flagVolume
+	}
+
+	err := w.buffer.Flush()
+	if err != nil {
+		w.Close()
+		return microerror.MaskAny(err)
+	}
+	_, err = w.file.WriteAt(h.encode(), 0)
+	if err != nil {
+		w.Close()
+		return microerror.MaskAny(err)
+	}
+	err = w.file.Close()
+	if err != nil {
+		os.Remove(w.file.Name())
+		return microerror.MaskAny(err)
+	}
+	err = os.Rename(w.file.Name(), w.path)
+	if err != nil {
+		os.Remove(w.file.Name())
+		return microerror.MaskAny(err)
+	}
+	w.written = true
+
+	return nil
+}
+
+// Close removes the temporary file in case the cache file was not written.
+// Close does nothing after Write succeeded.
+func (w *Writer) Close() error {
+	if w.written {
+		return nil
+	}
+
+	w.file.Close()
+	err := os.Remove(w.file.Name())
+	if err != nil && !os.IsNotExist(err) {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+// Cache provides the price events of a cache file.
+type Cache struct {
+	checksum string
+	columns  int
+	data     []byte
+	rows     int
+	unmap    func() error
+	volume   bool
+}
+
+// Open memory maps the cache file at the given path. Caches not matching the
+// given stamp cause a staleCacheError. Note that the returned cache has to be
+// closed by the caller.
+func Open(path string, stamp Stamp) (*Cache, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	if fi.Size() < headerSize {
+		return nil, microerror.MaskAnyf(malformedCacheError, "%s: missing cache header", path)
+	}
+
+	data, unmap, err := mmap(f, int(fi.Size()))
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	h, err := decodeHeader(path, data)
+	if err != nil {
+		unmap()
+		return nil, microerror.MaskAny(err)
+	}
+	if h.Stamp.Size != stamp.Size || !h.Stamp.ModTime.Equal(stamp.ModTime) || h.Stamp.Fingerprint != stamp.Fingerprint {
+		unmap()
+		return nil, microerror.MaskAnyf(staleCacheError, "%s: chart changed since the cache was built", path)
+	}
+	if len(data) != headerSize+8*h.Rows*columns(h.Flags) {
+		unmap()
+		return nil, microerror.MaskAnyf(malformedCacheError, "%s: expected %d rows", path, h.Rows)
+	}
+
+	newCache := &Cache{
+		checksum: h.Checksum,
+		columns:  columns(h.Flags),
+		data:     data,
+		rows:     h.Rows,
+		unmap:    unmap,
+		volume:   h.Flags&flagVolume != 0,
+	}
+
+	return newCache, nil
+}
+
+// Checksum returns the checksum of the chart file the cache was built from.
+func (c *Cache) Checksum() string {
+	return c.checksum
+}
+
+// Close unmaps the cache file. Price must not be called anymore afterwards.
+func (c *Cache) Close() error {
+	if c.unmap == nil {
+		return nil
+	}
+
+	err := c.unmap()
+	c.data = nil
+	c.unmap = nil
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+// Len returns the number of price events within the cache.
+func (c *Cache) Len() int {
+	return c.rows
+}
+
+// Price returns the price event at the given index. Price times are provided
+// in the local time zone.
+func (c *Cache) Price(i int) informer.Price {
+	p := informer.Price{
+		Buy:  c.float(1, i),
+		Sell: c.float(2, i),
+		Time: time.Unix(0, int64(c.value(0, i))),
+	}
+	if c.volume {
+		p.Volume = c.float(3, i)
+	}
+
+	return p
+}
+
+func (c *Cache) float(column, i int) float64 {
+	return math.Float64frombits(c.value(column, i))
+}
+
+func (c *Cache) value(column, i int) uint64 {
+	o := headerSize + 8*(i*c.columns+column)
+	return binary.LittleEndian.Uint64(c.data[o:])
+}
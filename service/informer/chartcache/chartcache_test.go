@@ -0,0 +1,226 @@
+package chartcache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+func Test_Path(t *testing.T) {
+	testCases := []struct {
+		Chart    string
+		Expected string
+	}{
+		{
+			Chart:    "/charts/001/chart.csv",
+			Expected: "/charts/001/chart.bin",
+		},
+		{
+			Chart:    "/charts/001/chart.csv.gz",
+			Expected: "/charts/001/chart.bin",
+		},
+		{
+			Chart:    "/charts/002.csv",
+			Expected: "/charts/002.bin",
+		},
+	}
+
+	for i, testCase := range testCases {
+		path := Path(testCase.Chart)
+		if path != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", path)
+		}
+	}
+}
+
+func testChart(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "wafer-chartcache")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	chart := filepath.Join(dir, "chart.csv")
+	err = ioutil.WriteFile(chart, []byte("1,1,1\n2,2,2\n"), 0644)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	return chart, func() { os.RemoveAll(dir) }
+}
+
+// Test_Writer_Open makes sure price events written to a cache are read back
+// unchanged.
+func Test_Writer_Open(t *testing.T) {
+	chart, cleanup := testChart(t)
+	defer cleanup()
+
+	stamp, err := NewStamp(chart, map[string]string{"time": "unix"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for _, volume := range []bool{false, true} {
+		prices := []informer.Price{
+			{Buy: 1.5, Sell: 1.25, Time: time.Unix(1, 0), Volume: 3},
+			{Buy: 2.5, Sell: 2.25, Time: time.Unix(2, 500), Volume: 4},
+			{Buy: 3.5, Sell: 3.25, Time: time.Unix(3, 0), Volume: 5},
+		}
+
+		w, err := NewWriter(Path(chart), volume)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		for _, p := range prices {
+			err := w.Add(p)
+			if err != nil {
+				t.Fatal("expected", nil, "got", err)
+			}
+		}
+		err = w.Write(stamp, "checksum")
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		c, err := Open(Path(chart), stamp)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		if c.Len() != len(prices) {
+			t.Fatal("expected", len(prices), "got", c.Len())
+		}
+		if c.Checksum() != "checksum" {
+			t.Fatal("expected", "checksum", "got", c.Checksum())
+		}
+		for i, p := range prices {
+			if !volume {
+				p.Volume = 0
+			}
+			if !reflect.DeepEqual(c.Price(i), p) {
+				t.Fatal("index", i, "expected", p, "got", c.Price(i))
+			}
+		}
+
+		err = c.Close()
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+}
+
+// Test_Writer_Close makes sure the temporary file of a writer is removed in
+// case the cache is not written.
+func Test_Writer_Close(t *testing.T) {
+	chart, cleanup := testChart(t)
+	defer cleanup()
+
+	w, err := NewWriter(Path(chart), false)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = w.Add(informer.Price{Buy: 1, Sell: 1, Time: time.Unix(1, 0)})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	fis, err := ioutil.ReadDir(filepath.Dir(chart))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(fis) != 1 {
+		t.Fatal("expected", 1, "got", len(fis))
+	}
+}
+
+func Test_IsTemp(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Expected bool
+	}{
+		{Name: ".chart.bin.123456", Expected: true},
+		{Name: "chart.bin", Expected: false},
+		{Name: "chart.csv", Expected: false},
+		{Name: ".hidden", Expected: false},
+	}
+
+	for i, testCase := range testCases {
+		ok := IsTemp(testCase.Name)
+		if ok != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ok)
+		}
+	}
+}
+
+// Test_Open_Stale makes sure caches are considered stale as soon as their
+// chart file or the settings it is parsed with changed.
+func Test_Open_Stale(t *testing.T) {
+	chart, cleanup := testChart(t)
+	defer cleanup()
+
+	stamp, err := NewStamp(chart, map[string]string{"time": "unix"})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	w, err := NewWriter(Path(chart), false)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	err = w.Write(stamp, "")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Modify func(s Stamp) Stamp
+	}{
+		// Test case 1, the chart file got modified.
+		{
+			Modify: func(s Stamp) Stamp {
+				s.ModTime = s.ModTime.Add(time.Second)
+				return s
+			},
+		},
+		// Test case 2, the size of the chart file changed.
+		{
+			Modify: func(s Stamp) Stamp {
+				s.Size++
+				return s
+			},
+		},
+		// Test case 3, the chart file is parsed with other settings.
+		{
+			Modify: func(s Stamp) Stamp {
+				o, err := NewStamp(chart, map[string]string{"time": "unixmilli"})
+				if err != nil {
+					t.Fatal("expected", nil, "got", err)
+				}
+				return o
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		_, err := Open(Path(chart), testCase.Modify(stamp))
+		if !IsStaleCache(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+
+	err = ioutil.WriteFile(Path(chart), []byte("foo"), 0644)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	_, err = Open(Path(chart), stamp)
+	if !IsMalformedCache(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
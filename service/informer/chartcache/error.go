@@ -0,0 +1,19 @@
+package chartcache
+
+import (
+	"github.com/juju/errgo"
+)
+
+var malformedCacheError = errgo.New("malformed cache")
+
+// IsMalformedCache asserts malformedCacheError.
+func IsMalformedCache(err error) bool {
+	return errgo.Cause(err) == malformedCacheError
+}
+
+var staleCacheError = errgo.New("stale cache")
+
+// IsStaleCache asserts staleCacheError.
+func IsStaleCache(err error) bool {
+	return errgo.Cause(err) == staleCacheError
+}
@@ -0,0 +1,26 @@
+//go:build !unix
+
+package chartcache
+
+import (
+	"io"
+	"os"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// mmap reads the content of the given file into memory on platforms not
+// supporting memory mapped files.
+func mmap(f *os.File, size int) ([]byte, func() error, error) {
+	b := make([]byte, size)
+	_, err := io.ReadFull(f, b)
+	if err != nil {
+		return nil, nil, microerror.MaskAny(err)
+	}
+
+	unmap := func() error {
+		return nil
+	}
+
+	return b, unmap, nil
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package chartcache
+
+import (
+	"os"
+	"syscall"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// mmap maps the content of the given file into memory. The returned function
+// unmaps the content again.
+func mmap(f *os.File, size int) ([]byte, func() error, error) {
+	b, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, microerror.MaskAny(err)
+	}
+
+	unmap := func() error {
+		err := syscall.Munmap(b)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+
+		return nil
+	}
+
+	return b, unmap, nil
+}
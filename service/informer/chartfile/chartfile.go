@@ -0,0 +1,117 @@
+// Package chartfile provides access to the content of chart files, regardless
+// of them being compressed or not. The content is decompressed while being
+// read, so compressed charts never have to be decompressed as a whole. While
+// reading, a checksum of the decompressed content is calculated. That way
+// analyses can be traced back to the exact chart data they are based on.
+package chartfile
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+const (
+	// ExtGzip is the file extension of gzip compressed chart files, e.g.
+	// chart.csv.gz.
+	ExtGzip = ".gz"
+	// ExtZstd is the file extension of zstd compressed chart files, e.g.
+	// chart.csv.zst. Note that zstd compressed chart files are recognized but
+	// not yet supported.
+	ExtZstd = ".zst"
+)
+
+// Base returns the name of the given chart file without any compression
+// extension, e.g. chart.csv for chart.csv.gz.
+func Base(name string) string {
+	name = filepath.Base(name)
+
+	for _, ext := range []string{ExtGzip, ExtZstd} {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+
+	return name
+}
+
+// Open opens the chart file at the given path. Files having the ExtGzip
+// extension are decompressed transparently. Note that the returned reader has
+// to be closed by the caller.
+func Open(path string) (*Reader, error) {
+	if strings.HasSuffix(path, ExtZstd) {
+		return nil, microerror.MaskAnyf(unsupportedCompressionError, "%s: zstd compression is not supported, use gzip instead", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	newReader := &Reader{
+		file:   f,
+		gzip:   nil,
+		hash:   sha256.New(),
+		reader: f,
+	}
+
+	if strings.HasSuffix(path, ExtGzip) {
+		g, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, microerror.MaskAnyf(unsupportedCompressionError, "%s: %s", path, err.Error())
+		}
+		newReader.gzip = g
+		newReader.reader = g
+	}
+
+	newReader.reader = io.TeeReader(newReader.reader, newReader.hash)
+
+	return newReader, nil
+}
+
+// Reader provides the decompressed content of a chart file.
+type Reader struct {
+	file   *os.File
+	gzip   *gzip.Reader
+	hash   hash.Hash
+	reader io.Reader
+}
+
+// Checksum returns the hex encoded SHA-256 checksum of the decompressed content
+// read so far. Once the reader returned io.EOF, the checksum covers the whole
+// content of the chart file. Checksum can still be called after the reader was
+// closed.
+func (r *Reader) Checksum() string {
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
+
+// Close closes the underlying chart file.
+func (r *Reader) Close() error {
+	if r.gzip != nil {
+		err := r.gzip.Close()
+		if err != nil {
+			r.file.Close()
+			return microerror.MaskAny(err)
+		}
+	}
+
+	err := r.file.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+// Read reads the decompressed content of the chart file.
+func (r *Reader) Read(p []byte) (int, error) {
+	return r.reader.Read(p)
+}
@@ -0,0 +1,36 @@
+package chartfile
+
+import (
+	"testing"
+)
+
+func Test_Base(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Expected string
+	}{
+		{
+			Name:     "chart.csv",
+			Expected: "chart.csv",
+		},
+		{
+			Name:     "chart.csv.gz",
+			Expected: "chart.csv",
+		},
+		{
+			Name:     "/charts/001/chart.jsonl.zst",
+			Expected: "chart.jsonl",
+		},
+		{
+			Name:     "header.yaml",
+			Expected: "header.yaml",
+		},
+	}
+
+	for i, testCase := range testCases {
+		base := Base(testCase.Name)
+		if base != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", base)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package chartfile
+
+import (
+	"github.com/juju/errgo"
+)
+
+var unsupportedCompressionError = errgo.New("unsupported compression")
+
+// IsUnsupportedCompression asserts unsupportedCompressionError.
+func IsUnsupportedCompression(err error) bool {
+	return errgo.Cause(err) == unsupportedCompressionError
+}
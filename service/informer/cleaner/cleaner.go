@@ -0,0 +1,239 @@
+// Package cleaner provides the application of data quality policies to the
+// price events of a chart. The cleaner is independent of the format charts are
+// stored in, so it can be shared by all informers reading charts from files.
+package cleaner
+
+import (
+	"io"
+	"math"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	configquality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/quality"
+	pricequality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price/quality"
+)
+
+// Source provides the price events of a single chart one after another. Read
+// returns io.EOF in case there are no more price events to read.
+type Source interface {
+	Close() error
+	Read() (informer.Price, error)
+}
+
+// Config is the configuration used to create a new cleaner.
+type Config struct {
+	// Dependencies.
+
+	// Source is the source of the price events to clean. The source is closed
+	// when the cleaner is closed.
+	Source Source
+
+	// Settings.
+
+	// Path is the location of the chart being cleaned. It is used to point to
+	// the affected chart in case price events are rejected.
+	Path string
+	// Quality describes the policies applied to the price events of the chart.
+	Quality configquality.Quality
+}
+
+// DefaultConfig returns the default configuration used to create a new cleaner
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Dependencies.
+		Source: nil,
+
+		// Settings.
+		Path:    "",
+		Quality: configquality.Quality{},
+	}
+}
+
+// New creates a new configured cleaner. In case the sort policy is configured
+// for the order of price events, the whole chart of the given source is read
+// into memory and the source is closed right away.
+func New(config Config) (*Cleaner, error) {
+	// Dependencies.
+	if config.Source == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Source must not be empty")
+	}
+
+	// Settings.
+	err := config.Quality.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "%s: %s", config.Path, err.Error())
+	}
+
+	s := config.Source
+	if config.Quality.Order == configquality.PolicySort {
+		s, err = newSortedSource(s)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	newCleaner := &Cleaner{
+		// Dependencies.
+		source: s,
+
+		// Settings.
+		path:    config.Path,
+		quality: config.Quality,
+
+		// Internals.
+		summary: pricequality.Quality{},
+	}
+
+	return newCleaner, nil
+}
+
+// Cleaner applies the data quality policies of a chart to the price events of
+// another source. While doing so, the cleaner gathers a data quality summary of
+// the chart. Cleaner implements Source itself.
+type Cleaner struct {
+	// Dependencies.
+	source Source
+
+	// Settings.
+	path    string
+	quality configquality.Quality
+
+	// Internals.
+	summary pricequality.Quality
+
+	eof     bool
+	hasLast bool
+	last    informer.Price
+	// pending is the price event held back to be able to remove duplicates in
+	// case the dedupe-keep-last policy is configured.
+	hasPending bool
+	pending    informer.Price
+}
+
+func (c *Cleaner) Close() error {
+	err := c.source.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+func (c *Cleaner) Read() (informer.Price, error) {
+	for {
+		if c.eof {
+			if c.hasPending {
+				c.hasPending = false
+				return c.pending, nil
+			}
+
+			return informer.Price{}, io.EOF
+		}
+
+		p, err := c.source.Read()
+		if err == io.EOF {
+			c.eof = true
+			continue
+		} else if err != nil {
+			return informer.Price{}, microerror.MaskAny(err)
+		}
+
+		ok, err := c.check(p)
+		if err != nil {
+			return informer.Price{}, microerror.MaskAny(err)
+		}
+		if !ok {
+			c.summary.Dropped++
+			continue
+		}
+
+		if c.quality.Duplicate == configquality.PolicyDedupeKeepLast {
+			if c.hasPending && c.pending.Time.Equal(p.Time) {
+				c.summary.Dropped++
+				c.pending = p
+				c.last = p
+				continue
+			}
+
+			pending, hasPending := c.pending, c.hasPending
+			c.hasPending, c.pending = true, p
+			c.hasLast, c.last = true, p
+
+			if hasPending {
+				return pending, nil
+			}
+			continue
+		}
+
+		c.hasLast, c.last = true, p
+
+		return p, nil
+	}
+}
+
+// Summary returns the data quality summary of the price events read so far.
+func (c *Cleaner) Summary() pricequality.Quality {
+	return c.summary
+}
+
+// check applies the data quality policies to the given price event. The
+// returned bool is false in case the price event has to be dropped.
+func (c *Cleaner) check(p informer.Price) (bool, error) {
+	q := c.quality
+
+	if isInvalidPrice(p.Buy) || isInvalidPrice(p.Sell) {
+		c.summary.Invalid++
+
+		switch q.Invalid {
+		case configquality.PolicyReject:
+			return false, c.rejectf("invalid prices %f and %f", p.Buy, p.Sell)
+		case configquality.PolicyDrop:
+			return false, nil
+		}
+	}
+
+	if !c.hasLast {
+		return true, nil
+	}
+
+	if p.Time.Before(c.last.Time) {
+		c.summary.Unordered++
+
+		switch q.Order {
+		case configquality.PolicyReject:
+			return false, c.rejectf("price event at %s before price event at %s", p.Time, c.last.Time)
+		case configquality.PolicyDrop:
+			return false, nil
+		}
+	} else if p.Time.Equal(c.last.Time) {
+		c.summary.Duplicates++
+
+		switch q.Duplicate {
+		case configquality.PolicyReject:
+			return false, c.rejectf("duplicate price event at %s", p.Time)
+		case configquality.PolicyDrop:
+			return false, nil
+		}
+	}
+
+	if q.Gap != 0 && p.Time.Sub(c.last.Time) > q.Gap {
+		c.summary.Gaps++
+
+		switch q.GapPolicy {
+		case configquality.PolicyReject:
+			return false, c.rejectf("gap of %s between %s and %s", p.Time.Sub(c.last.Time), c.last.Time, p.Time)
+		}
+	}
+
+	return true, nil
+}
+
+func (c *Cleaner) rejectf(f string, v ...interface{}) error {
+	return microerror.MaskAnyf(malformedChartError, "%s: "+f, append([]interface{}{c.path}, v...)...)
+}
+
+func isInvalidPrice(f float64) bool {
+	return f <= 0 || math.IsNaN(f) || math.IsInf(f, 0)
+}
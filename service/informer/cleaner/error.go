@@ -0,0 +1,19 @@
+package cleaner
+
+import (
+	"github.com/juju/errgo"
+)
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var malformedChartError = errgo.New("malformed chart")
+
+// IsMalformedChart asserts malformedChartError.
+func IsMalformedChart(err error) bool {
+	return errgo.Cause(err) == malformedChartError
+}
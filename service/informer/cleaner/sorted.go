@@ -0,0 +1,63 @@
+package cleaner
+
+import (
+	"io"
+	"sort"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// sortedSource provides the price events of another source ordered by time.
+// The whole chart of the other source is loaded into memory to do so.
+type sortedSource struct {
+	index  int
+	prices []informer.Price
+}
+
+// newSortedSource reads the given source completely and closes it afterwards.
+func newSortedSource(s Source) (*sortedSource, error) {
+	defer s.Close()
+
+	var prices []informer.Price
+
+	for {
+		p, err := s.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+
+		prices = append(prices, p)
+	}
+
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].Time.Before(prices[j].Time)
+	})
+
+	newSource := &sortedSource{
+		index:  0,
+		prices: prices,
+	}
+
+	return newSource, nil
+}
+
+func (s *sortedSource) Close() error {
+	s.prices = nil
+
+	return nil
+}
+
+func (s *sortedSource) Read() (informer.Price, error) {
+	if s.index >= len(s.prices) {
+		return informer.Price{}, io.EOF
+	}
+
+	p := s.prices[s.index]
+	s.index++
+
+	return p, nil
+}
@@ -0,0 +1,202 @@
+package csv
+
+import (
+	"io"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/chartcache"
+	"github.com/xh3b4sd/wafer/service/informer/csv/column"
+	runtimestatefile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file"
+	"github.com/xh3b4sd/wafer/service/informer/timeformat"
+)
+
+// checksumSource is a source of price events being able to tell the checksum
+// of the chart they are read from.
+type checksumSource interface {
+	Checksum() string
+	Close() error
+	Read() (informer.Price, error)
+}
+
+// cacheStamp returns the stamp identifying the cache of the given file. Only
+// the header settings affecting how rows are parsed are part of the stamp. The
+// quality policies are applied to the price events read from the cache, so
+// changing them does not invalidate the cache.
+func cacheStamp(file runtimestatefile.File) (chartcache.Stamp, error) {
+	settings := struct {
+		Buy        column.Column
+		Ignore     bool
+		Sell       column.Column
+		Time       column.Column
+		TimeFormat string
+		TimeZone   string
+		Volume     *column.Column
+	}{
+		Buy:        file.Header.Buy,
+		Ignore:     file.Header.Ignore,
+		Sell:       file.Header.Sell,
+		Time:       file.Header.Time,
+		TimeFormat: file.Header.TimeFormat,
+		TimeZone:   file.Header.TimeZone,
+		Volume:     file.Header.Volume,
+	}
+
+	s, err := chartcache.NewStamp(file.Path, settings)
+	if err != nil {
+		return chartcache.Stamp{}, microerror.MaskAny(err)
+	}
+
+	return s, nil
+}
+
+// cacheReader reads the price events of a single CSV file from its binary
+// cache instead of parsing the CSV file.
+type cacheReader struct {
+	cache *chartcache.Cache
+	index int
+	time  *timeformat.Parser
+}
+
+// openCache opens the cache of the given file. Caches which do not exist or
+// which are stale cause an error. Note that the returned reader has to be
+// closed by the caller.
+func openCache(file runtimestatefile.File) (*cacheReader, error) {
+	timeParser, err := timeformat.NewParser(file.Header.TimeFormat, file.Header.TimeZone)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	stamp, err := cacheStamp(file)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	c, err := chartcache.Open(chartcache.Path(file.Path), stamp)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	newReader := &cacheReader{
+		cache: c,
+		index: 0,
+		time:  timeParser,
+	}
+
+	return newReader, nil
+}
+
+// Checksum returns the checksum of the CSV file the cache was built from.
+func (r *cacheReader) Checksum() string {
+	return r.cache.Checksum()
+}
+
+// Close closes the underlying cache.
+func (r *cacheReader) Close() error {
+	err := r.cache.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+// Read returns the next price event of the cache. Read returns io.EOF in case
+// there are no more price events to read.
+func (r *cacheReader) Read() (informer.Price, error) {
+	if r.index >= r.cache.Len() {
+		return informer.Price{}, io.EOF
+	}
+
+	p := r.cache.Price(r.index)
+	p.Time = r.time.In(p.Time)
+	r.index++
+
+	return p, nil
+}
+
+// cacheWriter streams the price events read from a CSV file into the cache of
+// the CSV file, which replaces the current cache once the CSV file was read
+// completely.
+type cacheWriter struct {
+	*reader
+
+	done   bool
+	err    error
+	stamp  chartcache.Stamp
+	writer *chartcache.Writer
+}
+
+// newCacheWriter wraps the given reader to stream its price events into the
+// cache. The stamp is taken before the CSV file is read, so that changes made
+// while reading render the written cache stale.
+func newCacheWriter(r *reader) (*cacheWriter, error) {
+	stamp, err := cacheStamp(r.file)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	writer, err := chartcache.NewWriter(chartcache.Path(r.file.Path), r.file.Header.Volume != nil)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	newWriter := &cacheWriter{
+		reader: r,
+
+		done:   false,
+		err:    nil,
+		stamp:  stamp,
+		writer: writer,
+	}
+
+	return newWriter, nil
+}
+
+// Close closes the underlying reader. The cache is discarded in case it was
+// not written.
+func (w *cacheWriter) Close() error {
+	w.writer.Close()
+
+	err := w.reader.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+func (w *cacheWriter) Read() (informer.Price, error) {
+	p, err := w.reader.Read()
+	if err == io.EOF {
+		w.done = true
+		return informer.Price{}, io.EOF
+	} else if err != nil {
+		return informer.Price{}, microerror.MaskAny(err)
+	}
+
+	// Failing to write the cache must not fail reading the CSV file. The cache
+	// is then not written at all.
+	if w.err == nil {
+		w.err = w.writer.Add(p)
+	}
+
+	return p, nil
+}
+
+// Write replaces the cache of the CSV file by the streamed price events. Write
+// does nothing in case the CSV file was not read completely or the price
+// events could not be streamed.
+func (w *cacheWriter) Write() error {
+	if !w.done || w.err != nil {
+		return nil
+	}
+
+	err := w.writer.Write(w.stamp, w.Checksum())
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
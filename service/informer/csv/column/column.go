@@ -0,0 +1,121 @@
+// Package column provides a way to reference columns within CSV files, either
+// by their index or by their name as given in the first row of a CSV file.
+package column
+
+import (
+	"encoding/json"
+	"strconv"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Column references a column within a CSV file. In case Name is empty, the
+// column is referenced by Index. Otherwise the column is referenced by Name,
+// which has to be resolved against the first row of a CSV file.
+type Column struct {
+	// Index is the zero based index of the column.
+	Index int
+	// Name is the name of the column as given in the first row of a CSV file.
+	Name string
+}
+
+// Index returns a column referenced by the given index.
+func Index(i int) Column {
+	return Column{Index: i}
+}
+
+// Name returns a column referenced by the given name.
+func Name(n string) Column {
+	return Column{Name: n}
+}
+
+// Parse returns a column referenced by index in case the given string is an
+// integer. Otherwise the returned column is referenced by name.
+func Parse(s string) Column {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return Name(s)
+	}
+
+	return Index(i)
+}
+
+// IsName returns true in case the column is referenced by name.
+func (c Column) IsName() bool {
+	return c.Name != ""
+}
+
+// MarshalJSON renders the column either as integer or as string, depending on
+// the way the column is referenced.
+func (c Column) MarshalJSON() ([]byte, error) {
+	if c.IsName() {
+		return json.Marshal(c.Name)
+	}
+
+	return json.Marshal(c.Index)
+}
+
+// Resolve returns the index of the column. Columns referenced by name are
+// looked up in the given names, which are usually taken from the first row of a
+// CSV file.
+func (c Column) Resolve(names []string) (int, error) {
+	if !c.IsName() {
+		return c.Index, nil
+	}
+
+	for i, n := range names {
+		if n == c.Name {
+			return i, nil
+		}
+	}
+
+	return 0, microerror.MaskAnyf(notFoundError, "column '%s'", c.Name)
+}
+
+// String returns the index or the name of the column.
+func (c Column) String() string {
+	if c.IsName() {
+		return c.Name
+	}
+
+	return strconv.Itoa(c.Index)
+}
+
+// UnmarshalJSON accepts integers as well as strings.
+func (c *Column) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	switch t := v.(type) {
+	case float64:
+		*c = Index(int(t))
+	case string:
+		*c = Parse(t)
+	default:
+		return microerror.MaskAnyf(invalidConfigError, "column must be integer or string")
+	}
+
+	return nil
+}
+
+// UnmarshalYAML accepts integers as well as strings. That way header.yaml files
+// can reference columns like this.
+//
+//     buy: 9
+//     sell: sell
+//     time: server_time
+//
+func (c *Column) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	err := unmarshal(&s)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	*c = Parse(s)
+
+	return nil
+}
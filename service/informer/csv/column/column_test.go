@@ -0,0 +1,93 @@
+package column
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func Test_Column_Resolve(t *testing.T) {
+	names := []string{"id", "buy", "sell", "server_time"}
+
+	testCases := []struct {
+		Column       Column
+		Expected     int
+		ErrorMatcher func(err error) bool
+	}{
+		// Test case 1 makes sure columns referenced by index do not depend on the
+		// given names.
+		{
+			Column:       Index(12),
+			Expected:     12,
+			ErrorMatcher: nil,
+		},
+		// Test case 2 makes sure columns referenced by name are looked up.
+		{
+			Column:       Name("server_time"),
+			Expected:     3,
+			ErrorMatcher: nil,
+		},
+		// Test case 3 makes sure unknown column names cause an error.
+		{
+			Column:       Name("time"),
+			Expected:     0,
+			ErrorMatcher: IsNotFound,
+		},
+	}
+
+	for i, testCase := range testCases {
+		index, err := testCase.Column.Resolve(names)
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if index != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", index)
+		}
+	}
+}
+
+func Test_Column_Unmarshal(t *testing.T) {
+	type header struct {
+		Buy  Column
+		Sell Column
+	}
+
+	expected := header{
+		Buy:  Index(9),
+		Sell: Name("sell"),
+	}
+
+	var y header
+	err := yaml.Unmarshal([]byte("buy: 9\nsell: sell\n"), &y)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if !reflect.DeepEqual(y, expected) {
+		t.Fatal("expected", expected, "got", y)
+	}
+
+	var j header
+	err = json.Unmarshal([]byte(`{"buy":9,"sell":"sell"}`), &j)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if !reflect.DeepEqual(j, expected) {
+		t.Fatal("expected", expected, "got", j)
+	}
+
+	b, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if string(b) != `{"Buy":9,"Sell":"sell"}` {
+		t.Fatal("expected", `{"Buy":9,"Sell":"sell"}`, "got", string(b))
+	}
+}
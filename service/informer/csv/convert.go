@@ -1,21 +1,23 @@
 package csv
 
 import (
-	"encoding/csv"
+	"io"
 	"io/ioutil"
-	"os"
 	"path/filepath"
-	"strconv"
-	"time"
 
 	microerror "github.com/giantswarm/microkit/error"
 	yaml "gopkg.in/yaml.v2"
 
-	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/chartcache"
+	"github.com/xh3b4sd/wafer/service/informer/chartfile"
 	runtimeconfigdir "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/dir"
 	runtimeconfigfile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file"
+	configmeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/meta"
 	runtimestatefile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file"
 	statefileheader "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+	statequality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/quality"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
 )
 
 func fileToFiles(file runtimeconfigfile.File) ([]runtimestatefile.File, error) {
@@ -25,8 +27,19 @@ func fileToFiles(file runtimeconfigfile.File) ([]runtimestatefile.File, error) {
 			Header: statefileheader.Header{
 				Buy:    file.Header.Buy,
 				Ignore: file.Header.Ignore,
-				Sell:   file.Header.Sell,
-				Time:   file.Header.Time,
+				Meta:   configMetaToStateMeta(file.Header.Meta),
+				Quality: statequality.Quality{
+					Duplicate: file.Header.Quality.Duplicate,
+					Gap:       file.Header.Quality.Gap,
+					GapPolicy: file.Header.Quality.GapPolicy,
+					Invalid:   file.Header.Quality.Invalid,
+					Order:     file.Header.Quality.Order,
+				},
+				Sell:       file.Header.Sell,
+				Time:       file.Header.Time,
+				TimeFormat: file.Header.TimeFormat,
+				TimeZone:   file.Header.TimeZone,
+				Volume:     file.Header.Volume,
 			},
 		},
 	}
@@ -57,7 +70,12 @@ func dirToFiles(dir runtimeconfigdir.Dir) ([]runtimestatefile.File, error) {
 				return nil, microerror.MaskAnyf(invalidExecutionError, "outer CSV dir must not contain dirs")
 			}
 
-			if ifi.Name() == "chart.csv" {
+			// The chart data may be compressed, e.g. chart.csv.gz. Compressed
+			// charts are decompressed while being read.
+			if chartfile.Base(ifi.Name()) == "chart.csv" {
+				if stateFile.Path != "" {
+					return nil, microerror.MaskAnyf(invalidExecutionError, "multiple charts '%s' and '%s' not allowed", filepath.Base(stateFile.Path), ifi.Name())
+				}
 				stateFile.Path = filepath.Join(dir.Path, ofi.Name(), ifi.Name())
 				continue
 			}
@@ -74,20 +92,42 @@ func dirToFiles(dir runtimeconfigdir.Dir) ([]runtimestatefile.File, error) {
 					return nil, microerror.MaskAny(err)
 				}
 
+				err = stateMetaToConfigMeta(header.Meta).Validate()
+				if err != nil {
+					return nil, microerror.MaskAnyf(invalidConfigError, "%s: %s", filepath.Join(dir.Path, ofi.Name(), ifi.Name()), err.Error())
+				}
+
 				stateFile.Header.Buy = header.Buy
 				stateFile.Header.Ignore = header.Ignore
+				stateFile.Header.Meta = header.Meta
+				stateFile.Header.Quality = header.Quality
 				stateFile.Header.Sell = header.Sell
 				stateFile.Header.Time = header.Time
+				stateFile.Header.TimeFormat = header.TimeFormat
+				stateFile.Header.TimeZone = header.TimeZone
+				stateFile.Header.Volume = header.Volume
 
 				continue
 			}
 
+			// The binary cache of the chart is maintained by the informer itself.
+			// That includes the temporary files caches are written to.
+			if ifi.Name() == chartcache.Path("chart.csv") || chartcache.IsTemp(ifi.Name()) {
+				continue
+			}
+
 			// We accept a README.md to be able to provide useful information on the
 			// chart data stored in the chart directory.
 			if ifi.Name() == "README.md" {
 				continue
 			}
 
+			// We accept a provenance.yaml as written by the chart import, recording
+			// the raw export the chart data was imported from.
+			if ifi.Name() == "provenance.yaml" {
+				continue
+			}
+
 			return nil, microerror.MaskAnyf(invalidExecutionError, "additional file '%s' not allowed", ifi.Name())
 		}
 
@@ -97,60 +137,124 @@ func dirToFiles(dir runtimeconfigdir.Dir) ([]runtimestatefile.File, error) {
 	return files, nil
 }
 
-func filesToPrices(files []runtimestatefile.File) ([][]informer.Price, error) {
-	var prices [][]informer.Price
+// filesToPrices pre-scans the given files to gather the statistical
+// information about the charts they contain. The rows are read one by one, so
+// the charts are never loaded into memory as a whole. Note that each row is
+// parsed during the pre-scan, which makes sure malformed charts are detected
+// before any price event is handed out to consumers. In case cache is true,
+// charts having an up to date cache are read from their cache. The cache of
+// all other charts is written once they were read completely.
+func filesToPrices(files []runtimestatefile.File, cache bool) ([]stateprice.Price, error) {
+	var prices []stateprice.Price
 
 	for _, f := range files {
-		// Read the CSV file.
-		var fields [][]string
-		{
-			// TODO this should be abstracted with some more decent interface.
-			csvFile, err := os.Open(f.Path)
-			if err != nil {
-				return nil, microerror.MaskAny(err)
-			}
-			defer csvFile.Close()
+		price, err := fileToPrice(f, cache)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
 
-			reader := csv.NewReader(csvFile)
-			fields, err = reader.ReadAll()
-			if err != nil {
-				return nil, microerror.MaskAny(err)
-			}
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+}
+
+func fileToPrice(file runtimestatefile.File, cache bool) (stateprice.Price, error) {
+	var r checksumSource
+	var w *cacheWriter
+	if cache {
+		c, err := openCache(file)
+		if err == nil {
+			r = c
+		}
+	}
+	if r == nil {
+		c, err := newReader(file)
+		if err != nil {
+			return stateprice.Price{}, microerror.MaskAny(err)
 		}
+		r = c
 
-		// Fill the prices channel.
-		var ps []informer.Price
-		{
-			if f.Header.Ignore {
-				fields = fields[1:]
+		// The cache only speeds up loading the chart. Failing to create it, e.g.
+		// because the chart dir is read-only, means the chart is parsed again the
+		// next time it is loaded.
+		if cache {
+			w, err = newCacheWriter(c)
+			if err == nil {
+				r = w
 			}
+		}
+	}
 
-			for _, fs := range fields {
-				b, err := strconv.ParseFloat(fs[f.Header.Buy], 64)
-				if err != nil {
-					return nil, microerror.MaskAny(err)
-				}
-				s, err := strconv.ParseFloat(fs[f.Header.Sell], 64)
-				if err != nil {
-					return nil, microerror.MaskAny(err)
-				}
-				t, err := strconv.ParseInt(fs[f.Header.Time], 10, 64)
-				if err != nil {
-					return nil, microerror.MaskAny(err)
-				}
+	s, err := newCleaner(file, r)
+	if err != nil {
+		return stateprice.Price{}, microerror.MaskAny(err)
+	}
+	defer s.Close()
 
-				price := informer.Price{
-					Buy:  b,
-					Sell: s,
-					Time: time.Unix(t, 0),
-				}
+	var price stateprice.Price
 
-				ps = append(ps, price)
-			}
+	for {
+		p, err := s.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return stateprice.Price{}, microerror.MaskAny(err)
 		}
 
-		prices = append(prices, ps)
+		if price.Events == 0 {
+			price.Start = p.Time
+		}
+		price.End = p.Time
+		price.Events++
 	}
 
-	return prices, nil
+	price.Checksum = r.Checksum()
+	price.Meta = file.Header.Meta
+	price.Quality = s.Summary()
+
+	// The cache only speeds up loading the chart. Failing to write it, e.g.
+	// because the chart dir is read-only, means the chart is parsed again the
+	// next time it is loaded.
+	if w != nil {
+		w.Write()
+	}
+
+	return price, nil
+}
+
+func configMetaToStateMeta(m configmeta.Meta) statemeta.Meta {
+	meta := statemeta.Meta{
+		Base:     m.Base,
+		LotSize:  m.LotSize,
+		Quote:    m.Quote,
+		Symbol:   m.Symbol,
+		TickSize: m.TickSize,
+	}
+	if m.Fee != nil {
+		meta.Fee = &statemeta.Fee{
+			Maker: m.Fee.Maker,
+			Taker: m.Fee.Taker,
+		}
+	}
+
+	return meta
+}
+
+func stateMetaToConfigMeta(m statemeta.Meta) configmeta.Meta {
+	meta := configmeta.Meta{
+		Base:     m.Base,
+		LotSize:  m.LotSize,
+		Quote:    m.Quote,
+		Symbol:   m.Symbol,
+		TickSize: m.TickSize,
+	}
+	if m.Fee != nil {
+		meta.Fee = &configmeta.Fee{
+			Maker: m.Fee.Maker,
+			Taker: m.Fee.Taker,
+		}
+	}
+
+	return meta
 }
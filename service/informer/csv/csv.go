@@ -3,26 +3,51 @@
 package csv
 
 import (
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
 	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
 
 	"github.com/xh3b4sd/wafer/service/informer"
 	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	runtimeconfig "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config"
 	runtimeconfigdir "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/dir"
 	runtimeconfigfile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file"
+	runtimestate "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state"
 	runtimestatefile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file"
 	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+	statereload "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/reload"
 )
 
 // Config is the configuration used to create a new informer.
 type Config struct {
 	// Settings.
 
+	// Cache decides whether to maintain a binary cache beside each CSV file,
+	// e.g. chart.bin beside chart.csv. Charts having an up to date cache are
+	// read from their cache instead of being parsed. The cache of a chart is
+	// written when the informer is created and rewritten as soon as the CSV
+	// file or its header settings changed.
+	Cache bool
+
 	// Dir is the config for an absolute location of the CSV dir to consume.
 	// Either File or dir can be used at the same time.
 	Dir runtimeconfigdir.Dir
 	// File is the config for an absolute location of the CSV file to consume.
 	// Either File or dir can be used at the same time.
 	File runtimeconfigfile.File
+	// Watch decides whether to watch the CSV dir for changes. New chart dirs,
+	// changed header options and rows appended to charts become available
+	// without creating a new informer. Each successful reload increments the
+	// reload generation reported by the runtime state. Watch can only be used
+	// together with Dir. Informers watching their CSV dir have to be closed.
+	Watch bool
+	// WatchDelay is the duration to wait for further changes of the CSV dir
+	// before the charts are reloaded. That way charts being written are not
+	// reloaded for every single row.
+	WatchDelay time.Duration
 }
 
 // DefaultConfig returns the default configuration used to create a new informer
@@ -30,8 +55,11 @@ type Config struct {
 func DefaultConfig() Config {
 	return Config{
 		// Settings.
-		Dir:  runtimeconfigdir.Dir{},
-		File: runtimeconfigfile.File{},
+		Cache:      false,
+		Dir:        runtimeconfigdir.Dir{},
+		File:       runtimeconfigfile.File{},
+		Watch:      false,
+		WatchDelay: time.Second,
 	}
 }
 
@@ -47,80 +75,155 @@ func New(config Config) (informer.Informer, error) {
 		return nil, microerror.MaskAnyf(invalidConfigError, "either config.Dir or config.File must be given")
 	}
 
+	if config.Watch && dErr != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Watch requires config.Dir to be given")
+	}
+	if config.Watch && config.WatchDelay <= 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.WatchDelay must be greater than 0")
+	}
+
+	files, prices, err := load(config)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	newInformer := &Informer{
+		// Settings.
+		cache:  config.Cache,
+		config: config,
+
+		// Internals.
+		closeOnce: sync.Once{},
+		done:      make(chan struct{}),
+		files:     files,
+		mutex:     sync.Mutex{},
+		runtime: runtime.Runtime{
+			Config: runtimeconfig.Config{
+				Cache: config.Cache,
+				Dir:   config.Dir,
+				File:  config.File,
+				Watch: config.Watch,
+			},
+			State: runtimestate.State{
+				Files:  files,
+				Prices: prices,
+				Reload: statereload.Reload{
+					Time: time.Now(),
+				},
+			},
+		},
+		watcher: nil,
+	}
+
+	if config.Watch {
+		newInformer.watcher, err = fsnotify.NewWatcher()
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+		err = newInformer.watch(files)
+		if err != nil {
+			newInformer.watcher.Close()
+			return nil, microerror.MaskAny(err)
+		}
+
+		go newInformer.reloadOnChange()
+	}
+
+	return newInformer, nil
+}
+
+// load reads the files described by the given config and pre-scans the charts
+// they contain.
+func load(config Config) ([]runtimestatefile.File, []stateprice.Price, error) {
 	var files []runtimestatefile.File
 	var err error
 
-	if dErr != nil {
+	if config.Dir.Validate() != nil {
 		files, err = fileToFiles(config.File)
 		if err != nil {
-			return nil, microerror.MaskAny(err)
+			return nil, nil, microerror.MaskAny(err)
 		}
 	} else {
 		files, err = dirToFiles(config.Dir)
 		if err != nil {
-			return nil, microerror.MaskAny(err)
+			return nil, nil, microerror.MaskAny(err)
 		}
 	}
 
-	prices, err := filesToPrices(files)
+	prices, err := filesToPrices(files, config.Cache)
 	if err != nil {
-		return nil, microerror.MaskAny(err)
-	}
-
-	newInformer := &Informer{
-		// Internals.
-		prices:  prices,
-		runtime: runtime.Runtime{},
+		return nil, nil, microerror.MaskAny(err)
 	}
 
 	for _, p := range prices {
-		if len(p) < 2 {
-			return nil, microerror.MaskAnyf(invalidConfigError, "chart must contain at least 2 price events")
+		if p.Events < 2 {
+			return nil, nil, microerror.MaskAnyf(invalidConfigError, "chart must contain at least 2 price events")
 		}
-
-		price := stateprice.Price{
-			End:    p[len(p)-1].Time,
-			Events: len(p),
-			Start:  p[0].Time,
-		}
-		newInformer.runtime.State.Prices = append(newInformer.runtime.State.Prices, price)
 	}
 
-	return newInformer, nil
+	return files, prices, nil
 }
 
 // Informer implements informer.Informer.
 type Informer struct {
+	// Settings.
+	cache  bool
+	config Config
+
 	// Internals.
-	prices  [][]informer.Price
-	runtime runtime.Runtime
+	closeOnce sync.Once
+	done      chan struct{}
+	files     []runtimestatefile.File
+	mutex     sync.Mutex
+	runtime   runtime.Runtime
+	watcher   *fsnotify.Watcher
 }
 
-// Prices returns a list of price channels containing price events. These hold
-// buy and sell prices as well as their corresponding timestamps. Note that buy
-// and sell prices are parsed as float64 and the CSV informer assumes the
-// timestamp is a usual unix timestamp in seconds. Also note that the returned
-// list of channels must be consumed beginning with the first channel of the
-// list. Consuming the last channel of the returned list at first would cause a
-// dead lock.
-func (i *Informer) Prices() []chan informer.Price {
-	prices := make([]chan informer.Price, len(i.prices))
-	for i, _ := range prices {
-		prices[i] = make(chan informer.Price, 10)
-	}
+// Close stops watching the CSV dir. Close does nothing in case the informer
+// does not watch its CSV dir.
+func (i *Informer) Close() error {
+	var err error
 
-	go func() {
-		for j, c := range prices {
-			for _, p := range i.prices[j] {
-				c <- p
-			}
-			close(c)
+	i.closeOnce.Do(func() {
+		close(i.done)
+		if i.watcher != nil {
+			err = i.watcher.Close()
 		}
-	}()
+	})
+
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
 
-	return prices
+// Prices returns a list of iterators providing price events. These hold buy
+// and sell prices as well as their corresponding timestamps. Note that buy and
+// sell prices are parsed as float64. Timestamps are parsed according to the
+// TimeFormat of the header of each CSV file, e.g. unix timestamps in seconds,
+// and interpreted in its TimeZone. Each call of Prices creates new
+// iterators, which open their underlying CSV files on their own and read their
+// rows lazily. In case caching is enabled, iterators read from the cache of
+// their CSV files as long as it is up to date. In case the CSV dir is watched,
+// the iterators provide the charts of the current reload generation.
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	i.mutex.Lock()
+	files := i.files
+	i.mutex.Unlock()
+
+	var iterators []informer.Iterator
+
+	for _, f := range files {
+		iterators = append(iterators, newIterator(ctx, f, i.cache))
+	}
+
+	return iterators, nil
 }
 
 func (i *Informer) Runtime() runtime.Runtime {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
 	return i.runtime
 }
@@ -1,15 +1,28 @@
 package csv
 
 import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/juju/errgo"
+	"golang.org/x/net/context"
+
 	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/chartcache"
+	"github.com/xh3b4sd/wafer/service/informer/chartfile"
+	"github.com/xh3b4sd/wafer/service/informer/csv/column"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
 	runtimeconfigdir "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/dir"
 	runtimeconfigfile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file"
 	configfileheader "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
 )
 
 func Test_Informer_File_Prices(t *testing.T) {
@@ -17,6 +30,7 @@ func Test_Informer_File_Prices(t *testing.T) {
 	if err != nil {
 		t.Fatal("expected", nil, "got", err)
 	}
+	volumeColumn := column.Name("vol_cur")
 
 	testCases := []struct {
 		File     runtimeconfigfile.File
@@ -25,10 +39,10 @@ func Test_Informer_File_Prices(t *testing.T) {
 		{
 			File: runtimeconfigfile.File{
 				Header: configfileheader.Header{
-					Buy:    9,
+					Buy:    column.Index(9),
 					Ignore: true,
-					Sell:   10,
-					Time:   12,
+					Sell:   column.Index(10),
+					Time:   column.Index(12),
 				},
 				Path: path,
 			},
@@ -50,6 +64,34 @@ func Test_Informer_File_Prices(t *testing.T) {
 				},
 			},
 		},
+		// Test case 2 makes sure columns can be referenced by the names given in
+		// the first row of the CSV file.
+		{
+			File: runtimeconfigfile.File{
+				Header: configfileheader.Header{
+					Buy:    column.Name("buy"),
+					Ignore: false,
+					Sell:   column.Name("sell"),
+					Time:   column.Name("server_time"),
+					Volume: &volumeColumn,
+				},
+				Path: path,
+			},
+			Expected: map[int]informer.Price{
+				0: {
+					Buy:    797.4000000000,
+					Sell:   797.0000000000,
+					Time:   time.Unix(1391212802, 0),
+					Volume: 4327.8020100000,
+				},
+				9: {
+					Buy:    796.9000000000,
+					Sell:   793.0000000000,
+					Time:   time.Unix(1391213342, 0),
+					Volume: 4393.2324500000,
+				},
+			},
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -60,15 +102,19 @@ func Test_Informer_File_Prices(t *testing.T) {
 			t.Fatal("case", i+1, "expected", nil, "got", err)
 		}
 
-		// The file configuration for the CSV informer must result in one channel in
-		// the channel list, because there is only one CSV file to parse.
-		prices := newInformer.Prices()
-		if len(prices) != 1 {
-			t.Fatal("case", i+1, "expected", 1, "got", len(prices))
+		// The file configuration for the CSV informer must result in one iterator
+		// in the iterator list, because there is only one CSV file to parse.
+		iterators, err := newInformer.Prices(context.Background())
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if len(iterators) != 1 {
+			t.Fatal("case", i+1, "expected", 1, "got", len(iterators))
 		}
 
 		var j int
-		for price := range prices[0] {
+		for iterators[0].Next() {
+			price := iterators[0].Price()
 			for k, p := range testCase.Expected {
 				if j != k {
 					continue
@@ -81,12 +127,15 @@ func Test_Informer_File_Prices(t *testing.T) {
 
 			j++
 		}
+		if iterators[0].Err() != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", iterators[0].Err())
+		}
 	}
 }
 
 // Test_Informer_File_Prices_MultipleCalls makes sure Informer.Prices can be
-// called mutliple times, which means the actual price data is cached and always
-// available in memory.
+// called mutliple times, which means the underlying CSV file is read again for
+// each call.
 func Test_Informer_File_Prices_MultipleCalls(t *testing.T) {
 	path, err := filepath.Abs("./fixtures/file/001.csv")
 	if err != nil {
@@ -94,10 +143,10 @@ func Test_Informer_File_Prices_MultipleCalls(t *testing.T) {
 	}
 
 	newConfig := DefaultConfig()
-	newConfig.File.Header.Buy = 9
+	newConfig.File.Header.Buy = column.Index(9)
 	newConfig.File.Header.Ignore = true
-	newConfig.File.Header.Sell = 10
-	newConfig.File.Header.Time = 12
+	newConfig.File.Header.Sell = column.Index(10)
+	newConfig.File.Header.Time = column.Index(12)
 	newConfig.File.Path = path
 
 	newInformer, err := New(newConfig)
@@ -105,23 +154,26 @@ func Test_Informer_File_Prices_MultipleCalls(t *testing.T) {
 		t.Fatal("expected", nil, "got", err)
 	}
 
-	// The file configuration for the CSV informer must result in one channel in
-	// the channel list, because there is only one CSV file to parse.
-	prices1 := newInformer.Prices()
-	if len(prices1) != 1 {
-		t.Fatal("expected", 1, "got", len(prices1))
-	}
-	p1 := <-prices1[0]
-	prices2 := newInformer.Prices()
-	if len(prices2) != 1 {
-		t.Fatal("expected", 1, "got", len(prices2))
-	}
-	p2 := <-prices2[0]
-	prices3 := newInformer.Prices()
-	if len(prices3) != 1 {
-		t.Fatal("expected", 1, "got", len(prices3))
+	// The file configuration for the CSV informer must result in one iterator in
+	// the iterator list, because there is only one CSV file to parse.
+	var prices []informer.Price
+	for i := 0; i < 3; i++ {
+		iterators, err := newInformer.Prices(context.Background())
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		if len(iterators) != 1 {
+			t.Fatal("expected", 1, "got", len(iterators))
+		}
+		if !iterators[0].Next() {
+			t.Fatal("expected", true, "got", false)
+		}
+		prices = append(prices, iterators[0].Price())
+		iterators[0].Close()
 	}
-	p3 := <-prices3[0]
+	p1 := prices[0]
+	p2 := prices[1]
+	p3 := prices[2]
 
 	if !reflect.DeepEqual(p1, p2) {
 		t.Fatal("expected", true, "got", false)
@@ -195,16 +247,20 @@ func Test_Informer_Dir_Prices(t *testing.T) {
 			t.Fatal("case", i+1, "expected", nil, "got", err)
 		}
 
-		// The dir configuration for the CSV informer must result in two channels in
-		// the channel list, because there are two CSV directories to parse.
-		prices := newInformer.Prices()
-		if len(prices) != 2 {
-			t.Fatal("case", i+1, "expected", 2, "got", len(prices))
+		// The dir configuration for the CSV informer must result in two iterators
+		// in the iterator list, because there are two CSV directories to parse.
+		iterators, err := newInformer.Prices(context.Background())
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if len(iterators) != 2 {
+			t.Fatal("case", i+1, "expected", 2, "got", len(iterators))
 		}
 
-		for d, c := range prices {
+		for d, it := range iterators {
 			var j int
-			for price := range c {
+			for it.Next() {
+				price := it.Price()
 				for k, p := range testCase.Expected[d] {
 					if j != k {
 						continue
@@ -217,6 +273,696 @@ func Test_Informer_Dir_Prices(t *testing.T) {
 
 				j++
 			}
+			if it.Err() != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", it.Err())
+			}
+		}
+	}
+}
+
+// Test_Informer_Dir_Prices_ReverseOrder makes sure the iterators returned by
+// Informer.Prices can be consumed independently of each other. Here the last
+// iterator is consumed completely before the first iterator is touched.
+func Test_Informer_Dir_Prices_ReverseOrder(t *testing.T) {
+	path, err := filepath.Abs("./fixtures/dir/")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.Dir.Path = path
+	newInformer, err := New(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i := len(iterators) - 1; i >= 0; i-- {
+		var n int
+		for iterators[i].Next() {
+			n++
+		}
+		if iterators[i].Err() != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", iterators[i].Err())
+		}
+
+		e := newInformer.Runtime().State.Prices[i].Events
+		if n != e {
+			t.Fatal("case", i+1, "expected", e, "got", n)
+		}
+	}
+}
+
+// Test_Informer_Dir_Prices_Cancel makes sure iterators stop as soon as the
+// context given to Informer.Prices is canceled, and that the cancelation is
+// reported to the consumer.
+func Test_Informer_Dir_Prices_Cancel(t *testing.T) {
+	path, err := filepath.Abs("./fixtures/dir/")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.Dir.Path = path
+	newInformer, err := New(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iterators, err := newInformer.Prices(ctx)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	if !iterators[0].Next() {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	cancel()
+
+	for i, it := range iterators {
+		if it.Next() {
+			t.Fatal("case", i+1, "expected", false, "got", true)
+		}
+		if errgo.Cause(it.Err()) != context.Canceled {
+			t.Fatal("case", i+1, "expected", context.Canceled, "got", it.Err())
+		}
+	}
+}
+
+// Test_Informer_File_Prices_Error makes sure parse errors occurring while
+// iterating are reported to the consumer instead of silently ending the
+// iteration.
+func Test_Informer_File_Prices_Error(t *testing.T) {
+	path, err := filepath.Abs("./fixtures/file/001.csv")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	dir, err := ioutil.TempDir("", "wafer-csv")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "chart.csv")
+	err = ioutil.WriteFile(file, b, 0644)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.File.Header.Buy = column.Index(9)
+	newConfig.File.Header.Ignore = true
+	newConfig.File.Header.Sell = column.Index(10)
+	newConfig.File.Header.Time = column.Index(12)
+	newConfig.File.Path = file
+
+	newInformer, err := New(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// Corrupt the chart after the informer was created, so the error can only be
+	// detected while iterating.
+	err = ioutil.WriteFile(file, append(b, []byte("\"1\",\"1\",\"1\",\"1\",\"1\",\"1\",\"1\",\"1\",\"1\",\"foo\",\"1\",\"1\",\"1\"\n")...), 0644)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer iterators[0].Close()
+
+	var n int
+	for iterators[0].Next() {
+		n++
+	}
+	if n != 31 {
+		t.Fatal("expected", 31, "got", n)
+	}
+	if !IsMalformedChart(iterators[0].Err()) {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	// The corrupted row is the 33rd line, because of the ignored first line and
+	// the 31 valid rows in front of it.
+	if !strings.Contains(iterators[0].Err().Error(), file+":33:") {
+		t.Fatal("expected", file+":33:", "got", iterators[0].Err())
+	}
+}
+
+// Test_Informer_Dir_Runtime_Prices makes sure the statistical information about
+// the charts is gathered when creating the informer, without consuming any
+// price channel.
+func Test_Informer_Dir_Runtime_Prices(t *testing.T) {
+	path, err := filepath.Abs("./fixtures/dir/")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.Dir.Path = path
+	newInformer, err := New(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	prices := newInformer.Runtime().State.Prices
+	if len(prices) != 2 {
+		t.Fatal("expected", 2, "got", len(prices))
+	}
+
+	expected := []stateprice.Price{
+		{
+			Checksum: "4cf93d4c70923d08aa60db85c25b44fd8c1b74757867a007aa8f4ece3189a7fc",
+			End:      time.Unix(1391214602, 0),
+			Events:   31,
+			Start:    time.Unix(1391212802, 0),
+		},
+		{
+			Checksum: "b709fb7da2e6f80cb9b59e69a5307ec0c818e7c4fa683d7cf07eefb78ab9dbd1",
+			End:      time.Unix(1391214602, 0),
+			Events:   31,
+			Start:    time.Unix(1391212802, 0),
+		},
+	}
+
+	for i, p := range prices {
+		if !reflect.DeepEqual(p, expected[i]) {
+			t.Fatal("case", i+1, "expected", expected[i], "got", p)
+		}
+	}
+}
+
+// Test_Informer_File_MissingColumn makes sure referencing a column by a name
+// which is not given in the first row of the CSV file causes an error.
+func Test_Informer_File_MissingColumn(t *testing.T) {
+	path, err := filepath.Abs("./fixtures/file/001.csv")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.File.Header.Buy = column.Name("buy")
+	newConfig.File.Header.Sell = column.Name("sell")
+	newConfig.File.Header.Time = column.Name("time")
+	newConfig.File.Path = path
+
+	_, err = New(newConfig)
+	if !IsInvalidConfig(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+	if !strings.Contains(err.Error(), "'time'") {
+		t.Fatal("expected", "'time'", "got", err)
+	}
+}
+
+// Test_Informer_Dir_Prices_Gzip makes sure gzip compressed charts provide the
+// same price events and the same checksum as their uncompressed originals.
+func Test_Informer_Dir_Prices_Gzip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wafer-csv")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var original informer.Informer
+	{
+		newConfig := DefaultConfig()
+		newConfig.Dir.Path, err = filepath.Abs("./fixtures/dir/")
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		original, err = New(newConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	for _, name := range []string{"001", "002"} {
+		err = os.Mkdir(filepath.Join(dir, name), 0755)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join("fixtures", "dir", name, "header.yaml"))
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, name, "header.yaml"), b, 0644)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		b, err = ioutil.ReadFile(filepath.Join("fixtures", "dir", name, "chart.csv"))
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		f, err := os.Create(filepath.Join(dir, name, "chart.csv.gz"))
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		w := gzip.NewWriter(f)
+		_, err = w.Write(b)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		w.Close()
+		f.Close()
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.Dir.Path = dir
+	newInformer, err := New(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	prices := newInformer.Runtime().State.Prices
+	if !reflect.DeepEqual(prices, original.Runtime().State.Prices) {
+		t.Fatal("expected", original.Runtime().State.Prices, "got", prices)
+	}
+
+	compressed, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	uncompressed, err := original.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i := range compressed {
+		for uncompressed[i].Next() {
+			if !compressed[i].Next() {
+				t.Fatal("chart", i+1, "expected", true, "got", false)
+			}
+			if !reflect.DeepEqual(compressed[i].Price(), uncompressed[i].Price()) {
+				t.Fatal("chart", i+1, "expected", uncompressed[i].Price(), "got", compressed[i].Price())
+			}
+		}
+		if compressed[i].Next() {
+			t.Fatal("chart", i+1, "expected", false, "got", true)
+		}
+		if compressed[i].Err() != nil {
+			t.Fatal("chart", i+1, "expected", nil, "got", compressed[i].Err())
+		}
+		compressed[i].Close()
+		uncompressed[i].Close()
+	}
+
+	// zstd compressed charts are recognized, but cannot be read yet.
+	err = os.Rename(filepath.Join(dir, "001", "chart.csv.gz"), filepath.Join(dir, "001", "chart.csv.zst"))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	_, err = New(newConfig)
+	if !chartfile.IsUnsupportedCompression(err) {
+		t.Fatal("expected", true, "got", err)
+	}
+}
+
+// Test_Informer_Dir_Prices_Cache makes sure charts are read from their binary
+// cache as long as the cache is up to date, and that the cache is rebuilt once
+// the chart changed.
+func Test_Informer_Dir_Prices_Cache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wafer-csv")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var original informer.Informer
+	{
+		newConfig := DefaultConfig()
+		newConfig.Dir.Path, err = filepath.Abs("./fixtures/dir/")
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		original, err = New(newConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	for _, name := range []string{"001", "002"} {
+		err = os.Mkdir(filepath.Join(dir, name), 0755)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		for _, file := range []string{"chart.csv", "header.yaml"} {
+			b, err := ioutil.ReadFile(filepath.Join("fixtures", "dir", name, file))
+			if err != nil {
+				t.Fatal("expected", nil, "got", err)
+			}
+			err = ioutil.WriteFile(filepath.Join(dir, name, file), b, 0644)
+			if err != nil {
+				t.Fatal("expected", nil, "got", err)
+			}
+		}
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.Cache = true
+	newConfig.Dir.Path = dir
+
+	// The first load parses the CSV files and writes their caches. The caches
+	// must not change the price events in any way.
+	for _, load := range []string{"write", "read"} {
+		newInformer, err := New(newConfig)
+		if err != nil {
+			t.Fatal(load, "expected", nil, "got", err)
+		}
+
+		prices := newInformer.Runtime().State.Prices
+		if !reflect.DeepEqual(prices, original.Runtime().State.Prices) {
+			t.Fatal(load, "expected", original.Runtime().State.Prices, "got", prices)
+		}
+
+		cached, err := newInformer.Prices(context.Background())
+		if err != nil {
+			t.Fatal(load, "expected", nil, "got", err)
+		}
+		parsed, err := original.Prices(context.Background())
+		if err != nil {
+			t.Fatal(load, "expected", nil, "got", err)
+		}
+
+		for i := range cached {
+			for parsed[i].Next() {
+				if !cached[i].Next() {
+					t.Fatal(load, "chart", i+1, "expected", true, "got", false)
+				}
+				if !reflect.DeepEqual(cached[i].Price(), parsed[i].Price()) {
+					t.Fatal(load, "chart", i+1, "expected", parsed[i].Price(), "got", cached[i].Price())
+				}
+			}
+			if cached[i].Next() {
+				t.Fatal(load, "chart", i+1, "expected", false, "got", true)
+			}
+			cached[i].Close()
+			parsed[i].Close()
+		}
+	}
+
+	// Up to date caches are read instead of the CSV files. A cache holding a
+	// single price event shows up in the runtime state right away.
+	file := testInformer(t, newConfig).(*Informer).files[0]
+	{
+		stamp, err := cacheStamp(file)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		w, err := chartcache.NewWriter(chartcache.Path(file.Path), false)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		w.Add(informer.Price{Buy: 1, Sell: 1, Time: time.Unix(1, 0)})
+		w.Add(informer.Price{Buy: 2, Sell: 2, Time: time.Unix(2, 0)})
+		err = w.Write(stamp, "cached")
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		// Temporary files left behind by caches being written are ignored.
+		err = ioutil.WriteFile(filepath.Join(filepath.Dir(file.Path), "."+filepath.Base(chartcache.Path(file.Path))+".123456"), nil, 0644)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		p := testInformer(t, newConfig).Runtime().State.Prices[0]
+		if p.Checksum != "cached" || p.Events != 2 {
+			t.Fatal("expected", "cached chart with 2 events", "got", p)
+		}
+	}
+
+	// Changing the CSV file renders its cache stale, so the CSV file is parsed
+	// and the cache is rewritten.
+	{
+		b, err := ioutil.ReadFile(file.Path)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		lines := strings.SplitAfter(string(b), "\n")
+		err = ioutil.WriteFile(file.Path, []byte(strings.Join(lines[:4], "")), 0644)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		p := testInformer(t, newConfig).Runtime().State.Prices[0]
+		if p.Checksum == "cached" || p.Events != 3 {
+			t.Fatal("expected", "parsed chart with 3 events", "got", p)
+		}
+
+		r, err := openCache(file)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		defer r.Close()
+		if r.Checksum() != p.Checksum {
+			t.Fatal("expected", p.Checksum, "got", r.Checksum())
+		}
+	}
+}
+
+func testInformer(t *testing.T, config Config) informer.Informer {
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	return newInformer
+}
+
+// Test_Informer_Dir_Watch makes sure new chart dirs and rows appended to charts
+// become available once the CSV dir is watched, and that reloads which fail
+// keep the charts loaded before.
+func Test_Informer_Dir_Watch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wafer-csv")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer os.RemoveAll(dir)
+
+	copyChart := func(name string) {
+		err := os.Mkdir(filepath.Join(dir, name), 0755)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		for _, file := range []string{"header.yaml", "chart.csv"} {
+			b, err := ioutil.ReadFile(filepath.Join("fixtures", "dir", name, file))
+			if err != nil {
+				t.Fatal("expected", nil, "got", err)
+			}
+			err = ioutil.WriteFile(filepath.Join(dir, name, file), b, 0644)
+			if err != nil {
+				t.Fatal("expected", nil, "got", err)
+			}
+		}
+	}
+
+	// waitFor waits until the runtime state of the given informer reports the
+	// given reload generation.
+	waitFor := func(i informer.Informer, generation int) runtime.Runtime {
+		for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(5 * time.Millisecond) {
+			r := i.Runtime()
+			if r.State.Reload.Generation >= generation {
+				return r
+			}
+		}
+
+		t.Fatal("expected", generation, "got", i.Runtime().State.Reload)
+		return runtime.Runtime{}
+	}
+
+	copyChart("001")
+
+	newConfig := DefaultConfig()
+	newConfig.Dir.Path = dir
+	newConfig.Watch = true
+	newConfig.WatchDelay = 10 * time.Millisecond
+	newInformer, err := New(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer newInformer.(*Informer).Close()
+
+	r := newInformer.Runtime()
+	if r.State.Reload.Generation != 0 || len(r.State.Prices) != 1 {
+		t.Fatal("expected", "generation 0 with 1 chart", "got", r.State.Reload.Generation, len(r.State.Prices))
+	}
+
+	// New chart dirs are picked up.
+	copyChart("002")
+	r = waitFor(newInformer, 1)
+	for len(r.State.Prices) != 2 {
+		r = waitFor(newInformer, r.State.Reload.Generation+1)
+	}
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(iterators) != 2 {
+		t.Fatal("expected", 2, "got", len(iterators))
+	}
+	for _, it := range iterators {
+		it.Close()
+	}
+
+	// Rows appended to charts are picked up.
+	{
+		f, err := os.OpenFile(filepath.Join(dir, "002", "chart.csv"), os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		_, err = f.WriteString("\"1\",\"1\",\"1\",\"1\",\"1\",\"800.0\",\"799.0\",\"1391214660\",\"1391214662\"\n")
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		f.Close()
+
+		generation := r.State.Reload.Generation
+		for r.State.Prices[1].Events != 32 {
+			generation++
+			r = waitFor(newInformer, generation)
+		}
+		if !r.State.Prices[1].End.Equal(time.Unix(1391214662, 0)) {
+			t.Fatal("expected", time.Unix(1391214662, 0), "got", r.State.Prices[1].End)
+		}
+	}
+
+	// Reloads which fail keep the current generation.
+	{
+		err := ioutil.WriteFile(filepath.Join(dir, "foo.txt"), nil, 0644)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		for start := time.Now(); newInformer.Runtime().State.Reload.Error == ""; time.Sleep(5 * time.Millisecond) {
+			if time.Since(start) > 5*time.Second {
+				t.Fatal("expected", "reload error", "got", "")
+			}
+		}
+
+		f := newInformer.Runtime()
+		if f.State.Reload.Generation != r.State.Reload.Generation || !reflect.DeepEqual(f.State.Prices, r.State.Prices) {
+			t.Fatal("expected", r.State, "got", f.State)
+		}
+	}
+}
+
+// Test_Informer_File_Watch makes sure only CSV dirs can be watched.
+func Test_Informer_File_Watch(t *testing.T) {
+	path, err := filepath.Abs("./fixtures/file/001.csv")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.File.Path = path
+	newConfig.File.Header.Buy = column.Index(7)
+	newConfig.File.Header.Sell = column.Index(8)
+	newConfig.File.Header.Time = column.Index(10)
+	newConfig.Watch = true
+	_, err = New(newConfig)
+	if !IsInvalidConfig(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+// Test_Informer_Dir_Meta makes sure the market a chart belongs to is read from
+// its header.yaml and exposed through the runtime state.
+func Test_Informer_Dir_Meta(t *testing.T) {
+	testCases := []struct {
+		Meta         string
+		Expected     statemeta.Meta
+		ErrorMatcher func(error) bool
+	}{
+		// Test case 1, charts not declaring their market have empty meta data.
+		{
+			Meta:         "",
+			Expected:     statemeta.Meta{},
+			ErrorMatcher: nil,
+		},
+		// Test case 2, the declared market is exposed.
+		{
+			Meta: "meta:\n  symbol: BTC-EUR\n  base: BTC\n  quote: EUR\n  ticksize: 0.01\n  lotsize: 0.001\n  fee:\n    maker: 0.15\n    taker: 0.25\n",
+			Expected: statemeta.Meta{
+				Base:     "BTC",
+				Fee:      &statemeta.Fee{Maker: 0.15, Taker: 0.25},
+				LotSize:  0.001,
+				Quote:    "EUR",
+				Symbol:   "BTC-EUR",
+				TickSize: 0.01,
+			},
+			ErrorMatcher: nil,
+		},
+		// Test case 3, invalid meta data causes an error.
+		{
+			Meta:         "meta:\n  lotsize: -1\n",
+			Expected:     statemeta.Meta{},
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		dir, err := ioutil.TempDir("", "wafer-csv")
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		defer os.RemoveAll(dir)
+
+		err = os.Mkdir(filepath.Join(dir, "001"), 0755)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		for _, file := range []string{"chart.csv", "header.yaml"} {
+			b, err := ioutil.ReadFile(filepath.Join("fixtures", "dir", "001", file))
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+			if file == "header.yaml" {
+				b = append(b, []byte(testCase.Meta)...)
+			}
+			err = ioutil.WriteFile(filepath.Join(dir, "001", file), b, 0644)
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+		}
+
+		newConfig := DefaultConfig()
+		newConfig.Dir.Path = dir
+		newInformer, err := New(newConfig)
+		if testCase.ErrorMatcher == nil && err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+
+		r := newInformer.Runtime()
+		if !reflect.DeepEqual(r.State.Prices[0].Meta, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", r.State.Prices[0].Meta)
+		}
+		if !reflect.DeepEqual(r.State.Files[0].Header.Meta, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", r.State.Files[0].Header.Meta)
 		}
 	}
 }
@@ -2,6 +2,8 @@ package csv
 
 import (
 	"github.com/juju/errgo"
+
+	"github.com/xh3b4sd/wafer/service/informer/cleaner"
 )
 
 var invalidConfigError = errgo.New("invalid config")
@@ -17,3 +19,11 @@ var invalidExecutionError = errgo.New("invalid execution")
 func IsInvalidExecution(err error) bool {
 	return errgo.Cause(err) == invalidExecutionError
 }
+
+var malformedChartError = errgo.New("malformed chart")
+
+// IsMalformedChart asserts malformedChartError. Price events rejected due to
+// the data quality policies of a chart are reported as malformed chart as well.
+func IsMalformedChart(err error) bool {
+	return errgo.Cause(err) == malformedChartError || cleaner.IsMalformedChart(err)
+}
@@ -0,0 +1,33 @@
+package importer
+
+import (
+	"github.com/juju/errgo"
+)
+
+var alreadyExistsError = errgo.New("already exists")
+
+// IsAlreadyExists asserts alreadyExistsError.
+func IsAlreadyExists(err error) bool {
+	return errgo.Cause(err) == alreadyExistsError
+}
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var invalidExecutionError = errgo.New("invalid execution")
+
+// IsInvalidExecution asserts invalidExecutionError.
+func IsInvalidExecution(err error) bool {
+	return errgo.Cause(err) == invalidExecutionError
+}
+
+var malformedInputError = errgo.New("malformed input")
+
+// IsMalformedInput asserts malformedInputError.
+func IsMalformedInput(err error) bool {
+	return errgo.Cause(err) == malformedInputError
+}
@@ -0,0 +1,323 @@
+// Package importer converts raw chart exports into the chart dir layout the CSV
+// informer consumes. Each imported chart dir contains the normalized chart.csv,
+// the header.yaml describing it and a provenance.yaml recording where the chart
+// data came from. Importing works offline on local files. Downloading raw
+// exports is left to the tools of the user's choice.
+//
+// The following formats are supported.
+//
+//     json       JSON arrays of objects or arrays, e.g. [{"time":1,"usd":2}]
+//     trades     CSV trade dumps of time, price and amount, e.g. bitcoincharts
+//     candles    CSV candles having a header row, e.g. time,open,high,low,close
+//
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/meta"
+)
+
+const (
+	// FormatCandles is the format of CSV candles having a header row. Columns
+	// are referenced by name and default to time and close.
+	FormatCandles = "candles"
+	// FormatJSON is the format of JSON arrays. Values are referenced by JSON
+	// paths relative to the array elements and default to time and price.
+	FormatJSON = "json"
+	// FormatTrades is the format of CSV trade dumps without header row, having
+	// the columns time, price and amount in this order.
+	FormatTrades = "trades"
+)
+
+const (
+	// ChartFile is the name of the chart file within imported chart dirs.
+	ChartFile = "chart.csv"
+	// HeaderFile is the name of the header file within imported chart dirs.
+	HeaderFile = "header.yaml"
+	// ProvenanceFile is the name of the file recording the origin of the chart
+	// data within imported chart dirs.
+	ProvenanceFile = "provenance.yaml"
+)
+
+// defaults holds the references of each format used in case the config does
+// not reference the respective values.
+var defaults = map[string]struct {
+	Buy    string
+	Time   string
+	Volume string
+}{
+	FormatCandles: {Buy: "close", Time: "time", Volume: ""},
+	FormatJSON:    {Buy: "price", Time: "time", Volume: ""},
+	FormatTrades:  {Buy: "1", Time: "0", Volume: "2"},
+}
+
+// Config is the configuration used to create a new importer.
+type Config struct {
+	// Settings.
+
+	// Buy references the buy prices within the input. Buy is a column index or
+	// name for CSV formats and a JSON path for FormatJSON. Empty to use the
+	// default of the format.
+	Buy string
+	// First is the number of price events to keep from the start of the chart.
+	// Zero keeps all price events.
+	First int
+	// Format is the format of the input. One of FormatCandles, FormatJSON or
+	// FormatTrades.
+	Format string
+	// Ignore decides whether to ignore the first row of CSV inputs, e.g. in
+	// case trade dumps start with a header row.
+	Ignore bool
+	// Input is the path of the raw export to import. Inputs may be gzip
+	// compressed, e.g. coinbaseUSD.csv.gz.
+	Input string
+	// Last is the number of price events to keep from the end of the chart.
+	// Zero keeps all price events.
+	Last int
+	// Meta describes the market the chart belongs to. It is written into the
+	// generated header.yaml.
+	Meta meta.Meta
+	// Output is the path of the chart dir to create, e.g. charts/004. The dir
+	// must not contain any chart files yet.
+	Output string
+	// Path is the JSON path of the array holding the price events within JSON
+	// inputs, e.g. data. Empty in case the document itself is the array.
+	Path string
+	// Sell references the sell prices within the input, just like Buy. Empty to
+	// use the buy prices as sell prices.
+	Sell string
+	// Source describes where the input was obtained from, e.g. the address it
+	// was downloaded from. It is recorded in the generated provenance.yaml.
+	Source string
+	// Time references the price times within the input, just like Buy. Empty
+	// to use the default of the format.
+	Time string
+	// TimeFormat is the format of the price times within the input. It can be
+	// one of unix, unixmilli, unixnano or rfc3339. Any other value is treated
+	// as Go time layout. Defaults to unix.
+	TimeFormat string
+	// TimeZone is the name of the location price times within the input are
+	// interpreted in, e.g. UTC. Defaults to the local time zone.
+	TimeZone string
+	// Version is the version of the importing program. It is recorded in the
+	// generated provenance.yaml.
+	Version string
+	// Volume references the traded volumes within the input, just like Buy.
+	// Empty to use the default of the format. Only trade dumps provide traded
+	// volumes by default.
+	Volume string
+}
+
+// DefaultConfig returns the default configuration used to create a new
+// importer by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Settings.
+		Buy:        "",
+		First:      0,
+		Format:     "",
+		Ignore:     false,
+		Input:      "",
+		Last:       0,
+		Meta:       meta.Meta{},
+		Output:     "",
+		Path:       "",
+		Sell:       "",
+		Source:     "",
+		Time:       "",
+		TimeFormat: "unix",
+		TimeZone:   "",
+		Version:    "",
+		Volume:     "",
+	}
+}
+
+// New creates a new configured importer.
+func New(config Config) (*Importer, error) {
+	// Settings.
+	d, ok := defaults[config.Format]
+	if !ok {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Format must be one of candles, json or trades")
+	}
+	if config.First < 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.First must not be negative")
+	}
+	if config.First != 0 && config.Last != 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.First and config.Last must not be given at the same time")
+	}
+	if config.Input == "" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Input must not be empty")
+	}
+	if config.Last < 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Last must not be negative")
+	}
+	if config.Output == "" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Output must not be empty")
+	}
+	if config.Path != "" && config.Format != FormatJSON {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Path requires config.Format to be json")
+	}
+	err := config.Meta.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+
+	if config.Buy == "" {
+		config.Buy = d.Buy
+	}
+	if config.Sell == "" {
+		config.Sell = config.Buy
+	}
+	if config.Time == "" {
+		config.Time = d.Time
+	}
+	if config.Volume == "" {
+		config.Volume = d.Volume
+	}
+
+	newImporter := &Importer{
+		// Settings.
+		config: config,
+	}
+
+	return newImporter, nil
+}
+
+// Importer converts a single raw export into a chart dir.
+type Importer struct {
+	// Settings.
+	config Config
+}
+
+// Import reads the configured input and writes the chart dir. Price events are
+// ordered by time before they are written. Import returns the provenance
+// recorded within the chart dir.
+func (i *Importer) Import() (Provenance, error) {
+	checksum, err := fileChecksum(i.config.Input)
+	if err != nil {
+		return Provenance{}, microerror.MaskAny(err)
+	}
+
+	var prices []informer.Price
+	switch i.config.Format {
+	case FormatJSON:
+		prices, err = readJSON(i.config)
+	default:
+		prices, err = readCSV(i.config)
+	}
+	if err != nil {
+		return Provenance{}, microerror.MaskAny(err)
+	}
+	read := len(prices)
+
+	sort.SliceStable(prices, func(a, b int) bool {
+		return prices[a].Time.Before(prices[b].Time)
+	})
+
+	if i.config.First != 0 && i.config.First < len(prices) {
+		prices = prices[:i.config.First]
+	}
+	if i.config.Last != 0 && i.config.Last < len(prices) {
+		prices = prices[len(prices)-i.config.Last:]
+	}
+	if len(prices) < 2 {
+		return Provenance{}, microerror.MaskAnyf(invalidExecutionError, "chart must contain at least 2 price events")
+	}
+
+	err = prepareDir(i.config.Output)
+	if err != nil {
+		return Provenance{}, microerror.MaskAny(err)
+	}
+
+	h, err := writeChart(filepath.Join(i.config.Output, ChartFile), prices, i.config.Volume != "")
+	if err != nil {
+		return Provenance{}, microerror.MaskAny(err)
+	}
+	h.Meta = newHeaderMeta(i.config.Meta)
+
+	err = writeYAML(filepath.Join(i.config.Output, HeaderFile), h)
+	if err != nil {
+		return Provenance{}, microerror.MaskAny(err)
+	}
+
+	p := Provenance{
+		Checksum: checksum,
+		Format:   i.config.Format,
+		Imported: time.Now().UTC().Format(time.RFC3339),
+		Input:    filepath.Base(i.config.Input),
+		Options: Options{
+			Buy:        i.config.Buy,
+			First:      i.config.First,
+			Ignore:     i.config.Ignore,
+			Last:       i.config.Last,
+			Path:       i.config.Path,
+			Sell:       i.config.Sell,
+			Time:       i.config.Time,
+			TimeFormat: i.config.TimeFormat,
+			TimeZone:   i.config.TimeZone,
+			Volume:     i.config.Volume,
+		},
+		Rows: Rows{
+			Read:    read,
+			Written: len(prices),
+		},
+		Source:  i.config.Source,
+		Version: i.config.Version,
+	}
+
+	err = writeYAML(filepath.Join(i.config.Output, ProvenanceFile), p)
+	if err != nil {
+		return Provenance{}, microerror.MaskAny(err)
+	}
+
+	return p, nil
+}
+
+// fileChecksum returns the hex encoded SHA-256 checksum of the file at the
+// given path, as it is stored on disk.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", microerror.MaskAny(err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", microerror.MaskAny(err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// prepareDir creates the chart dir at the given path. Existing chart dirs are
+// only accepted as long as they do not contain any of the files an import
+// writes, so that imports never overwrite charts.
+func prepareDir(path string) error {
+	err := os.MkdirAll(path, 0755)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	for _, name := range []string{ChartFile, ChartFile + ".gz", HeaderFile, ProvenanceFile} {
+		_, err := os.Stat(filepath.Join(path, name))
+		if err == nil {
+			return microerror.MaskAnyf(alreadyExistsError, "%s", filepath.Join(path, name))
+		} else if !os.IsNotExist(err) {
+			return microerror.MaskAny(err)
+		}
+	}
+
+	return nil
+}
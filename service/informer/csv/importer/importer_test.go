@@ -0,0 +1,263 @@
+package importer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv"
+	runtimeconfigdir "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/dir"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/meta"
+)
+
+// Test_Importer_Import makes sure raw exports are imported into chart dirs the
+// CSV informer is able to read.
+func Test_Importer_Import(t *testing.T) {
+	testCases := []struct {
+		Config         func(config Config) Config
+		Input          string
+		ExpectedHeader string
+		Expected       []informer.Price
+	}{
+		// Test case 1 makes sure JSON arrays nested in objects are imported, like
+		// the price statistics of etherchain.
+		{
+			Config: func(config Config) Config {
+				config.Format = FormatJSON
+				config.Path = "data"
+				config.Buy = "usd"
+				config.TimeFormat = "rfc3339"
+				return config
+			},
+			Input:          `{"data":[{"time":"2015-08-30T07:56:28.000Z","usd":1.17},{"time":"2015-08-30T08:56:28.000Z","usd":"1.27"}]}`,
+			ExpectedHeader: "buy: 1\nignore: false\nsell: 1\ntime: 0\n",
+			Expected: []informer.Price{
+				{Buy: 1.17, Sell: 1.17, Time: time.Unix(1440921388, 0)},
+				{Buy: 1.27, Sell: 1.27, Time: time.Unix(1440924988, 0)},
+			},
+		},
+		// Test case 2 makes sure JSON arrays of arrays are imported and price
+		// events are ordered by time.
+		{
+			Config: func(config Config) Config {
+				config.Format = FormatJSON
+				config.Buy = "2"
+				config.Sell = "1"
+				config.Time = "0"
+				config.TimeFormat = "unixmilli"
+				config.Volume = "3"
+				return config
+			},
+			Input:          `[[2000500,9,10,3],[1000000,8,9,2],[3000000,7,8,1]]`,
+			ExpectedHeader: "buy: 1\nignore: false\nsell: 2\ntime: 0\ntimeformat: unixnano\nvolume: 3\n",
+			Expected: []informer.Price{
+				{Buy: 9, Sell: 8, Time: time.Unix(1000, 0), Volume: 2},
+				{Buy: 10, Sell: 9, Time: time.Unix(2000, 500000000), Volume: 3},
+				{Buy: 8, Sell: 7, Time: time.Unix(3000, 0), Volume: 1},
+			},
+		},
+		// Test case 3 makes sure trade dumps are imported, duplicated times are
+		// deduped while reading and Last keeps the end of the chart.
+		{
+			Config: func(config Config) Config {
+				config.Format = FormatTrades
+				config.Last = 3
+				return config
+			},
+			Input:          "1417412036,300.000000000000,0.010000000000\n1417412423,300.000000000000,0.010000000000\n1417415048,370.000000000000,0.010000000000\n1417415048,371.000000000000,0.020000000000\n",
+			ExpectedHeader: "buy: 1\nignore: false\nsell: 1\ntime: 0\nvolume: 2\nquality:\n  duplicate: dedupe-keep-last\n",
+			Expected: []informer.Price{
+				{Buy: 300, Sell: 300, Time: time.Unix(1417412423, 0), Volume: 0.01},
+				{Buy: 371, Sell: 371, Time: time.Unix(1417415048, 0), Volume: 0.02},
+			},
+		},
+		// Test case 4 makes sure candles are imported by column names, First
+		// keeps the start of the chart and meta data is written.
+		{
+			Config: func(config Config) Config {
+				config.Format = FormatCandles
+				config.First = 2
+				config.Meta = meta.Meta{
+					Fee:    &meta.Fee{Maker: 0.15, Taker: 0.25},
+					Symbol: "BTC-EUR",
+				}
+				config.Volume = "volume"
+				return config
+			},
+			Input:          "time,open,high,low,close,volume\n60,1,3,0.5,2,10\n120,2,4,1.5,3,20\n180,3,5,2.5,4,30\n",
+			ExpectedHeader: "buy: 1\nignore: false\nsell: 1\ntime: 0\nvolume: 2\nmeta:\n  symbol: BTC-EUR\n  fee:\n    maker: 0.15\n    taker: 0.25\n",
+			Expected: []informer.Price{
+				{Buy: 2, Sell: 2, Time: time.Unix(60, 0), Volume: 10},
+				{Buy: 3, Sell: 3, Time: time.Unix(120, 0), Volume: 20},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		dir, cleanup := testDir(t)
+		defer cleanup()
+
+		input := filepath.Join(dir, "input")
+		err := ioutil.WriteFile(input, []byte(testCase.Input), 0644)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		config := DefaultConfig()
+		config.Input = input
+		config.Output = filepath.Join(dir, "charts", "001")
+		config.Source = "https://example.com"
+		config = testCase.Config(config)
+
+		newImporter, err := New(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		p, err := newImporter.Import()
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if p.Input != "input" || p.Source != "https://example.com" || len(p.Checksum) != 64 {
+			t.Fatal("case", i+1, "expected", "provenance", "got", p)
+		}
+
+		b, err := ioutil.ReadFile(filepath.Join(config.Output, HeaderFile))
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if string(b) != testCase.ExpectedHeader {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedHeader, "got", string(b))
+		}
+
+		prices := testPrices(t, filepath.Join(dir, "charts"))
+		if len(prices) != len(testCase.Expected) {
+			t.Fatal("case", i+1, "expected", len(testCase.Expected), "got", len(prices))
+		}
+		for j, e := range testCase.Expected {
+			p := prices[j]
+			if p.Buy != e.Buy || p.Sell != e.Sell || !p.Time.Equal(e.Time) || p.Volume != e.Volume {
+				t.Fatal("case", i+1, "index", j, "expected", e, "got", p)
+			}
+		}
+	}
+}
+
+// Test_Importer_Import_AlreadyExists makes sure imports never overwrite
+// existing charts.
+func Test_Importer_Import_AlreadyExists(t *testing.T) {
+	dir, cleanup := testDir(t)
+	defer cleanup()
+
+	input := filepath.Join(dir, "input")
+	err := ioutil.WriteFile(input, []byte("1,1,1\n2,2,2\n"), 0644)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	config := DefaultConfig()
+	config.Format = FormatTrades
+	config.Input = input
+	config.Output = filepath.Join(dir, "001")
+
+	for i, expected := range []bool{false, true} {
+		newImporter, err := New(config)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		_, err = newImporter.Import()
+		if IsAlreadyExists(err) != expected {
+			t.Fatal("import", i+1, "expected", expected, "got", err)
+		}
+	}
+}
+
+func Test_Importer_New(t *testing.T) {
+	testCases := []struct {
+		Config func(config Config) Config
+	}{
+		// Test case 1, the format is unknown.
+		{
+			Config: func(config Config) Config {
+				config.Format = "xml"
+				return config
+			},
+		},
+		// Test case 2, JSON paths are only used by JSON inputs.
+		{
+			Config: func(config Config) Config {
+				config.Path = "data"
+				return config
+			},
+		},
+		// Test case 3, First and Last exclude each other.
+		{
+			Config: func(config Config) Config {
+				config.First = 1
+				config.Last = 1
+				return config
+			},
+		},
+		// Test case 4, the meta data is invalid.
+		{
+			Config: func(config Config) Config {
+				config.Meta.LotSize = -1
+				return config
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultConfig()
+		config.Format = FormatTrades
+		config.Input = "input"
+		config.Output = "output"
+
+		_, err := New(testCase.Config(config))
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+}
+
+func testDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "wafer-importer")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// testPrices reads the price events of the single chart within the given CSV
+// dir.
+func testPrices(t *testing.T, dir string) []informer.Price {
+	config := csv.DefaultConfig()
+	config.Dir = runtimeconfigdir.Dir{Path: dir}
+
+	newInformer, err := csv.New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(iterators) != 1 {
+		t.Fatal("expected", 1, "got", len(iterators))
+	}
+
+	var prices []informer.Price
+	for iterators[0].Next() {
+		prices = append(prices, iterators[0].Price())
+	}
+	if iterators[0].Err() != nil {
+		t.Fatal("expected", nil, "got", iterators[0].Err())
+	}
+
+	return prices
+}
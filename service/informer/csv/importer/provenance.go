@@ -0,0 +1,62 @@
+package importer
+
+// Provenance records the origin of the chart data of an imported chart dir.
+// It is written into the provenance.yaml of the chart dir, so that charts can
+// be traced back to the raw exports they were imported from. Consider the
+// following provenance.yaml.
+//
+//     checksum: 5c1b1d0c5bb4e0e1a8b8f6b1e2f3...
+//     format: trades
+//     imported: 2017-05-21T14:03:29Z
+//     input: coinbaseUSD.csv.gz
+//     options:
+//       buy: "1"
+//       ...
+//     rows:
+//       read: 31042118
+//       written: 15000
+//     source: http://api.bitcoincharts.com/v1/csv/coinbaseUSD.csv.gz
+//
+type Provenance struct {
+	// Checksum is the hex encoded SHA-256 checksum of the input file as it was
+	// stored on disk.
+	Checksum string `yaml:"checksum"`
+	// Format is the format the input was imported as.
+	Format string `yaml:"format"`
+	// Imported is the RFC3339 time the chart was imported at.
+	Imported string `yaml:"imported"`
+	// Input is the name of the input file.
+	Input string `yaml:"input"`
+	// Options are the options the input was imported with.
+	Options Options `yaml:"options"`
+	// Rows are the numbers of price events read and written.
+	Rows Rows `yaml:"rows"`
+	// Source describes where the input was obtained from, if given.
+	Source string `yaml:"source,omitempty"`
+	// Version is the version of the importing program, if given.
+	Version string `yaml:"version,omitempty"`
+}
+
+// Options are the options an input was imported with. Defaults of the format
+// are resolved, so the recorded options describe the import completely.
+type Options struct {
+	Buy        string `yaml:"buy"`
+	First      int    `yaml:"first,omitempty"`
+	Ignore     bool   `yaml:"ignore,omitempty"`
+	Last       int    `yaml:"last,omitempty"`
+	Path       string `yaml:"path,omitempty"`
+	Sell       string `yaml:"sell"`
+	Time       string `yaml:"time"`
+	TimeFormat string `yaml:"timeformat"`
+	TimeZone   string `yaml:"timezone,omitempty"`
+	Volume     string `yaml:"volume,omitempty"`
+}
+
+// Rows are the numbers of price events of an import.
+type Rows struct {
+	// Read is the number of price events read from the input.
+	Read int `yaml:"read"`
+	// Written is the number of price events written into the chart file, after
+	// First or Last were applied.
+	Written int `yaml:"written"`
+}
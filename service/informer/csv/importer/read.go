@@ -0,0 +1,185 @@
+package importer
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/chartfile"
+	"github.com/xh3b4sd/wafer/service/informer/csv"
+	"github.com/xh3b4sd/wafer/service/informer/csv/column"
+	runtimeconfigfile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/jsonpath"
+	"github.com/xh3b4sd/wafer/service/informer/timeformat"
+)
+
+// readCSV reads the price events of CSV inputs. The input is read by the CSV
+// informer, so raw exports are parsed exactly like the charts they become.
+func readCSV(config Config) ([]informer.Price, error) {
+	h := header.Header{
+		Buy:        column.Parse(config.Buy),
+		Ignore:     config.Ignore,
+		Sell:       column.Parse(config.Sell),
+		Time:       column.Parse(config.Time),
+		TimeFormat: config.TimeFormat,
+		TimeZone:   config.TimeZone,
+	}
+	if config.Volume != "" {
+		c := column.Parse(config.Volume)
+		h.Volume = &c
+	}
+
+	var newInformer informer.Informer
+	{
+		informerConfig := csv.DefaultConfig()
+
+		informerConfig.File = runtimeconfigfile.File{
+			Header: h,
+			Path:   config.Input,
+		}
+
+		var err error
+		newInformer, err = csv.New(informerConfig)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	var prices []informer.Price
+	for _, it := range iterators {
+		for it.Next() {
+			prices = append(prices, it.Price())
+		}
+		it.Close()
+		if it.Err() != nil {
+			return nil, microerror.MaskAny(it.Err())
+		}
+	}
+
+	return prices, nil
+}
+
+// readJSON reads the price events of JSON inputs. Each element of the
+// configured array is turned into a price event. Elements may be objects or
+// arrays, e.g. [1440921388,1.17] referenced by the paths 0 and 1.
+func readJSON(config Config) ([]informer.Price, error) {
+	timeParser, err := timeformat.NewParser(config.TimeFormat, config.TimeZone)
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+
+	var buy, sell, when jsonpath.Path
+	var volume *jsonpath.Path
+	{
+		buy, err = jsonpath.Parse(config.Buy)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+		}
+		sell, err = jsonpath.Parse(config.Sell)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+		}
+		when, err = jsonpath.Parse(config.Time)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+		}
+		if config.Volume != "" {
+			p, err := jsonpath.Parse(config.Volume)
+			if err != nil {
+				return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+			}
+			volume = &p
+		}
+	}
+
+	r, err := chartfile.Open(config.Input)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	defer r.Close()
+
+	var v interface{}
+	{
+		d := json.NewDecoder(r)
+		d.UseNumber()
+		err := d.Decode(&v)
+		if err != nil {
+			return nil, microerror.MaskAnyf(malformedInputError, "%s: %s", config.Input, err.Error())
+		}
+	}
+
+	if config.Path != "" {
+		p, err := jsonpath.Parse(config.Path)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+		}
+		v, err = p.Lookup(v)
+		if err != nil {
+			return nil, microerror.MaskAnyf(malformedInputError, "%s: %s", config.Input, err.Error())
+		}
+	}
+
+	elements, ok := v.([]interface{})
+	if !ok {
+		return nil, microerror.MaskAnyf(malformedInputError, "%s: expected JSON array", config.Input)
+	}
+
+	var prices []informer.Price
+	for n, e := range elements {
+		var p informer.Price
+
+		p.Buy, err = jsonFloat(e, buy)
+		if err != nil {
+			return nil, microerror.MaskAnyf(malformedInputError, "%s: element %d: %s", config.Input, n, err.Error())
+		}
+		p.Sell, err = jsonFloat(e, sell)
+		if err != nil {
+			return nil, microerror.MaskAnyf(malformedInputError, "%s: element %d: %s", config.Input, n, err.Error())
+		}
+		if volume != nil {
+			p.Volume, err = jsonFloat(e, *volume)
+			if err != nil {
+				return nil, microerror.MaskAnyf(malformedInputError, "%s: element %d: %s", config.Input, n, err.Error())
+			}
+		}
+
+		s, err := when.Scalar(e)
+		if err != nil {
+			return nil, microerror.MaskAnyf(malformedInputError, "%s: element %d: %s", config.Input, n, err.Error())
+		}
+		p.Time, err = timeParser.Parse(strings.TrimSpace(s))
+		if err != nil {
+			return nil, microerror.MaskAnyf(malformedInputError, "%s: element %d: %s", config.Input, n, err.Error())
+		}
+
+		prices = append(prices, p)
+	}
+
+	return prices, nil
+}
+
+// jsonFloat looks up the value of the given path and parses it as float64.
+// Numbers given as JSON strings are accepted as well.
+func jsonFloat(v interface{}, p jsonpath.Path) (float64, error) {
+	s, err := p.Scalar(v)
+	if err != nil {
+		return 0, microerror.MaskAny(err)
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, microerror.MaskAny(err)
+	}
+
+	return f, nil
+}
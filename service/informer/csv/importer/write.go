@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	microerror "github.com/giantswarm/microkit/error"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/meta"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/quality"
+	"github.com/xh3b4sd/wafer/service/informer/timeformat"
+)
+
+// chartHeader is the content of the generated header.yaml. The fields are
+// ordered like in the header.yaml files of the existing charts.
+type chartHeader struct {
+	Buy        int            `yaml:"buy"`
+	Ignore     bool           `yaml:"ignore"`
+	Sell       int            `yaml:"sell"`
+	Time       int            `yaml:"time"`
+	TimeFormat string         `yaml:"timeformat,omitempty"`
+	Volume     *int           `yaml:"volume,omitempty"`
+	Meta       *headerMeta    `yaml:"meta,omitempty"`
+	Quality    *headerQuality `yaml:"quality,omitempty"`
+}
+
+type headerMeta struct {
+	Symbol   string     `yaml:"symbol,omitempty"`
+	Base     string     `yaml:"base,omitempty"`
+	Quote    string     `yaml:"quote,omitempty"`
+	TickSize float64    `yaml:"ticksize,omitempty"`
+	LotSize  float64    `yaml:"lotsize,omitempty"`
+	Fee      *headerFee `yaml:"fee,omitempty"`
+}
+
+type headerFee struct {
+	Maker float64 `yaml:"maker"`
+	Taker float64 `yaml:"taker"`
+}
+
+type headerQuality struct {
+	Duplicate string `yaml:"duplicate"`
+}
+
+// newHeaderMeta returns the header.yaml representation of the given meta
+// data. In case no meta data is declared, nil is returned.
+func newHeaderMeta(m meta.Meta) *headerMeta {
+	if m == (meta.Meta{}) {
+		return nil
+	}
+
+	h := &headerMeta{
+		Base:     m.Base,
+		LotSize:  m.LotSize,
+		Quote:    m.Quote,
+		Symbol:   m.Symbol,
+		TickSize: m.TickSize,
+	}
+	if m.Fee != nil {
+		h.Fee = &headerFee{
+			Maker: m.Fee.Maker,
+			Taker: m.Fee.Taker,
+		}
+	}
+
+	return h
+}
+
+// writeChart writes the given price events into the chart file at the given
+// path and returns the header describing it. Price times are written as unix
+// timestamps in seconds, unless any price time has a fraction of a second.
+// Sell prices are only written in case they differ from the buy prices. Price
+// events sharing their time with the price event in front of them are kept,
+// but the returned header dedupes them while the chart is read.
+func writeChart(path string, prices []informer.Price, volume bool) (chartHeader, error) {
+	h := chartHeader{
+		Buy:  1,
+		Sell: 1,
+		Time: 0,
+	}
+
+	var duplicate bool
+	for n, p := range prices {
+		if p.Time.Nanosecond() != 0 {
+			h.TimeFormat = timeformat.UnixNano
+		}
+		if p.Buy != p.Sell {
+			h.Sell = 2
+		}
+		if n > 0 && p.Time.Equal(prices[n-1].Time) {
+			duplicate = true
+		}
+	}
+	if volume {
+		v := h.Sell + 1
+		h.Volume = &v
+	}
+	if duplicate {
+		h.Quality = &headerQuality{
+			Duplicate: quality.PolicyDedupeKeepLast,
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return chartHeader{}, microerror.MaskAny(err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, p := range prices {
+		var b []byte
+
+		if h.TimeFormat == timeformat.UnixNano {
+			b = strconv.AppendInt(b, p.Time.UnixNano(), 10)
+		} else {
+			b = strconv.AppendInt(b, p.Time.Unix(), 10)
+		}
+		b = append(b, ',')
+		b = strconv.AppendFloat(b, p.Buy, 'f', -1, 64)
+		if h.Sell == 2 {
+			b = append(b, ',')
+			b = strconv.AppendFloat(b, p.Sell, 'f', -1, 64)
+		}
+		if volume {
+			b = append(b, ',')
+			b = strconv.AppendFloat(b, p.Volume, 'f', -1, 64)
+		}
+		b = append(b, '\n')
+
+		_, err := w.Write(b)
+		if err != nil {
+			return chartHeader{}, microerror.MaskAny(err)
+		}
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return chartHeader{}, microerror.MaskAny(err)
+	}
+	err = f.Close()
+	if err != nil {
+		return chartHeader{}, microerror.MaskAny(err)
+	}
+
+	return h, nil
+}
+
+// writeYAML writes the YAML encoding of the given value into the file at the
+// given path.
+func writeYAML(path string, v interface{}) error {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	err = ioutil.WriteFile(path, b, 0644)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
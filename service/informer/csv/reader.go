@@ -0,0 +1,193 @@
+package csv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/juju/errgo"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/chartfile"
+	"github.com/xh3b4sd/wafer/service/informer/csv/column"
+	runtimestatefile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file"
+	statefileheader "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header"
+	"github.com/xh3b4sd/wafer/service/informer/timeformat"
+)
+
+// reader reads the price events of a single CSV file row by row. That way only
+// the current row of a chart is held in memory, regardless of the size of the
+// underlying CSV file. Compressed CSV files are decompressed while being read.
+type reader struct {
+	file   runtimestatefile.File
+	handle *chartfile.Reader
+	line   int
+	reader *csv.Reader
+	time   *timeformat.Parser
+
+	buyIndex    int
+	sellIndex   int
+	timeIndex   int
+	volumeIndex int
+}
+
+// columnIndex connects a configured column with the reader field its resolved
+// index is stored in.
+type columnIndex struct {
+	Column column.Column
+	Index  *int
+}
+
+// newReader opens the CSV file described by the given file and prepares it to
+// be read row by row. Note that the returned reader has to be closed by the
+// caller.
+func newReader(file runtimestatefile.File) (*reader, error) {
+	timeParser, err := timeformat.NewParser(file.Header.TimeFormat, file.Header.TimeZone)
+	if timeformat.IsInvalidConfig(err) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "%s: %s", file.Path, err.Error())
+	} else if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	handle, err := chartfile.Open(file.Path)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	csvReader := csv.NewReader(handle)
+	csvReader.ReuseRecord = true
+
+	newReader := &reader{
+		file:   file,
+		handle: handle,
+		line:   0,
+		reader: csvReader,
+		time:   timeParser,
+	}
+
+	// Columns referenced by name are resolved against the first row of the CSV
+	// file. The first row is then the header row and does not represent actual
+	// data.
+	var names []string
+	if file.Header.Ignore || hasNamedColumns(file.Header) {
+		newReader.line++
+		fields, err := newReader.reader.Read()
+		if err != nil && err != io.EOF {
+			newReader.Close()
+			return nil, newReader.malformed(err)
+		}
+		names = append(names, fields...)
+	}
+
+	columns := []columnIndex{
+		{Column: file.Header.Buy, Index: &newReader.buyIndex},
+		{Column: file.Header.Sell, Index: &newReader.sellIndex},
+		{Column: file.Header.Time, Index: &newReader.timeIndex},
+	}
+	if file.Header.Volume != nil {
+		columns = append(columns, columnIndex{Column: *file.Header.Volume, Index: &newReader.volumeIndex})
+	}
+
+	for _, c := range columns {
+		i, err := c.Column.Resolve(names)
+		if column.IsNotFound(err) {
+			newReader.Close()
+			return nil, microerror.MaskAnyf(invalidConfigError, "%s: column '%s' not found in first row %v", file.Path, c.Column.Name, names)
+		} else if err != nil {
+			newReader.Close()
+			return nil, microerror.MaskAny(err)
+		}
+		*c.Index = i
+	}
+
+	return newReader, nil
+}
+
+// Checksum returns the checksum of the decompressed content of the CSV file
+// read so far.
+func (r *reader) Checksum() string {
+	return r.handle.Checksum()
+}
+
+// Close closes the underlying CSV file.
+func (r *reader) Close() error {
+	err := r.handle.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+// Read parses the next row of the CSV file into a price event. Read returns
+// io.EOF in case there are no more rows to read. Rows which cannot be parsed
+// cause a malformedChartError naming the file and line of the row.
+func (r *reader) Read() (informer.Price, error) {
+	r.line++
+
+	fields, err := r.reader.Read()
+	if err == io.EOF {
+		return informer.Price{}, io.EOF
+	} else if err != nil {
+		return informer.Price{}, r.malformed(err)
+	}
+
+	indizes := []int{r.buyIndex, r.sellIndex, r.timeIndex}
+	if r.file.Header.Volume != nil {
+		indizes = append(indizes, r.volumeIndex)
+	}
+
+	for _, i := range indizes {
+		if i < 0 || i >= len(fields) {
+			return informer.Price{}, r.malformedf("column %d out of range", i)
+		}
+	}
+
+	b, err := strconv.ParseFloat(fields[r.buyIndex], 64)
+	if err != nil {
+		return informer.Price{}, r.malformed(err)
+	}
+	s, err := strconv.ParseFloat(fields[r.sellIndex], 64)
+	if err != nil {
+		return informer.Price{}, r.malformed(err)
+	}
+	t, err := r.time.Parse(fields[r.timeIndex])
+	if err != nil {
+		return informer.Price{}, r.malformed(err)
+	}
+
+	var v float64
+	if r.file.Header.Volume != nil {
+		v, err = strconv.ParseFloat(fields[r.volumeIndex], 64)
+		if err != nil {
+			return informer.Price{}, r.malformed(err)
+		}
+	}
+
+	price := informer.Price{
+		Buy:    b,
+		Sell:   s,
+		Time:   t,
+		Volume: v,
+	}
+
+	return price, nil
+}
+
+func (r *reader) malformed(err error) error {
+	return r.malformedf("%s", errgo.Cause(err).Error())
+}
+
+func (r *reader) malformedf(f string, v ...interface{}) error {
+	return microerror.MaskAnyf(malformedChartError, "%s:%d: %s", r.file.Path, r.line, fmt.Sprintf(f, v...))
+}
+
+func hasNamedColumns(header statefileheader.Header) bool {
+	if header.Volume != nil && header.Volume.IsName() {
+		return true
+	}
+
+	return header.Buy.IsName() || header.Sell.IsName() || header.Time.IsName()
+}
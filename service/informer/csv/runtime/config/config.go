@@ -6,6 +6,8 @@ import (
 )
 
 type Config struct {
-	Dir  dir.Dir
-	File file.File
+	Cache bool
+	Dir   dir.Dir
+	File  file.File
+	Watch bool
 }
@@ -0,0 +1,66 @@
+package meta
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Meta describes the market a chart belongs to. Consider the following
+// header.yaml.
+//
+//     meta:
+//       symbol: BTC-EUR
+//       base: BTC
+//       quote: EUR
+//       ticksize: 0.01
+//       lotsize: 0.00000001
+//       fee:
+//         maker: 0.15
+//         taker: 0.25
+//
+type Meta struct {
+	// Base is the currency being traded, e.g. BTC.
+	Base string
+	// Fee is the fee schedule of the exchange. Fee is nil in case the chart
+	// does not declare fees. Consumers then fall back to their own defaults.
+	Fee *Fee
+	// LotSize is the smallest step traded volumes can be expressed in, in units
+	// of the base currency. LotSize is 0 in case it is not declared.
+	LotSize float64
+	// Quote is the currency prices are expressed in, e.g. EUR.
+	Quote string
+	// Symbol is the name of the market at the exchange, e.g. BTC-EUR.
+	Symbol string
+	// TickSize is the smallest step prices can move, in units of the quote
+	// currency. TickSize is 0 in case it is not declared.
+	TickSize float64
+}
+
+// Fee is the fee schedule of an exchange. Fees are given in percent of the
+// traded amount and are charged for each order.
+type Fee struct {
+	// Maker is the fee charged for orders adding liquidity to the order book.
+	Maker float64
+	// Taker is the fee charged for orders removing liquidity from the order
+	// book, e.g. market orders.
+	Taker float64
+}
+
+func (m Meta) Validate() error {
+	if m.LotSize < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "m.LotSize must not be negative")
+	}
+	if m.TickSize < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "m.TickSize must not be negative")
+	}
+
+	if m.Fee != nil {
+		if m.Fee.Maker < 0 || m.Fee.Maker >= 100 {
+			return microerror.MaskAnyf(invalidConfigError, "m.Fee.Maker must be within [0, 100)")
+		}
+		if m.Fee.Taker < 0 || m.Fee.Taker >= 100 {
+			return microerror.MaskAnyf(invalidConfigError, "m.Fee.Taker must be within [0, 100)")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package quality
+
+import (
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+const (
+	// PolicyIgnore keeps affected price events as they are. This is the default
+	// in case no policy is configured. Affected price events are still counted
+	// in the data quality summary of a chart.
+	PolicyIgnore = "ignore"
+	// PolicyReject causes an error as soon as an affected price event is read.
+	PolicyReject = "reject"
+	// PolicyDrop removes affected price events from a chart.
+	PolicyDrop = "drop"
+	// PolicyDedupeKeepLast removes all but the last of the price events sharing
+	// the same time.
+	PolicyDedupeKeepLast = "dedupe-keep-last"
+	// PolicySort orders the price events of a chart by time. Note that the whole
+	// chart has to be loaded into memory to do so.
+	PolicySort = "sort"
+)
+
+// Quality describes the policies applied to price events of a chart which do
+// not meet certain data quality criteria. Consider the following header.yaml.
+//
+//     quality:
+//       duplicate: dedupe-keep-last
+//       gap: 24h
+//       gappolicy: reject
+//       invalid: drop
+//       order: sort
+//
+type Quality struct {
+	// Duplicate is the policy applied to price events having the same time as
+	// the price event in front of them. One of ignore, reject, drop or
+	// dedupe-keep-last. Note that drop keeps the first of the price events
+	// sharing the same time.
+	Duplicate string
+	// Gap is the maximum duration allowed between two consecutive price events.
+	// Zero disables the detection of gaps.
+	Gap time.Duration
+	// GapPolicy is the policy applied to price events being further away from
+	// the price event in front of them than Gap. One of ignore or reject.
+	GapPolicy string
+	// Invalid is the policy applied to price events having zero, negative or
+	// NaN prices. One of ignore, reject or drop.
+	Invalid string
+	// Order is the policy applied to price events having a time before the time
+	// of the price event in front of them. One of ignore, reject, drop or sort.
+	Order string
+}
+
+func (q Quality) Validate() error {
+	if !isPolicy(q.Duplicate, PolicyIgnore, PolicyReject, PolicyDrop, PolicyDedupeKeepLast) {
+		return microerror.MaskAnyf(invalidConfigError, "Quality.Duplicate must not be '%s'", q.Duplicate)
+	}
+	if q.Gap < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Quality.Gap must not be negative")
+	}
+	if !isPolicy(q.GapPolicy, PolicyIgnore, PolicyReject) {
+		return microerror.MaskAnyf(invalidConfigError, "Quality.GapPolicy must not be '%s'", q.GapPolicy)
+	}
+	if !isPolicy(q.Invalid, PolicyIgnore, PolicyReject, PolicyDrop) {
+		return microerror.MaskAnyf(invalidConfigError, "Quality.Invalid must not be '%s'", q.Invalid)
+	}
+	if !isPolicy(q.Order, PolicyIgnore, PolicyReject, PolicyDrop, PolicySort) {
+		return microerror.MaskAnyf(invalidConfigError, "Quality.Order must not be '%s'", q.Order)
+	}
+
+	return nil
+}
+
+// isPolicy checks whether the given policy is one of the given allowed
+// policies. An empty policy is always allowed, because it defaults to
+// PolicyIgnore.
+func isPolicy(policy string, allowed ...string) bool {
+	if policy == "" {
+		return true
+	}
+
+	for _, a := range allowed {
+		if policy == a {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,17 +1,43 @@
 package header
 
+import (
+	"github.com/xh3b4sd/wafer/service/informer/csv/column"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/quality"
+)
+
 type Header struct {
-	// Buy is the index of the row representing buy prices within the given
-	// CSV file.
-	Buy int
+	// Buy is the index or name of the row representing buy prices within the
+	// given CSV file.
+	Buy column.Column
 	// Ignore decides whether to ignore the first line of the given CSV.
 	// This can be set to true in case the first line does not represent actual
-	// data.
+	// data. Note that the first line is always ignored in case any column is
+	// referenced by name, because the first line then has to provide the column
+	// names.
 	Ignore bool
-	// Sell is the index of the row representing sell prices within the given
-	// CSV file.
-	Sell int
-	// Time is the index of the row representing price times within the given
-	// CSV file.
-	Time int
+	// Meta describes the market the chart belongs to, e.g. its symbol and the
+	// fee schedule of the exchange.
+	Meta meta.Meta
+	// Quality describes the policies applied to price events which do not meet
+	// certain data quality criteria.
+	Quality quality.Quality
+	// Sell is the index or name of the row representing sell prices within the
+	// given CSV file.
+	Sell column.Column
+	// Time is the index or name of the row representing price times within the
+	// given CSV file.
+	Time column.Column
+	// TimeFormat is the format of the price times within the given CSV file. It
+	// can be one of unix, unixmilli, unixnano or rfc3339. Any other value is
+	// treated as Go time layout, e.g. 2006-01-02 15:04:05. Defaults to unix.
+	TimeFormat string
+	// TimeZone is the name of the location price times within the given CSV
+	// file are interpreted in, e.g. UTC or Europe/Berlin. Defaults to the local
+	// time zone.
+	TimeZone string
+	// Volume is the index or name of the row representing traded volumes within
+	// the given CSV file. Volume is optional and can be nil in case the given
+	// CSV file does not provide traded volumes.
+	Volume *column.Column
 }
@@ -0,0 +1,27 @@
+package meta
+
+type Meta struct {
+	// Base is the currency being traded, e.g. BTC.
+	Base string
+	// Fee is the fee schedule of the exchange. Fee is nil in case the chart
+	// does not declare fees.
+	Fee *Fee
+	// LotSize is the smallest step traded volumes can be expressed in. LotSize
+	// is 0 in case it is not declared.
+	LotSize float64
+	// Quote is the currency prices are expressed in, e.g. EUR.
+	Quote string
+	// Symbol is the name of the market at the exchange, e.g. BTC-EUR.
+	Symbol string
+	// TickSize is the smallest step prices can move. TickSize is 0 in case it
+	// is not declared.
+	TickSize float64
+}
+
+// Fee is the fee schedule of an exchange in percent of the traded amount.
+type Fee struct {
+	// Maker is the fee charged for orders adding liquidity.
+	Maker float64
+	// Taker is the fee charged for orders removing liquidity.
+	Taker float64
+}
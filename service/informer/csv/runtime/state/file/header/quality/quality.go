@@ -0,0 +1,22 @@
+package quality
+
+import (
+	"time"
+)
+
+type Quality struct {
+	// Duplicate is the policy applied to price events having the same time as
+	// the price event in front of them.
+	Duplicate string
+	// Gap is the maximum duration allowed between two consecutive price events.
+	Gap time.Duration
+	// GapPolicy is the policy applied to price events being further away from
+	// the price event in front of them than Gap.
+	GapPolicy string
+	// Invalid is the policy applied to price events having zero, negative or
+	// NaN prices.
+	Invalid string
+	// Order is the policy applied to price events having a time before the time
+	// of the price event in front of them.
+	Order string
+}
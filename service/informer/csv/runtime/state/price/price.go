@@ -2,10 +2,27 @@ package price
 
 import (
 	"time"
+
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price/quality"
 )
 
 type Price struct {
-	End    time.Time `json:"end"`
-	Events int       `json:"events"`
-	Start  time.Time `json:"start"`
+	// Charts maps the IDs of merged charts to the markets they belong to. Charts
+	// is empty in case the price events do not originate from merged charts.
+	Charts map[string]meta.Meta `json:"charts"`
+	// Checksum is the hex encoded SHA-256 checksum of the decompressed content
+	// of the chart file. Checksum is empty in case the price events do not
+	// originate from a chart file.
+	Checksum string    `json:"checksum"`
+	End      time.Time `json:"end"`
+	Events   int       `json:"events"`
+	// Meta describes the market the chart belongs to. Meta is empty in case
+	// the chart does not declare it.
+	Meta    meta.Meta       `json:"meta"`
+	Quality quality.Quality `json:"quality"`
+	// Slice is the name of the slice the price events are restricted to. Slice
+	// is empty in case the price events are not restricted.
+	Slice string    `json:"slice"`
+	Start time.Time `json:"start"`
 }
@@ -0,0 +1,26 @@
+package quality
+
+// Quality is the data quality summary of a chart. Each counter represents the
+// number of price events found to be affected by the corresponding issue,
+// regardless of the policy applied to them.
+type Quality struct {
+	// Dropped is the number of price events removed from the chart due to the
+	// configured policies.
+	Dropped int `json:"dropped"`
+	// Duplicates is the number of price events having the same time as the
+	// price event in front of them.
+	Duplicates int `json:"duplicates"`
+	// Gaps is the number of price events being further away from the price
+	// event in front of them than the configured maximum gap.
+	Gaps int `json:"gaps"`
+	// Invalid is the number of price events having zero, negative or NaN
+	// prices.
+	Invalid int `json:"invalid"`
+	// Stale is the number of price events being older than the configured
+	// maximum age at the time they were received. Only informers receiving
+	// price events from live sources detect stale price events.
+	Stale int `json:"stale"`
+	// Unordered is the number of price events having a time before the time of
+	// the price event in front of them.
+	Unordered int `json:"unordered"`
+}
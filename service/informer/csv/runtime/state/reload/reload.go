@@ -0,0 +1,17 @@
+package reload
+
+import (
+	"time"
+)
+
+type Reload struct {
+	// Error is the error of the most recent reload, in case it failed. The
+	// charts of the current generation are kept when a reload fails.
+	Error string `json:"error"`
+	// Generation is the number of times the charts were reloaded successfully.
+	// Generation 0 describes the charts loaded initially. Results computed on
+	// the same generation are based on the same chart data.
+	Generation int `json:"generation"`
+	// Time is the time the charts of the current generation were loaded.
+	Time time.Time `json:"time"`
+}
@@ -3,9 +3,13 @@ package state
 import (
 	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file"
 	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/reload"
+	"github.com/xh3b4sd/wafer/service/informer/runtime/state/connection"
 )
 
 type State struct {
-	Files  []file.File   `json:"files"`
-	Prices []price.Price `json:"price"`
+	Connection connection.Connection `json:"connection"`
+	Files      []file.File           `json:"files"`
+	Prices     []price.Price         `json:"price"`
+	Reload     reload.Reload         `json:"reload"`
 }
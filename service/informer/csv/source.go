@@ -0,0 +1,64 @@
+package csv
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer/cleaner"
+	configquality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/quality"
+	runtimestatefile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file"
+)
+
+// newSource creates the source of price events for the given file. The
+// returned source applies the data quality policies configured in the header
+// of the given file. In case cache is true and the cache of the given file is
+// up to date, the price events are read from the cache. Otherwise the CSV file
+// is parsed. Note that the returned source has to be closed by the caller.
+func newSource(file runtimestatefile.File, cache bool) (*cleaner.Cleaner, error) {
+	var r cleaner.Source
+	if cache {
+		c, err := openCache(file)
+		if err == nil {
+			r = c
+		}
+	}
+	if r == nil {
+		c, err := newReader(file)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+		r = c
+	}
+
+	c, err := newCleaner(file, r)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return c, nil
+}
+
+// newCleaner wraps the given source to apply the data quality policies
+// configured in the header of the given file. The source is closed in case the
+// cleaner cannot be created.
+func newCleaner(file runtimestatefile.File, r cleaner.Source) (*cleaner.Cleaner, error) {
+	cleanerConfig := cleaner.DefaultConfig()
+	cleanerConfig.Path = file.Path
+	cleanerConfig.Quality = configquality.Quality{
+		Duplicate: file.Header.Quality.Duplicate,
+		Gap:       file.Header.Quality.Gap,
+		GapPolicy: file.Header.Quality.GapPolicy,
+		Invalid:   file.Header.Quality.Invalid,
+		Order:     file.Header.Quality.Order,
+	}
+	cleanerConfig.Source = r
+	c, err := cleaner.New(cleanerConfig)
+	if cleaner.IsInvalidConfig(err) {
+		r.Close()
+		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+	} else if err != nil {
+		r.Close()
+		return nil, microerror.MaskAny(err)
+	}
+
+	return c, nil
+}
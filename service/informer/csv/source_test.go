@@ -0,0 +1,181 @@
+package csv
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/cleaner"
+	"github.com/xh3b4sd/wafer/service/informer/csv/column"
+	runtimestatefile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file"
+	statefileheader "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header"
+	statequality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/quality"
+	pricequality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price/quality"
+)
+
+// testChart contains a duplicate time at line 3, an invalid price at line 4, an
+// unordered time at line 5, and a gap of 100 seconds at line 7.
+const testChart = `1,10,10
+2,11,11
+2,12,12
+3,0,13
+1,14,14
+4,15,15
+104,16,16
+`
+
+func Test_newSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wafer-csv")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "chart.csv")
+	err = ioutil.WriteFile(path, []byte(testChart), 0644)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Quality         statequality.Quality
+		ExpectedTimes   []int64
+		ExpectedSummary pricequality.Quality
+		ErrorMatcher    func(err error) bool
+	}{
+		// Test case 1 makes sure the default policies keep all price events but
+		// still count the data quality issues.
+		{
+			Quality:       statequality.Quality{},
+			ExpectedTimes: []int64{1, 2, 2, 3, 1, 4, 104},
+			ExpectedSummary: pricequality.Quality{
+				Dropped:    0,
+				Duplicates: 1,
+				Gaps:       0,
+				Invalid:    1,
+				Unordered:  1,
+			},
+			ErrorMatcher: nil,
+		},
+		// Test case 2 makes sure affected price events can be dropped and gaps are
+		// detected.
+		{
+			Quality: statequality.Quality{
+				Duplicate: "drop",
+				Gap:       time.Minute,
+				Invalid:   "drop",
+				Order:     "drop",
+			},
+			ExpectedTimes: []int64{1, 2, 4, 104},
+			ExpectedSummary: pricequality.Quality{
+				Dropped:    3,
+				Duplicates: 1,
+				Gaps:       1,
+				Invalid:    1,
+				Unordered:  1,
+			},
+			ErrorMatcher: nil,
+		},
+		// Test case 3 makes sure sorting and keeping the last duplicate work
+		// together.
+		{
+			Quality: statequality.Quality{
+				Duplicate: "dedupe-keep-last",
+				Order:     "sort",
+			},
+			ExpectedTimes: []int64{1, 2, 3, 4, 104},
+			ExpectedSummary: pricequality.Quality{
+				Dropped:    2,
+				Duplicates: 2,
+				Gaps:       0,
+				Invalid:    1,
+				Unordered:  0,
+			},
+			ErrorMatcher: nil,
+		},
+		// Test case 4 makes sure duplicates can be rejected.
+		{
+			Quality: statequality.Quality{
+				Duplicate: "reject",
+			},
+			ExpectedTimes:   nil,
+			ExpectedSummary: pricequality.Quality{},
+			ErrorMatcher:    IsMalformedChart,
+		},
+		// Test case 5 makes sure gaps can be rejected.
+		{
+			Quality: statequality.Quality{
+				Gap:       time.Minute,
+				GapPolicy: "reject",
+			},
+			ExpectedTimes:   nil,
+			ExpectedSummary: pricequality.Quality{},
+			ErrorMatcher:    IsMalformedChart,
+		},
+		// Test case 6 makes sure unknown policies cause an error.
+		{
+			Quality: statequality.Quality{
+				Order: "shuffle",
+			},
+			ExpectedTimes:   nil,
+			ExpectedSummary: pricequality.Quality{},
+			ErrorMatcher:    IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		file := runtimestatefile.File{
+			Header: statefileheader.Header{
+				Buy:     column.Index(1),
+				Quality: testCase.Quality,
+				Sell:    column.Index(2),
+				Time:    column.Index(0),
+			},
+			Path: path,
+		}
+
+		var err error
+		var times []int64
+		var summary pricequality.Quality
+		{
+			var s *cleaner.Cleaner
+			s, err = newSource(file, false)
+			if err == nil {
+				for {
+					var p informer.Price
+					p, err = s.Read()
+					if err != nil {
+						break
+					}
+					times = append(times, p.Time.Unix())
+				}
+				summary = s.Summary()
+				s.Close()
+			}
+			if err == io.EOF {
+				err = nil
+			}
+		}
+
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !reflect.DeepEqual(times, testCase.ExpectedTimes) {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedTimes, "got", times)
+		}
+		if !reflect.DeepEqual(summary, testCase.ExpectedSummary) {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedSummary, "got", summary)
+		}
+	}
+}
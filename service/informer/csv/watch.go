@@ -0,0 +1,124 @@
+package csv
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer/chartcache"
+	runtimestatefile "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file"
+)
+
+// watch makes the watcher observe the CSV dir and the chart dirs of the given
+// files. Chart dirs which are already observed are not affected.
+func (i *Informer) watch(files []runtimestatefile.File) error {
+	err := i.watcher.Add(i.config.Dir.Path)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	for _, f := range files {
+		err := i.watcher.Add(filepath.Dir(f.Path))
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+	}
+
+	return nil
+}
+
+// reloadOnChange reloads the charts as soon as the CSV dir did not change for
+// the configured delay. reloadOnChange returns once the informer is closed.
+func (i *Informer) reloadOnChange() {
+	var delay <-chan time.Time
+
+	for {
+		select {
+		case <-i.done:
+			return
+		case e, ok := <-i.watcher.Events:
+			if !ok {
+				return
+			}
+			if !i.isRelevant(e) {
+				continue
+			}
+
+			// New chart dirs have to be observed right away, so that charts being
+			// written into them are noticed.
+			if e.Op&fsnotify.Create != 0 && filepath.Dir(e.Name) == filepath.Clean(i.config.Dir.Path) {
+				fi, err := os.Stat(e.Name)
+				if err == nil && fi.IsDir() {
+					i.watcher.Add(e.Name)
+				}
+			}
+
+			delay = time.After(i.config.WatchDelay)
+		case err, ok := <-i.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			i.observeReload(err)
+		case <-delay:
+			delay = nil
+			i.reload()
+		}
+	}
+}
+
+// isRelevant returns true in case the given event may change the charts of the
+// CSV dir. Changes of the binary caches the informer writes itself are not
+// relevant, just like mere changes of file permissions.
+func (i *Informer) isRelevant(e fsnotify.Event) bool {
+	if e.Op == fsnotify.Chmod {
+		return false
+	}
+
+	name := filepath.Base(e.Name)
+	if name == chartcache.Path("chart.csv") || chartcache.IsTemp(name) {
+		return false
+	}
+
+	return true
+}
+
+// reload loads the charts of the CSV dir again. In case loading succeeds, the
+// loaded charts replace the charts of the current generation and the reload
+// generation is incremented. Otherwise the charts of the current generation
+// are kept.
+func (i *Informer) reload() {
+	files, prices, err := load(i.config)
+	if err != nil {
+		i.observeReload(err)
+		return
+	}
+
+	err = i.watch(files)
+	if err != nil {
+		i.observeReload(err)
+		return
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.files = files
+	i.runtime.State.Files = files
+	i.runtime.State.Prices = prices
+	i.runtime.State.Reload.Error = ""
+	i.runtime.State.Reload.Generation++
+	i.runtime.State.Reload.Time = time.Now()
+}
+
+// observeReload records the given error of a failed reload in the runtime
+// state of the informer.
+func (i *Informer) observeReload(err error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.runtime.State.Reload.Error = err.Error()
+}
@@ -0,0 +1,142 @@
+package jsonl
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	microerror "github.com/giantswarm/microkit/error"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/xh3b4sd/wafer/service/informer/chartfile"
+	runtimeconfigdir "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/dir"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/mapping"
+)
+
+func dirToCharts(dir runtimeconfigdir.Dir) ([]chart, error) {
+	var charts []chart
+
+	outerFileInfos, err := ioutil.ReadDir(dir.Path)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	for _, ofi := range outerFileInfos {
+		if !ofi.IsDir() {
+			return nil, microerror.MaskAnyf(invalidExecutionError, "outer JSON Lines dir must not contain files")
+		}
+
+		var c chart
+		var hasMapping bool
+
+		innerFileInfos, err := ioutil.ReadDir(filepath.Join(dir.Path, ofi.Name()))
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+		for _, ifi := range innerFileInfos {
+			if ifi.IsDir() {
+				return nil, microerror.MaskAnyf(invalidExecutionError, "inner JSON Lines dir must not contain dirs")
+			}
+
+			// The chart data may be compressed, e.g. chart.jsonl.gz. Compressed
+			// charts are decompressed while being read.
+			if chartfile.Base(ifi.Name()) == "chart.jsonl" {
+				if c.Path != "" {
+					return nil, microerror.MaskAnyf(invalidExecutionError, "multiple charts '%s' and '%s' not allowed", filepath.Base(c.Path), ifi.Name())
+				}
+				c.Path = filepath.Join(dir.Path, ofi.Name(), ifi.Name())
+				continue
+			}
+
+			if ifi.Name() == "mapping.yaml" {
+				b, err := ioutil.ReadFile(filepath.Join(dir.Path, ofi.Name(), ifi.Name()))
+				if err != nil {
+					return nil, microerror.MaskAny(err)
+				}
+
+				var m mapping.Mapping
+				err = yaml.Unmarshal(b, &m)
+				if err != nil {
+					return nil, microerror.MaskAny(err)
+				}
+
+				c.Mapping = m
+				hasMapping = true
+
+				continue
+			}
+
+			// We accept a README.md to be able to provide useful information on the
+			// chart data stored in the chart directory.
+			if ifi.Name() == "README.md" {
+				continue
+			}
+
+			return nil, microerror.MaskAnyf(invalidExecutionError, "additional file '%s' not allowed", ifi.Name())
+		}
+
+		if c.Path == "" {
+			return nil, microerror.MaskAnyf(invalidExecutionError, "chart.jsonl or chart.jsonl.gz missing in '%s'", ofi.Name())
+		}
+		if !hasMapping {
+			return nil, microerror.MaskAnyf(invalidExecutionError, "mapping.yaml missing in '%s'", ofi.Name())
+		}
+
+		charts = append(charts, c)
+	}
+
+	return charts, nil
+}
+
+// chartsToPrices pre-scans the given charts to gather the statistical
+// information about them. The lines are read one by one, so the charts are
+// never loaded into memory as a whole. Malformed charts are detected before any
+// price event is handed out to consumers.
+func chartsToPrices(charts []chart) ([]stateprice.Price, error) {
+	var prices []stateprice.Price
+
+	for _, c := range charts {
+		price, err := chartToPrice(c)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+}
+
+func chartToPrice(c chart) (stateprice.Price, error) {
+	r, err := newReader(c)
+	if err != nil {
+		return stateprice.Price{}, microerror.MaskAny(err)
+	}
+	s, err := newCleaner(c, r)
+	if err != nil {
+		return stateprice.Price{}, microerror.MaskAny(err)
+	}
+	defer s.Close()
+
+	var price stateprice.Price
+
+	for {
+		p, err := s.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return stateprice.Price{}, microerror.MaskAny(err)
+		}
+
+		if price.Events == 0 {
+			price.Start = p.Time
+		}
+		price.End = p.Time
+		price.Events++
+	}
+
+	price.Checksum = r.Checksum()
+	price.Quality = s.Summary()
+
+	return price, nil
+}
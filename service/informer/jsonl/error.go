@@ -0,0 +1,29 @@
+package jsonl
+
+import (
+	"github.com/juju/errgo"
+
+	"github.com/xh3b4sd/wafer/service/informer/cleaner"
+)
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var invalidExecutionError = errgo.New("invalid execution")
+
+// IsInvalidExecution asserts invalidExecutionError.
+func IsInvalidExecution(err error) bool {
+	return errgo.Cause(err) == invalidExecutionError
+}
+
+var malformedChartError = errgo.New("malformed chart")
+
+// IsMalformedChart asserts malformedChartError. Price events rejected due to
+// the data quality policies of a chart are reported as malformed chart as well.
+func IsMalformedChart(err error) bool {
+	return errgo.Cause(err) == malformedChartError || cleaner.IsMalformedChart(err)
+}
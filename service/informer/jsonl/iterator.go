@@ -0,0 +1,93 @@
+package jsonl
+
+import (
+	"io"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/cleaner"
+)
+
+// iterator implements informer.Iterator for a single JSON Lines chart. The
+// underlying file is opened lazily with the first call to Next, so creating
+// iterators for a lot of charts does not exhaust file descriptors.
+type iterator struct {
+	ctx    context.Context
+	done   bool
+	err    error
+	chart  chart
+	price  informer.Price
+	source cleaner.Source
+}
+
+func newIterator(ctx context.Context, c chart) *iterator {
+	return &iterator{
+		ctx:   ctx,
+		chart: c,
+	}
+}
+
+func (i *iterator) Close() error {
+	i.done = true
+
+	if i.source != nil {
+		err := i.source.Close()
+		i.source = nil
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+	}
+
+	return nil
+}
+
+func (i *iterator) Err() error {
+	return i.err
+}
+
+func (i *iterator) Next() bool {
+	if i.done {
+		return false
+	}
+
+	select {
+	case <-i.ctx.Done():
+		i.fail(i.ctx.Err())
+		return false
+	default:
+	}
+
+	if i.source == nil {
+		s, err := newSource(i.chart)
+		if err != nil {
+			i.fail(err)
+			return false
+		}
+		i.source = s
+	}
+
+	p, err := i.source.Read()
+	if err == io.EOF {
+		i.Close()
+		return false
+	} else if err != nil {
+		i.fail(err)
+		return false
+	}
+
+	i.price = p
+
+	return true
+}
+
+func (i *iterator) Price() informer.Price {
+	return i.price
+}
+
+// fail stops the iterator and remembers the given error to be returned by Err.
+func (i *iterator) fail(err error) {
+	i.err = microerror.MaskAny(err)
+	i.Close()
+}
@@ -0,0 +1,110 @@
+// Package jsonl provides the implementation of an informer able to read charts
+// stored as JSON Lines, also known as newline delimited JSON. Each line of a
+// chart is a JSON document describing a single price event.
+package jsonl
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	runtimeconfig "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config"
+	runtimeconfigdir "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/dir"
+	runtimestate "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state"
+)
+
+// Config is the configuration used to create a new informer.
+type Config struct {
+	// Settings.
+
+	// Dir is the config for an absolute location of the JSON Lines dir to
+	// consume. The dir is laid out like the CSV dir described by
+	// runtimeconfigdir.Dir. Each inner dir has to contain the chart data in a
+	// file named chart.jsonl, or chart.jsonl.gz in case it is gzip compressed,
+	// and the mapping of its JSON documents in a file named mapping.yaml.
+	//
+	//    Dir
+	//    ├── 001
+	//    │   ├── chart.jsonl
+	//    │   └── mapping.yaml
+	//    └── ...
+	//
+	Dir runtimeconfigdir.Dir
+}
+
+// DefaultConfig returns the default configuration used to create a new informer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Settings.
+		Dir: runtimeconfigdir.Dir{},
+	}
+}
+
+// New creates a new configured informer.
+func New(config Config) (informer.Informer, error) {
+	// Settings.
+	err := config.Dir.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Dir must be given")
+	}
+
+	charts, err := dirToCharts(config.Dir)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	if len(charts) == 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "JSON Lines dir must contain at least one chart")
+	}
+
+	prices, err := chartsToPrices(charts)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	for _, p := range prices {
+		if p.Events < 2 {
+			return nil, microerror.MaskAnyf(invalidConfigError, "chart must contain at least 2 price events")
+		}
+	}
+
+	newInformer := &Informer{
+		// Internals.
+		charts: charts,
+		runtime: runtime.Runtime{
+			Config: runtimeconfig.Config{
+				Dir: config.Dir,
+			},
+			State: runtimestate.State{
+				Prices: prices,
+			},
+		},
+	}
+
+	return newInformer, nil
+}
+
+// Informer implements informer.Informer.
+type Informer struct {
+	// Internals.
+	charts  []chart
+	runtime runtime.Runtime
+}
+
+// Prices returns a list of iterators providing price events, one for each
+// chart. Each call of Prices creates new iterators, which open their underlying
+// files on their own and read their lines lazily.
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	var iterators []informer.Iterator
+
+	for _, c := range i.charts {
+		iterators = append(iterators, newIterator(ctx, c))
+	}
+
+	return iterators, nil
+}
+
+func (i *Informer) Runtime() runtime.Runtime {
+	return i.runtime
+}
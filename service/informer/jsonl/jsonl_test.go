@@ -0,0 +1,202 @@
+package jsonl
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	runtimeconfigdir "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/dir"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+)
+
+func Test_Informer_Prices(t *testing.T) {
+	path, err := filepath.Abs("./fixtures/dir/")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.Dir = runtimeconfigdir.Dir{
+		Path: path,
+	}
+	newInformer, err := New(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	expected := []map[int]informer.Price{
+		{
+			0: {
+				Buy:    797.4000000000,
+				Sell:   797.0000000000,
+				Time:   time.Unix(1391212802, 0),
+				Volume: 4327.8020100000,
+			},
+			9: {
+				Buy:    796.9000000000,
+				Sell:   793.0000000000,
+				Time:   time.Unix(1391213342, 0),
+				Volume: 4393.2324500000,
+			},
+		},
+		{
+			0: {
+				Buy:  797.4000000000,
+				Sell: 797.0000000000,
+				Time: time.Unix(1391212802, 0).UTC(),
+			},
+			// The blank line in front of the fourth price event must be skipped.
+			3: {
+				Buy:  798.8610000000,
+				Sell: 797.0000000000,
+				Time: time.Unix(1391212981, 0).UTC(),
+			},
+		},
+	}
+
+	// There are two chart dirs, so there must be two iterators.
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(iterators) != 2 {
+		t.Fatal("expected", 2, "got", len(iterators))
+	}
+
+	for d, it := range iterators {
+		var j int
+		for it.Next() {
+			p, ok := expected[d][j]
+			if ok && !reflect.DeepEqual(it.Price(), p) {
+				t.Fatal("chart", d+1, "event", j, "expected", p, "got", it.Price())
+			}
+
+			j++
+		}
+		if it.Err() != nil {
+			t.Fatal("chart", d+1, "expected", nil, "got", it.Err())
+		}
+		it.Close()
+	}
+}
+
+func Test_Informer_Runtime_Prices(t *testing.T) {
+	path, err := filepath.Abs("./fixtures/dir/")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	newConfig := DefaultConfig()
+	newConfig.Dir.Path = path
+	newInformer, err := New(newConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	expected := []stateprice.Price{
+		{
+			Checksum: "f6c141fa1bb4e3a639d8d7fd5ce6624dd74311f6892f7fcc4f49bb6ee1dcaa07",
+			End:      time.Unix(1391213342, 0),
+			Events:   10,
+			Start:    time.Unix(1391212802, 0),
+		},
+		{
+			Checksum: "221abf1bd90e229799f3fdfc96bff50c4f4cec71fbe1dbf1e8a319d934ea4c16",
+			End:      time.Unix(1391213041, 0).UTC(),
+			Events:   5,
+			Start:    time.Unix(1391212802, 0).UTC(),
+		},
+	}
+
+	prices := newInformer.Runtime().State.Prices
+	if !reflect.DeepEqual(prices, expected) {
+		t.Fatal("expected", expected, "got", prices)
+	}
+}
+
+func Test_Informer_Error(t *testing.T) {
+	testCases := []struct {
+		Mapping      string
+		Chart        string
+		Message      string
+		ErrorMatcher func(err error) bool
+	}{
+		// Test case 1 makes sure lines which are no JSON documents are reported
+		// along with their location.
+		{
+			Mapping:      "buy: b\nsell: s\ntime: t\n",
+			Chart:        "{\"b\":2,\"s\":1,\"t\":1}\n{\"b\":2,\"s\":1,\"t\":2}\nfoo\n",
+			Message:      "chart.jsonl:3:",
+			ErrorMatcher: IsMalformedChart,
+		},
+		// Test case 2 makes sure missing values are reported along with their
+		// location.
+		{
+			Mapping:      "buy: b\nsell: s\ntime: t\n",
+			Chart:        "{\"b\":2,\"s\":1,\"t\":1}\n\n{\"b\":2,\"t\":2}\n",
+			Message:      "chart.jsonl:3:",
+			ErrorMatcher: IsMalformedChart,
+		},
+		// Test case 3 makes sure values which are neither numbers nor strings are
+		// rejected.
+		{
+			Mapping:      "buy: b\nsell: s\ntime: t\n",
+			Chart:        "{\"b\":2,\"s\":{},\"t\":1}\n{\"b\":2,\"s\":1,\"t\":2}\n",
+			Message:      "chart.jsonl:1:",
+			ErrorMatcher: IsMalformedChart,
+		},
+		// Test case 4 makes sure the data quality policies of the mapping are
+		// applied.
+		{
+			Mapping:      "buy: b\nsell: s\ntime: t\nquality:\n  duplicate: reject\n",
+			Chart:        "{\"b\":2,\"s\":1,\"t\":1}\n{\"b\":2,\"s\":1,\"t\":1}\n",
+			Message:      "duplicate price event",
+			ErrorMatcher: IsMalformedChart,
+		},
+		// Test case 5 makes sure invalid mappings are rejected.
+		{
+			Mapping:      "buy: b\nsell: s\ntime: b\n",
+			Chart:        "{\"b\":2,\"s\":1,\"t\":1}\n{\"b\":2,\"s\":1,\"t\":2}\n",
+			Message:      "m.Buy must not be equal to m.Time",
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		dir, err := ioutil.TempDir("", "wafer-jsonl")
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		defer os.RemoveAll(dir)
+
+		err = os.Mkdir(filepath.Join(dir, "001"), 0755)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, "001", "mapping.yaml"), []byte(testCase.Mapping), 0644)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, "001", "chart.jsonl"), []byte(testCase.Chart), 0644)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		newConfig := DefaultConfig()
+		newConfig.Dir.Path = dir
+		_, err = New(newConfig)
+		if !testCase.ErrorMatcher(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+		if !strings.Contains(err.Error(), testCase.Message) {
+			t.Fatal("case", i+1, "expected", testCase.Message, "got", err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package jsonpath
+
+import (
+	"github.com/juju/errgo"
+)
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var notFoundError = errgo.New("not found")
+
+// IsNotFound asserts notFoundError.
+func IsNotFound(err error) bool {
+	return errgo.Cause(err) == notFoundError
+}
+
+var invalidValueError = errgo.New("invalid value")
+
+// IsInvalidValue asserts invalidValueError.
+func IsInvalidValue(err error) bool {
+	return errgo.Cause(err) == invalidValueError
+}
@@ -0,0 +1,105 @@
+// Package jsonpath provides a way to reference values within JSON documents
+// using a simple subset of the JSON path notation. Object keys are separated by
+// dots and array elements are referenced by their zero based index, either in
+// brackets or as dot separated segment. The following paths are equivalent.
+//
+//     $.data[0].price
+//     data.0.price
+//
+package jsonpath
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Path references a value within a JSON document.
+type Path struct {
+	raw      string
+	segments []string
+}
+
+// Parse parses the given string into a path. An empty string or a path
+// containing empty segments causes an invalidConfigError.
+func Parse(s string) (Path, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(s, "$"), ".")
+	if trimmed == "" {
+		return Path{}, microerror.MaskAnyf(invalidConfigError, "path must not be empty")
+	}
+
+	// Brackets are turned into dot separated segments, so data[0].price becomes
+	// data.0.price before splitting the path.
+	trimmed = strings.Replace(trimmed, "[", ".", -1)
+	trimmed = strings.Replace(trimmed, "]", "", -1)
+
+	segments := strings.Split(trimmed, ".")
+	for _, seg := range segments {
+		if seg == "" {
+			return Path{}, microerror.MaskAnyf(invalidConfigError, "path '%s' must not contain empty segments", s)
+		}
+	}
+
+	p := Path{
+		raw:      s,
+		segments: segments,
+	}
+
+	return p, nil
+}
+
+// Lookup returns the value the path references within the given document. The
+// document is expected to be decoded by encoding/json into an interface{}. In
+// case the referenced value does not exist, a notFoundError is returned.
+func (p Path) Lookup(v interface{}) (interface{}, error) {
+	for _, seg := range p.segments {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			e, ok := t[seg]
+			if !ok {
+				return nil, microerror.MaskAnyf(notFoundError, "path '%s': key '%s' not found", p.raw, seg)
+			}
+			v = e
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil {
+				return nil, microerror.MaskAnyf(notFoundError, "path '%s': '%s' is no array index", p.raw, seg)
+			}
+			if i < 0 || i >= len(t) {
+				return nil, microerror.MaskAnyf(notFoundError, "path '%s': array index %d out of range", p.raw, i)
+			}
+			v = t[i]
+		default:
+			return nil, microerror.MaskAnyf(notFoundError, "path '%s': '%s' cannot be looked up in scalar value", p.raw, seg)
+		}
+	}
+
+	return v, nil
+}
+
+// Scalar returns the string representation of the value the path references
+// within the given document. Only JSON numbers and JSON strings are accepted,
+// which requires the document to be decoded using json.Decoder.UseNumber. In
+// case the referenced value is no scalar, an invalidValueError is returned.
+func (p Path) Scalar(v interface{}) (string, error) {
+	e, err := p.Lookup(v)
+	if err != nil {
+		return "", microerror.MaskAny(err)
+	}
+
+	switch t := e.(type) {
+	case json.Number:
+		return t.String(), nil
+	case string:
+		return t, nil
+	}
+
+	return "", microerror.MaskAnyf(invalidValueError, "path '%s' must reference number or string", p.raw)
+}
+
+// String returns the path as it was given to Parse.
+func (p Path) String() string {
+	return p.raw
+}
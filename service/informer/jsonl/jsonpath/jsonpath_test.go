@@ -0,0 +1,170 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_Path_Lookup(t *testing.T) {
+	document := `{"ticker":{"buy":"797.4","sell":797},"data":[{"price":1},{"price":2}]}`
+
+	var v interface{}
+	err := json.Unmarshal([]byte(document), &v)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Path         string
+		Expected     interface{}
+		ErrorMatcher func(err error) bool
+	}{
+		// Test case 1 makes sure object keys can be looked up.
+		{
+			Path:         "ticker.buy",
+			Expected:     "797.4",
+			ErrorMatcher: nil,
+		},
+		// Test case 2 makes sure the root prefix is accepted.
+		{
+			Path:         "$.ticker.sell",
+			Expected:     float64(797),
+			ErrorMatcher: nil,
+		},
+		// Test case 3 makes sure array elements can be referenced in brackets.
+		{
+			Path:         "$.data[1].price",
+			Expected:     float64(2),
+			ErrorMatcher: nil,
+		},
+		// Test case 4 makes sure array elements can be referenced as segment.
+		{
+			Path:         "data.0.price",
+			Expected:     float64(1),
+			ErrorMatcher: nil,
+		},
+		// Test case 5 makes sure missing keys cause an error.
+		{
+			Path:         "ticker.volume",
+			Expected:     nil,
+			ErrorMatcher: IsNotFound,
+		},
+		// Test case 6 makes sure out of range array indizes cause an error.
+		{
+			Path:         "data[2].price",
+			Expected:     nil,
+			ErrorMatcher: IsNotFound,
+		},
+		// Test case 7 makes sure scalar values cannot be looked into.
+		{
+			Path:         "ticker.buy.value",
+			Expected:     nil,
+			ErrorMatcher: IsNotFound,
+		},
+		// Test case 8 makes sure empty paths cause an error.
+		{
+			Path:         "$",
+			Expected:     nil,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 9 makes sure empty segments cause an error.
+		{
+			Path:         "ticker..buy",
+			Expected:     nil,
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		var value interface{}
+		p, err := Parse(testCase.Path)
+		if err == nil {
+			value, err = p.Lookup(v)
+		}
+
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !reflect.DeepEqual(value, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", value)
+		}
+	}
+}
+
+func Test_Path_Scalar(t *testing.T) {
+	document := `{"ticker":{"buy":"797.4","sell":797.25,"open":true},"data":[1,2]}`
+
+	var v interface{}
+	d := json.NewDecoder(strings.NewReader(document))
+	d.UseNumber()
+	err := d.Decode(&v)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Path         string
+		Expected     string
+		ErrorMatcher func(err error) bool
+	}{
+		// Test case 1 makes sure JSON strings are returned as they are.
+		{
+			Path:         "ticker.buy",
+			Expected:     "797.4",
+			ErrorMatcher: nil,
+		},
+		// Test case 2 makes sure JSON numbers keep their original precision.
+		{
+			Path:         "ticker.sell",
+			Expected:     "797.25",
+			ErrorMatcher: nil,
+		},
+		// Test case 3 makes sure JSON booleans are rejected.
+		{
+			Path:         "ticker.open",
+			Expected:     "",
+			ErrorMatcher: IsInvalidValue,
+		},
+		// Test case 4 makes sure JSON arrays are rejected.
+		{
+			Path:         "data",
+			Expected:     "",
+			ErrorMatcher: IsInvalidValue,
+		},
+		// Test case 5 makes sure missing keys cause an error.
+		{
+			Path:         "ticker.volume",
+			Expected:     "",
+			ErrorMatcher: IsNotFound,
+		},
+	}
+
+	for i, testCase := range testCases {
+		p, err := Parse(testCase.Path)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		value, err := p.Scalar(v)
+
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if value != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", value)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Package mapping provides the mapping of price event fields to values within
+// the JSON documents of a JSON Lines chart. Each chart dir of the JSON Lines
+// informer contains a mapping.yaml file like the following.
+//
+//     buy: ticker.buy
+//     sell: ticker.sell
+//     time: ticker.server_time
+//     volume: ticker.vol_cur
+//     timeformat: unix
+//
+package mapping
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+
+	configquality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/quality"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/jsonpath"
+)
+
+type Mapping struct {
+	// Buy is the JSON path of the value representing buy prices within each
+	// line of the given chart.
+	Buy string
+	// Quality describes the policies applied to price events which do not meet
+	// certain data quality criteria.
+	Quality configquality.Quality
+	// Sell is the JSON path of the value representing sell prices within each
+	// line of the given chart.
+	Sell string
+	// Time is the JSON path of the value representing price times within each
+	// line of the given chart.
+	Time string
+	// TimeFormat is the format of the price times within the given chart. It can
+	// be one of unix, unixmilli, unixnano or rfc3339. Any other value is treated
+	// as Go time layout, e.g. 2006-01-02 15:04:05. Defaults to unix.
+	TimeFormat string
+	// TimeZone is the name of the location price times within the given chart
+	// are interpreted in, e.g. UTC or Europe/Berlin. Defaults to the local time
+	// zone.
+	TimeZone string
+	// Volume is the JSON path of the value representing traded volumes within
+	// each line of the given chart. Volume is optional and can be empty in case
+	// the given chart does not provide traded volumes.
+	Volume string
+}
+
+func (m Mapping) Validate() error {
+	paths := map[string]string{
+		"m.Buy":  m.Buy,
+		"m.Sell": m.Sell,
+		"m.Time": m.Time,
+	}
+	if m.Volume != "" {
+		paths["m.Volume"] = m.Volume
+	}
+
+	for name, p := range paths {
+		_, err := jsonpath.Parse(p)
+		if err != nil {
+			return microerror.MaskAnyf(invalidConfigError, "%s: %s", name, err.Error())
+		}
+	}
+
+	if m.Buy == m.Time {
+		return microerror.MaskAnyf(invalidConfigError, "m.Buy must not be equal to m.Time")
+	}
+	if m.Sell == m.Time {
+		return microerror.MaskAnyf(invalidConfigError, "m.Sell must not be equal to m.Time")
+	}
+	if m.Volume != "" && m.Volume == m.Time {
+		return microerror.MaskAnyf(invalidConfigError, "m.Volume must not be equal to m.Time")
+	}
+
+	err := m.Quality.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/juju/errgo"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/chartfile"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/jsonpath"
+	"github.com/xh3b4sd/wafer/service/informer/timeformat"
+)
+
+// reader reads the price events of a single JSON Lines file line by line. That
+// way only the current line of a chart is held in memory, regardless of the
+// size of the underlying file. Compressed files are decompressed while being
+// read.
+type reader struct {
+	chart  chart
+	handle *chartfile.Reader
+	line   int
+	reader *bufio.Reader
+	time   *timeformat.Parser
+
+	buy    jsonpath.Path
+	sell   jsonpath.Path
+	volume *jsonpath.Path
+	when   jsonpath.Path
+}
+
+// newReader opens the JSON Lines file described by the given chart and
+// prepares it to be read line by line. Note that the returned reader has to be
+// closed by the caller.
+func newReader(c chart) (*reader, error) {
+	err := c.Mapping.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "%s: %s", c.Path, err.Error())
+	}
+
+	timeParser, err := timeformat.NewParser(c.Mapping.TimeFormat, c.Mapping.TimeZone)
+	if timeformat.IsInvalidConfig(err) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "%s: %s", c.Path, err.Error())
+	} else if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	// The paths have been validated above, so parsing them cannot fail anymore.
+	buy, _ := jsonpath.Parse(c.Mapping.Buy)
+	sell, _ := jsonpath.Parse(c.Mapping.Sell)
+	when, _ := jsonpath.Parse(c.Mapping.Time)
+
+	var volume *jsonpath.Path
+	if c.Mapping.Volume != "" {
+		p, _ := jsonpath.Parse(c.Mapping.Volume)
+		volume = &p
+	}
+
+	handle, err := chartfile.Open(c.Path)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	newReader := &reader{
+		chart:  c,
+		handle: handle,
+		line:   0,
+		reader: bufio.NewReader(handle),
+		time:   timeParser,
+
+		buy:    buy,
+		sell:   sell,
+		volume: volume,
+		when:   when,
+	}
+
+	return newReader, nil
+}
+
+// Checksum returns the checksum of the decompressed content of the JSON Lines
+// file read so far.
+func (r *reader) Checksum() string {
+	return r.handle.Checksum()
+}
+
+// Close closes the underlying JSON Lines file.
+func (r *reader) Close() error {
+	err := r.handle.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+// Read parses the next non empty line of the JSON Lines file into a price
+// event. Read returns io.EOF in case there are no more lines to read. Lines
+// which cannot be parsed cause a malformedChartError naming the file and line
+// of the JSON document.
+func (r *reader) Read() (informer.Price, error) {
+	var line []byte
+
+	for {
+		r.line++
+
+		b, err := r.reader.ReadBytes('\n')
+		if err == io.EOF && len(bytes.TrimSpace(b)) == 0 {
+			return informer.Price{}, io.EOF
+		} else if err != nil && err != io.EOF {
+			return informer.Price{}, microerror.MaskAny(err)
+		}
+
+		line = bytes.TrimSpace(b)
+		if len(line) != 0 {
+			break
+		}
+	}
+
+	var v interface{}
+	{
+		d := json.NewDecoder(bytes.NewReader(line))
+		d.UseNumber()
+		err := d.Decode(&v)
+		if err != nil {
+			return informer.Price{}, r.malformed(err)
+		}
+	}
+
+	b, err := r.float(v, r.buy)
+	if err != nil {
+		return informer.Price{}, microerror.MaskAny(err)
+	}
+	s, err := r.float(v, r.sell)
+	if err != nil {
+		return informer.Price{}, microerror.MaskAny(err)
+	}
+	t, err := r.timestamp(v, r.when)
+	if err != nil {
+		return informer.Price{}, microerror.MaskAny(err)
+	}
+
+	var vol float64
+	if r.volume != nil {
+		vol, err = r.float(v, *r.volume)
+		if err != nil {
+			return informer.Price{}, microerror.MaskAny(err)
+		}
+	}
+
+	price := informer.Price{
+		Buy:    b,
+		Sell:   s,
+		Time:   t,
+		Volume: vol,
+	}
+
+	return price, nil
+}
+
+// float looks up the value of the given path and parses it as float64. Prices
+// are accepted as JSON numbers as well as JSON strings, because a lot of APIs
+// provide prices as strings to not lose precision.
+func (r *reader) float(v interface{}, p jsonpath.Path) (float64, error) {
+	s, err := r.scalar(v, p)
+	if err != nil {
+		return 0, microerror.MaskAny(err)
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, r.malformed(err)
+	}
+
+	return f, nil
+}
+
+// timestamp looks up the value of the given path and parses it according to
+// the configured time format.
+func (r *reader) timestamp(v interface{}, p jsonpath.Path) (time.Time, error) {
+	s, err := r.scalar(v, p)
+	if err != nil {
+		return time.Time{}, microerror.MaskAny(err)
+	}
+
+	t, err := r.time.Parse(s)
+	if err != nil {
+		return time.Time{}, r.malformed(err)
+	}
+
+	return t, nil
+}
+
+// scalar looks up the value of the given path and returns its string
+// representation. Only JSON numbers and JSON strings are accepted.
+func (r *reader) scalar(v interface{}, p jsonpath.Path) (string, error) {
+	s, err := p.Scalar(v)
+	if err != nil {
+		return "", r.malformedf("%s", err.Error())
+	}
+
+	return s, nil
+}
+
+func (r *reader) malformed(err error) error {
+	return r.malformedf("%s", errgo.Cause(err).Error())
+}
+
+func (r *reader) malformedf(f string, v ...interface{}) error {
+	return microerror.MaskAnyf(malformedChartError, "%s:%d: %s", r.chart.Path, r.line, fmt.Sprintf(f, v...))
+}
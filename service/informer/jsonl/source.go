@@ -0,0 +1,53 @@
+package jsonl
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer/cleaner"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/mapping"
+)
+
+// chart describes a single JSON Lines chart along with the mapping of its JSON
+// documents to price events.
+type chart struct {
+	Mapping mapping.Mapping
+	Path    string
+}
+
+// newSource creates the source of price events for the given chart. The
+// returned source applies the data quality policies configured in the mapping
+// of the given chart. Note that the returned source has to be closed by the
+// caller.
+func newSource(c chart) (*cleaner.Cleaner, error) {
+	r, err := newReader(c)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	s, err := newCleaner(c, r)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return s, nil
+}
+
+// newCleaner wraps the given reader to apply the data quality policies
+// configured in the mapping of the given chart. The reader is closed in case
+// the cleaner cannot be created.
+func newCleaner(c chart, r *reader) (*cleaner.Cleaner, error) {
+	cleanerConfig := cleaner.DefaultConfig()
+	cleanerConfig.Path = c.Path
+	cleanerConfig.Quality = c.Mapping.Quality
+	cleanerConfig.Source = r
+	s, err := cleaner.New(cleanerConfig)
+	if cleaner.IsInvalidConfig(err) {
+		r.Close()
+		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+	} else if err != nil {
+		r.Close()
+		return nil, microerror.MaskAny(err)
+	}
+
+	return s, nil
+}
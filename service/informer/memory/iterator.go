@@ -0,0 +1,67 @@
+package memory
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// NewIterator returns an informer.Iterator providing the given price events.
+// The iterator stops as soon as the given context is canceled.
+func NewIterator(ctx context.Context, prices []informer.Price) informer.Iterator {
+	return &iterator{
+		ctx:    ctx,
+		index:  -1,
+		prices: prices,
+	}
+}
+
+// iterator implements informer.Iterator.
+type iterator struct {
+	ctx    context.Context
+	done   bool
+	err    error
+	index  int
+	prices []informer.Price
+}
+
+func (i *iterator) Close() error {
+	i.done = true
+
+	return nil
+}
+
+func (i *iterator) Err() error {
+	return i.err
+}
+
+func (i *iterator) Next() bool {
+	if i.done {
+		return false
+	}
+
+	select {
+	case <-i.ctx.Done():
+		i.err = microerror.MaskAny(i.ctx.Err())
+		i.done = true
+		return false
+	default:
+	}
+
+	i.index++
+	if i.index >= len(i.prices) {
+		i.done = true
+		return false
+	}
+
+	return true
+}
+
+func (i *iterator) Price() informer.Price {
+	if i.index < 0 || i.index >= len(i.prices) {
+		return informer.Price{}
+	}
+
+	return i.prices[i.index]
+}
@@ -0,0 +1,81 @@
+// Package memory provides the implementation of an informer serving charts
+// held in memory. This is useful to feed other components with well known
+// price events, e.g. for testing purposes.
+package memory
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+)
+
+// Config is the configuration used to create a new informer.
+type Config struct {
+	// Settings.
+
+	// Charts is the list of charts the informer provides. Each chart is a list of
+	// price events ordered by time.
+	Charts [][]informer.Price
+}
+
+// DefaultConfig returns the default configuration used to create a new informer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Settings.
+		Charts: nil,
+	}
+}
+
+// New creates a new configured informer.
+func New(config Config) (informer.Informer, error) {
+	// Settings.
+	if len(config.Charts) == 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Charts must not be empty")
+	}
+
+	newInformer := &Informer{
+		// Internals.
+		charts:  config.Charts,
+		runtime: runtime.Runtime{},
+	}
+
+	for _, c := range config.Charts {
+		if len(c) == 0 {
+			return nil, microerror.MaskAnyf(invalidConfigError, "chart must not be empty")
+		}
+
+		price := stateprice.Price{
+			End:    c[len(c)-1].Time,
+			Events: len(c),
+			Start:  c[0].Time,
+		}
+		newInformer.runtime.State.Prices = append(newInformer.runtime.State.Prices, price)
+	}
+
+	return newInformer, nil
+}
+
+// Informer implements informer.Informer.
+type Informer struct {
+	// Internals.
+	charts  [][]informer.Price
+	runtime runtime.Runtime
+}
+
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	var iterators []informer.Iterator
+
+	for _, c := range i.charts {
+		iterators = append(iterators, NewIterator(ctx, c))
+	}
+
+	return iterators, nil
+}
+
+func (i *Informer) Runtime() runtime.Runtime {
+	return i.runtime
+}
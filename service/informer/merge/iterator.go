@@ -0,0 +1,170 @@
+package merge
+
+import (
+	"container/heap"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// iterator implements informer.Iterator to merge the price events of multiple
+// iterators. The next price event of each wrapped iterator is kept in a heap,
+// so the earliest of them can be handed out.
+type iterator struct {
+	done      bool
+	err       error
+	heads     heads
+	iterators []informer.Iterator
+	price     informer.Price
+	started   bool
+}
+
+func newIterator(ids []string, iterators []informer.Iterator) *iterator {
+	i := &iterator{
+		iterators: iterators,
+	}
+
+	for index, it := range iterators {
+		i.heads = append(i.heads, &head{id: ids[index], index: index, iterator: it})
+	}
+
+	return i
+}
+
+func (i *iterator) Close() error {
+	i.done = true
+
+	var err error
+	for _, it := range i.iterators {
+		if cerr := it.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+func (i *iterator) Err() error {
+	return i.err
+}
+
+func (i *iterator) Next() bool {
+	if i.done {
+		return false
+	}
+
+	// Initially each wrapped iterator is advanced to its first price event. Later
+	// on only the wrapped iterator which provided the recent price event has to
+	// be advanced.
+	if !i.started {
+		i.started = true
+
+		var active heads
+		for _, h := range i.heads {
+			ok, err := h.advance()
+			if err != nil {
+				i.fail(err)
+				return false
+			}
+			if ok {
+				active = append(active, h)
+			}
+		}
+		i.heads = active
+		heap.Init(&i.heads)
+	} else if len(i.heads) != 0 {
+		ok, err := i.heads[0].advance()
+		if err != nil {
+			i.fail(err)
+			return false
+		}
+		if ok {
+			heap.Fix(&i.heads, 0)
+		} else {
+			heap.Pop(&i.heads)
+		}
+	}
+
+	if len(i.heads) == 0 {
+		i.Close()
+		return false
+	}
+
+	i.price = i.heads[0].price
+	i.price.Chart = i.heads[0].id
+
+	return true
+}
+
+func (i *iterator) Price() informer.Price {
+	return i.price
+}
+
+// fail stops the iterator and remembers the given error to be returned by Err.
+func (i *iterator) fail(err error) {
+	i.err = microerror.MaskAny(err)
+	i.Close()
+}
+
+// head holds the next price event of a wrapped iterator.
+type head struct {
+	id       string
+	index    int
+	iterator informer.Iterator
+	price    informer.Price
+}
+
+// advance moves the wrapped iterator to its next price event. advance returns
+// false in case the wrapped iterator does not provide any more price events.
+func (h *head) advance() (bool, error) {
+	if !h.iterator.Next() {
+		err := h.iterator.Err()
+		if err != nil {
+			return false, microerror.MaskAny(err)
+		}
+
+		return false, nil
+	}
+
+	h.price = h.iterator.Price()
+
+	return true, nil
+}
+
+// heads implements heap.Interface. The head having the earliest price event is
+// at the top of the heap. Ties are broken by the index of the wrapped
+// iterator, which makes the merged order deterministic.
+type heads []*head
+
+func (h heads) Len() int {
+	return len(h)
+}
+
+func (h heads) Less(i, j int) bool {
+	if h[i].price.Time.Equal(h[j].price.Time) {
+		return h[i].index < h[j].index
+	}
+
+	return h[i].price.Time.Before(h[j].price.Time)
+}
+
+func (h heads) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *heads) Push(x interface{}) {
+	*h = append(*h, x.(*head))
+}
+
+func (h *heads) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+
+	return x
+}
@@ -0,0 +1,176 @@
+// Package merge provides the implementation of an informer which wraps another
+// informer to merge all of its charts into a single, chronologically ordered
+// stream of price events. Each price event is tagged with the ID of the chart
+// it originates from. That way a single consumer can react to several markets
+// at once, e.g. to share one budget across all of them.
+package merge
+
+import (
+	"reflect"
+	"strconv"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+)
+
+// Config is the configuration used to create a new informer.
+type Config struct {
+	// Dependencies.
+	Informer informer.Informer
+
+	// Settings.
+
+	// IDs is the list of chart IDs price events are tagged with. The ID at index
+	// i is used for the chart provided by the i-th iterator of the wrapped
+	// informer. In case IDs is empty, charts are identified by their index,
+	// starting at 0.
+	IDs []string
+}
+
+// DefaultConfig returns the default configuration used to create a new informer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Dependencies.
+		Informer: nil,
+
+		// Settings.
+		IDs: nil,
+	}
+}
+
+// New creates a new configured informer.
+func New(config Config) (informer.Informer, error) {
+	// Dependencies.
+	if config.Informer == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Informer must not be empty")
+	}
+
+	// Settings.
+	seen := map[string]bool{}
+	for _, id := range config.IDs {
+		if id == "" {
+			return nil, microerror.MaskAnyf(invalidConfigError, "config.IDs must not contain empty IDs")
+		}
+		if seen[id] {
+			return nil, microerror.MaskAnyf(invalidConfigError, "config.IDs must not contain duplicate ID '%s'", id)
+		}
+		seen[id] = true
+	}
+
+	newInformer := &Informer{
+		// Dependencies.
+		informer: config.Informer,
+
+		// Settings.
+		ids: config.IDs,
+	}
+
+	return newInformer, nil
+}
+
+// Informer implements informer.Informer.
+type Informer struct {
+	// Dependencies.
+	informer informer.Informer
+
+	// Settings.
+	ids []string
+}
+
+// Endless returns true in case the wrapped informer is endless.
+func (i *Informer) Endless() bool {
+	return informer.IsEndless(i.informer)
+}
+
+// Prices returns a single iterator providing the price events of all charts of
+// the wrapped informer ordered by time. Price events having the same time are
+// ordered by the index of their chart within the wrapped informer.
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	iterators, err := i.informer.Prices(ctx)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	ids := i.chartIDs(len(iterators))
+	if len(ids) != len(iterators) {
+		for _, it := range iterators {
+			it.Close()
+		}
+		return nil, microerror.MaskAnyf(invalidConfigError, "got %d chart IDs for %d charts", len(ids), len(iterators))
+	}
+
+	return []informer.Iterator{newIterator(ids, iterators)}, nil
+}
+
+// Runtime returns the runtime of the wrapped informer. The prices reported by
+// its state are merged the same way the charts are, so that there is one price
+// summary for the single merged chart. The merged chart only describes the
+// market of its charts in case all of them declare the same market. The market
+// of each chart is reported by the ID price events of the chart are tagged
+// with.
+func (i *Informer) Runtime() runtime.Runtime {
+	r := i.informer.Runtime()
+	if len(r.State.Prices) == 0 {
+		return r
+	}
+
+	ids := i.chartIDs(len(r.State.Prices))
+
+	merged := r.State.Prices[0]
+	merged.Charts = map[string]statemeta.Meta{}
+	for index, p := range r.State.Prices {
+		if index < len(ids) {
+			merged.Charts[ids[index]] = p.Meta
+		}
+		if index == 0 {
+			continue
+		}
+
+		if merged.Checksum != p.Checksum {
+			merged.Checksum = ""
+		}
+		if !reflect.DeepEqual(merged.Meta, p.Meta) {
+			merged.Meta = statemeta.Meta{}
+		}
+		if merged.Slice != p.Slice {
+			merged.Slice = ""
+		}
+		if p.Events != 0 && (merged.Events == 0 || p.Start.Before(merged.Start)) {
+			merged.Start = p.Start
+		}
+		if p.Events != 0 && (merged.Events == 0 || p.End.After(merged.End)) {
+			merged.End = p.End
+		}
+
+		merged.Events += p.Events
+		merged.Quality.Dropped += p.Quality.Dropped
+		merged.Quality.Duplicates += p.Quality.Duplicates
+		merged.Quality.Gaps += p.Quality.Gaps
+		merged.Quality.Invalid += p.Quality.Invalid
+		merged.Quality.Unordered += p.Quality.Unordered
+	}
+	r.State.Prices = []stateprice.Price{merged}
+
+	return r
+}
+
+// chartIDs returns the IDs of the given number of charts. In case no IDs are
+// configured, charts are identified by their index.
+func (i *Informer) chartIDs(n int) []string {
+	if len(i.ids) != 0 {
+		return i.ids
+	}
+
+	var ids []string
+	for index := 0; index < n; index++ {
+		ids = append(ids, strconv.Itoa(index))
+	}
+
+	return ids
+}
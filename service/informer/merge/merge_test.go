@@ -0,0 +1,231 @@
+package merge
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+	"github.com/xh3b4sd/wafer/service/informer/memory"
+)
+
+// testInformer returns an informer providing one chart per given list of
+// seconds. The buy price of each price event is its position within its chart.
+func testInformer(t *testing.T, charts ...[]int64) informer.Informer {
+	var list [][]informer.Price
+	for _, seconds := range charts {
+		var chart []informer.Price
+		for i, s := range seconds {
+			chart = append(chart, informer.Price{
+				Buy:  float64(i),
+				Sell: float64(i),
+				Time: time.Unix(s, 0),
+			})
+		}
+		list = append(list, chart)
+	}
+
+	config := memory.DefaultConfig()
+	config.Charts = list
+	newInformer, err := memory.New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	return newInformer
+}
+
+// event is the part of a price event relevant to verify the merged order.
+type event struct {
+	Chart string
+	Buy   float64
+	Time  int64
+}
+
+func Test_Informer_Prices(t *testing.T) {
+	testCases := []struct {
+		Charts   [][]int64
+		IDs      []string
+		Expected []event
+	}{
+		// Test case 1, interleaving charts are merged by time.
+		{
+			Charts: [][]int64{
+				{1, 3, 5},
+				{2, 4, 6},
+			},
+			IDs: nil,
+			Expected: []event{
+				{Chart: "0", Buy: 0, Time: 1},
+				{Chart: "1", Buy: 0, Time: 2},
+				{Chart: "0", Buy: 1, Time: 3},
+				{Chart: "1", Buy: 1, Time: 4},
+				{Chart: "0", Buy: 2, Time: 5},
+				{Chart: "1", Buy: 2, Time: 6},
+			},
+		},
+		// Test case 2, ties are broken by the order of the charts, regardless of
+		// their IDs.
+		{
+			Charts: [][]int64{
+				{2, 3},
+				{1, 2},
+				{2},
+			},
+			IDs: []string{"xbt", "eth", "ada"},
+			Expected: []event{
+				{Chart: "eth", Buy: 0, Time: 1},
+				{Chart: "xbt", Buy: 0, Time: 2},
+				{Chart: "eth", Buy: 1, Time: 2},
+				{Chart: "ada", Buy: 0, Time: 2},
+				{Chart: "xbt", Buy: 1, Time: 3},
+			},
+		},
+		// Test case 3, a chart ending early does not stop the other charts.
+		{
+			Charts: [][]int64{
+				{1},
+				{2, 3, 4},
+			},
+			IDs: []string{"a", "b"},
+			Expected: []event{
+				{Chart: "a", Buy: 0, Time: 1},
+				{Chart: "b", Buy: 0, Time: 2},
+				{Chart: "b", Buy: 1, Time: 3},
+				{Chart: "b", Buy: 2, Time: 4},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultConfig()
+		config.Informer = testInformer(t, testCase.Charts...)
+		config.IDs = testCase.IDs
+		newInformer, err := New(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		iterators, err := newInformer.Prices(context.Background())
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if len(iterators) != 1 {
+			t.Fatal("case", i+1, "expected", 1, "got", len(iterators))
+		}
+
+		var events []event
+		for iterators[0].Next() {
+			p := iterators[0].Price()
+			events = append(events, event{Chart: p.Chart, Buy: p.Buy, Time: p.Time.Unix()})
+		}
+		if iterators[0].Err() != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", iterators[0].Err())
+		}
+		if !reflect.DeepEqual(events, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", events)
+		}
+
+		prices := newInformer.Runtime().State.Prices
+		if len(prices) != 1 {
+			t.Fatal("case", i+1, "expected", 1, "got", len(prices))
+		}
+		if prices[0].Events != len(testCase.Expected) {
+			t.Fatal("case", i+1, "expected", len(testCase.Expected), "got", prices[0].Events)
+		}
+		if prices[0].Start.Unix() != testCase.Expected[0].Time {
+			t.Fatal("case", i+1, "expected", testCase.Expected[0].Time, "got", prices[0].Start.Unix())
+		}
+		if prices[0].End.Unix() != testCase.Expected[len(testCase.Expected)-1].Time {
+			t.Fatal("case", i+1, "expected", testCase.Expected[len(testCase.Expected)-1].Time, "got", prices[0].End.Unix())
+		}
+	}
+}
+
+func Test_Informer_IDs(t *testing.T) {
+	testCases := []struct {
+		IDs          []string
+		ErrorMatcher func(error) bool
+	}{
+		{
+			IDs:          []string{"a", ""},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			IDs:          []string{"a", "a"},
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultConfig()
+		config.Informer = testInformer(t, []int64{1}, []int64{2})
+		config.IDs = testCase.IDs
+		_, err := New(config)
+		if !testCase.ErrorMatcher(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+
+	// The number of IDs must match the number of charts.
+	config := DefaultConfig()
+	config.Informer = testInformer(t, []int64{1}, []int64{2})
+	config.IDs = []string{"a", "b", "c"}
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	_, err = newInformer.Prices(context.Background())
+	if !IsInvalidConfig(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+// metaInformer wraps an informer to report the given markets for its charts.
+type metaInformer struct {
+	informer.Informer
+	metas []statemeta.Meta
+}
+
+func (i *metaInformer) Runtime() runtime.Runtime {
+	r := i.Informer.Runtime()
+	for index := range r.State.Prices {
+		r.State.Prices[index].Meta = i.metas[index]
+	}
+
+	return r
+}
+
+func Test_Informer_Runtime_Charts(t *testing.T) {
+	btc := statemeta.Meta{Symbol: "BTC-EUR", LotSize: 0.001}
+	eth := statemeta.Meta{Symbol: "ETH-EUR", LotSize: 0.01}
+
+	config := DefaultConfig()
+	config.Informer = &metaInformer{
+		Informer: testInformer(t, []int64{1}, []int64{2}),
+		metas:    []statemeta.Meta{btc, eth},
+	}
+	config.IDs = []string{"btc", "eth"}
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	prices := newInformer.Runtime().State.Prices
+	if len(prices) != 1 {
+		t.Fatal("expected", 1, "got", len(prices))
+	}
+	// The charts declare different markets, so the merged chart does not
+	// describe a market, but each of its charts does.
+	if !reflect.DeepEqual(prices[0].Meta, statemeta.Meta{}) {
+		t.Fatal("expected", statemeta.Meta{}, "got", prices[0].Meta)
+	}
+	expected := map[string]statemeta.Meta{"btc": btc, "eth": eth}
+	if !reflect.DeepEqual(prices[0].Charts, expected) {
+		t.Fatal("expected", expected, "got", prices[0].Charts)
+	}
+}
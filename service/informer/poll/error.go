@@ -0,0 +1,33 @@
+package poll
+
+import (
+	"github.com/juju/errgo"
+)
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var malformedResponseError = errgo.New("malformed response")
+
+// IsMalformedResponse asserts malformedResponseError.
+func IsMalformedResponse(err error) bool {
+	return errgo.Cause(err) == malformedResponseError
+}
+
+var requestFailedError = errgo.New("request failed")
+
+// IsRequestFailed asserts requestFailedError.
+func IsRequestFailed(err error) bool {
+	return errgo.Cause(err) == requestFailedError
+}
+
+var unexpectedStatusError = errgo.New("unexpected status")
+
+// IsUnexpectedStatus asserts unexpectedStatusError.
+func IsUnexpectedStatus(err error) bool {
+	return errgo.Cause(err) == unexpectedStatusError
+}
@@ -0,0 +1,62 @@
+package poll
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/cleaner"
+)
+
+// iterator implements informer.Iterator to provide the ticks of the ticker
+// endpoint after applying the data quality policies of the mapping.
+type iterator struct {
+	cleaner  *cleaner.Cleaner
+	done     bool
+	err      error
+	informer *Informer
+	price    informer.Price
+}
+
+func newIterator(c *cleaner.Cleaner, i *Informer) *iterator {
+	return &iterator{
+		cleaner:  c,
+		informer: i,
+	}
+}
+
+func (i *iterator) Close() error {
+	i.done = true
+
+	err := i.cleaner.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+func (i *iterator) Err() error {
+	return i.err
+}
+
+func (i *iterator) Next() bool {
+	if i.done {
+		return false
+	}
+
+	p, err := i.cleaner.Read()
+	if err != nil {
+		i.err = microerror.MaskAny(err)
+		i.Close()
+		return false
+	}
+
+	i.price = p
+	i.informer.observe(p, i.cleaner.Summary())
+
+	return true
+}
+
+func (i *iterator) Price() informer.Price {
+	return i.price
+}
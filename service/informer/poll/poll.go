@@ -0,0 +1,260 @@
+// Package poll provides the implementation of an informer polling a REST ticker
+// endpoint of an exchange at a fixed interval. Each response is mapped to a
+// single price event using the same JSON path mapping as the JSON Lines
+// informer. The informer provides a single chart which never ends, until the
+// context given to Prices is canceled or the endpoint keeps failing.
+package poll
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/backoff"
+	"github.com/xh3b4sd/wafer/service/informer/cleaner"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	configquality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/quality"
+	runtimestate "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+	pricequality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price/quality"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/jsonpath"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/mapping"
+	"github.com/xh3b4sd/wafer/service/informer/timeformat"
+)
+
+// Config is the configuration used to create a new informer.
+type Config struct {
+	// Dependencies.
+	Client *http.Client
+
+	// Settings.
+
+	// Backoff describes how failed polls are retried.
+	Backoff backoff.Backoff
+	// Interval is the duration between two consecutive polls.
+	Interval time.Duration
+	// Mapping maps the JSON document of each response to a price event. The
+	// duplicate and order policies of Mapping.Quality default to drop, because
+	// endpoints usually respond with the same tick until a new trade happens.
+	// The dedupe-keep-last and sort policies are not supported, since they
+	// require to look ahead of the most recent tick.
+	Mapping mapping.Mapping
+	// MaxAge is the maximum age a tick may have at the time it is received.
+	// Older ticks are considered stale and dropped. Zero disables the detection
+	// of stale ticks.
+	MaxAge time.Duration
+	// URL is the absolute HTTP or HTTPS address of the ticker endpoint.
+	URL string
+}
+
+// DefaultConfig returns the default configuration used to create a new informer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Dependencies.
+		Client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+
+		// Settings.
+		Backoff: backoff.Backoff{
+			Initial: time.Second,
+			Max:     30 * time.Second,
+			Retries: 5,
+		},
+		Interval: 10 * time.Second,
+		Mapping:  mapping.Mapping{},
+		MaxAge:   0,
+		URL:      "",
+	}
+}
+
+// New creates a new configured informer.
+func New(config Config) (informer.Informer, error) {
+	// Dependencies.
+	if config.Client == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Client must not be empty")
+	}
+
+	// Settings.
+	err := config.Backoff.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Backoff: %s", err.Error())
+	}
+	if config.Interval <= 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Interval must be greater than 0")
+	}
+	if config.MaxAge < 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.MaxAge must not be negative")
+	}
+	{
+		u, err := url.Parse(config.URL)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, "config.URL: %s", err.Error())
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, microerror.MaskAnyf(invalidConfigError, "config.URL must be an absolute HTTP or HTTPS address")
+		}
+	}
+
+	m := config.Mapping
+	if m.Quality.Duplicate == "" {
+		m.Quality.Duplicate = configquality.PolicyDrop
+	}
+	if m.Quality.Order == "" {
+		m.Quality.Order = configquality.PolicyDrop
+	}
+	if m.Quality.Duplicate == configquality.PolicyDedupeKeepLast {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Mapping.Quality.Duplicate must not be '%s'", m.Quality.Duplicate)
+	}
+	if m.Quality.Order == configquality.PolicySort {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Mapping.Quality.Order must not be '%s'", m.Quality.Order)
+	}
+	err = m.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Mapping: %s", err.Error())
+	}
+
+	timeParser, err := timeformat.NewParser(m.TimeFormat, m.TimeZone)
+	if timeformat.IsInvalidConfig(err) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Mapping: %s", err.Error())
+	} else if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	// The paths have been validated above, so parsing them cannot fail anymore.
+	buy, _ := jsonpath.Parse(m.Buy)
+	sell, _ := jsonpath.Parse(m.Sell)
+	when, _ := jsonpath.Parse(m.Time)
+
+	var volume *jsonpath.Path
+	if m.Volume != "" {
+		p, _ := jsonpath.Parse(m.Volume)
+		volume = &p
+	}
+
+	newInformer := &Informer{
+		// Dependencies.
+		client: config.Client,
+
+		// Settings.
+		backoff:  config.Backoff,
+		interval: config.Interval,
+		maxAge:   config.MaxAge,
+		quality:  m.Quality,
+		url:      config.URL,
+
+		// Internals.
+		buy:    buy,
+		mutex:  sync.Mutex{},
+		price:  stateprice.Price{},
+		sell:   sell,
+		stale:  0,
+		time:   timeParser,
+		volume: volume,
+		when:   when,
+	}
+
+	return newInformer, nil
+}
+
+// Informer implements informer.Informer.
+type Informer struct {
+	// Dependencies.
+	client *http.Client
+
+	// Settings.
+	backoff  backoff.Backoff
+	interval time.Duration
+	maxAge   time.Duration
+	quality  configquality.Quality
+	url      string
+
+	// Internals.
+	buy    jsonpath.Path
+	mutex  sync.Mutex
+	price  stateprice.Price
+	sell   jsonpath.Path
+	stale  int
+	time   *timeformat.Parser
+	volume *jsonpath.Path
+	when   jsonpath.Path
+}
+
+// Endless returns true, because the informer keeps polling the configured
+// endpoint until the given context is canceled.
+func (i *Informer) Endless() bool {
+	return true
+}
+
+// Prices returns a single iterator providing the ticks of the configured
+// endpoint. The first poll happens right away. Next blocks until the next tick
+// was received. The runtime state of the informer describes the ticks of the
+// iterator created most recently.
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	var newCleaner *cleaner.Cleaner
+	{
+		c := cleaner.DefaultConfig()
+		c.Source = newSource(ctx, i)
+		c.Path = i.url
+		c.Quality = i.quality
+		var err error
+		newCleaner, err = cleaner.New(c)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	i.mutex.Lock()
+	i.price = stateprice.Price{}
+	i.stale = 0
+	i.mutex.Unlock()
+
+	return []informer.Iterator{newIterator(newCleaner, i)}, nil
+}
+
+func (i *Informer) Runtime() runtime.Runtime {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	r := runtime.Runtime{
+		State: runtimestate.State{
+			Prices: []stateprice.Price{i.price},
+		},
+	}
+
+	return r
+}
+
+// observe records the given tick and the data quality summary of the iterator
+// providing it in the runtime state of the informer.
+func (i *Informer) observe(p informer.Price, q pricequality.Quality) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.price.Events == 0 {
+		i.price.Start = p.Time
+	}
+	i.price.End = p.Time
+	i.price.Events++
+	i.price.Quality = q
+	i.price.Quality.Dropped += i.stale
+	i.price.Quality.Stale = i.stale
+}
+
+// observeStale records a stale tick in the runtime state of the informer.
+// Stale ticks are dropped before the cleaner sees them, so they are counted
+// separately.
+func (i *Informer) observeStale() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.stale++
+	i.price.Quality.Dropped++
+	i.price.Quality.Stale = i.stale
+}
@@ -0,0 +1,325 @@
+package poll
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/backoff"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/mapping"
+)
+
+// exchange is a stub of a ticker endpoint. It responds with the given list of
+// responses one after another and repeats the last response once all others
+// have been served.
+type exchange struct {
+	mutex     sync.Mutex
+	requests  int
+	responses []response
+}
+
+type response struct {
+	Body   string
+	Status int
+}
+
+func (e *exchange) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mutex.Lock()
+	res := e.responses[len(e.responses)-1]
+	if e.requests < len(e.responses) {
+		res = e.responses[e.requests]
+	}
+	e.requests++
+	e.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(res.Status)
+	fmt.Fprint(w, res.Body)
+}
+
+func (e *exchange) Requests() int {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.requests
+}
+
+func testTick(buy string, t int64) response {
+	return response{
+		Body:   fmt.Sprintf(`{"ticker":{"buy":"%s","sell":%s,"server_time":%d}}`, buy, buy, t),
+		Status: http.StatusOK,
+	}
+}
+
+func testConfig(url string) Config {
+	config := DefaultConfig()
+	config.Backoff = backoff.Backoff{
+		Initial: time.Millisecond,
+		Max:     4 * time.Millisecond,
+		Retries: 2,
+	}
+	config.Interval = time.Millisecond
+	config.Mapping = mapping.Mapping{
+		Buy:  "ticker.buy",
+		Sell: "ticker.sell",
+		Time: "ticker.server_time",
+	}
+	config.URL = url
+
+	return config
+}
+
+func testIterator(t *testing.T, config Config) (informer.Informer, informer.Iterator) {
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(iterators) != 1 {
+		t.Fatal("expected", 1, "got", len(iterators))
+	}
+
+	return newInformer, iterators[0]
+}
+
+// Test_Informer_Prices makes sure ticks are mapped to price events, while
+// duplicate and unordered ticks are dropped and counted.
+func Test_Informer_Prices(t *testing.T) {
+	e := &exchange{
+		responses: []response{
+			testTick("10", 1),
+			testTick("10", 1),
+			testTick("12", 2),
+			testTick("9", 1),
+			testTick("13", 3),
+		},
+	}
+	s := httptest.NewServer(e)
+	defer s.Close()
+
+	newInformer, it := testIterator(t, testConfig(s.URL))
+	defer it.Close()
+
+	var buys []float64
+	for len(buys) < 3 && it.Next() {
+		buys = append(buys, it.Price().Buy)
+	}
+	if it.Err() != nil {
+		t.Fatal("expected", nil, "got", it.Err())
+	}
+	if !reflect.DeepEqual(buys, []float64{10, 12, 13}) {
+		t.Fatal("expected", []float64{10, 12, 13}, "got", buys)
+	}
+	if !it.Price().Time.Equal(time.Unix(3, 0)) {
+		t.Fatal("expected", time.Unix(3, 0), "got", it.Price().Time)
+	}
+
+	p := newInformer.Runtime().State.Prices[0]
+	if p.Events != 3 {
+		t.Fatal("expected", 3, "got", p.Events)
+	}
+	if !p.Start.Equal(time.Unix(1, 0)) || !p.End.Equal(time.Unix(3, 0)) {
+		t.Fatal("expected", time.Unix(1, 0), time.Unix(3, 0), "got", p.Start, p.End)
+	}
+	if p.Quality.Duplicates != 1 || p.Quality.Unordered != 1 || p.Quality.Dropped != 2 {
+		t.Fatal("expected", "1 duplicate, 1 unordered and 2 dropped", "got", p.Quality)
+	}
+}
+
+// Test_Informer_Prices_Retry makes sure failing polls are retried with backoff
+// and the iterator stops once all retries failed.
+func Test_Informer_Prices_Retry(t *testing.T) {
+	testCases := []struct {
+		Responses        []response
+		ExpectedBuys     []float64
+		ExpectedRequests int
+		ErrorMatcher     func(error) bool
+	}{
+		// Test case 1, server errors are retried until the endpoint recovers.
+		{
+			Responses: []response{
+				{Body: "", Status: http.StatusInternalServerError},
+				{Body: "", Status: http.StatusTooManyRequests},
+				testTick("10", 1),
+			},
+			ExpectedBuys:     []float64{10},
+			ExpectedRequests: 3,
+			ErrorMatcher:     nil,
+		},
+		// Test case 2, the iterator stops after the configured number of retries.
+		{
+			Responses: []response{
+				{Body: "", Status: http.StatusServiceUnavailable},
+			},
+			ExpectedBuys:     nil,
+			ExpectedRequests: 3,
+			ErrorMatcher:     IsRequestFailed,
+		},
+		// Test case 3, client errors are not retried.
+		{
+			Responses: []response{
+				{Body: "", Status: http.StatusNotFound},
+			},
+			ExpectedBuys:     nil,
+			ExpectedRequests: 1,
+			ErrorMatcher:     IsUnexpectedStatus,
+		},
+		// Test case 4, malformed responses are retried like failed requests.
+		{
+			Responses: []response{
+				{Body: `{"ticker":{"buy":true}}`, Status: http.StatusOK},
+			},
+			ExpectedBuys:     nil,
+			ExpectedRequests: 3,
+			ErrorMatcher:     IsMalformedResponse,
+		},
+	}
+
+	for i, testCase := range testCases {
+		e := &exchange{responses: testCase.Responses}
+		s := httptest.NewServer(e)
+
+		_, it := testIterator(t, testConfig(s.URL))
+
+		var buys []float64
+		if it.Next() {
+			buys = append(buys, it.Price().Buy)
+		}
+		it.Close()
+		s.Close()
+
+		if testCase.ErrorMatcher == nil && it.Err() != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", it.Err())
+		}
+		if testCase.ErrorMatcher != nil && !testCase.ErrorMatcher(it.Err()) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+		if !reflect.DeepEqual(buys, testCase.ExpectedBuys) {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedBuys, "got", buys)
+		}
+		if e.Requests() != testCase.ExpectedRequests {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedRequests, "got", e.Requests())
+		}
+	}
+}
+
+// Test_Informer_Prices_Stale makes sure ticks older than the configured maximum
+// age are dropped and counted.
+func Test_Informer_Prices_Stale(t *testing.T) {
+	now := time.Now().Unix()
+	e := &exchange{
+		responses: []response{
+			testTick("10", now-7200),
+			testTick("11", now),
+		},
+	}
+	s := httptest.NewServer(e)
+	defer s.Close()
+
+	config := testConfig(s.URL)
+	config.MaxAge = time.Hour
+	newInformer, it := testIterator(t, config)
+	defer it.Close()
+
+	if !it.Next() {
+		t.Fatal("expected", true, "got", false)
+	}
+	if it.Price().Buy != 11 {
+		t.Fatal("expected", 11, "got", it.Price().Buy)
+	}
+
+	q := newInformer.Runtime().State.Prices[0].Quality
+	if q.Stale != 1 || q.Dropped != 1 {
+		t.Fatal("expected", "1 stale and 1 dropped", "got", q)
+	}
+}
+
+// Test_Informer_Prices_Cancel makes sure iterators waiting for the next poll
+// stop as soon as their context is canceled.
+func Test_Informer_Prices_Cancel(t *testing.T) {
+	e := &exchange{
+		responses: []response{
+			testTick("10", 1),
+		},
+	}
+	s := httptest.NewServer(e)
+	defer s.Close()
+
+	config := testConfig(s.URL)
+	config.Interval = time.Hour
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iterators, err := newInformer.Prices(ctx)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	it := iterators[0]
+	defer it.Close()
+
+	if !it.Next() {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	if it.Next() {
+		t.Fatal("expected", false, "got", true)
+	}
+	if it.Err() == nil {
+		t.Fatal("expected", "error", "got", nil)
+	}
+}
+
+func Test_Informer_New(t *testing.T) {
+	testCases := []struct {
+		Modify func(*Config)
+	}{
+		// Test case 1, the URL must be absolute.
+		{
+			Modify: func(c *Config) { c.URL = "/ticker" },
+		},
+		// Test case 2, the interval must be positive.
+		{
+			Modify: func(c *Config) { c.Interval = 0 },
+		},
+		// Test case 3, the mapping must be valid.
+		{
+			Modify: func(c *Config) { c.Mapping.Buy = "" },
+		},
+		// Test case 4, policies looking ahead are not supported.
+		{
+			Modify: func(c *Config) { c.Mapping.Quality.Order = "sort" },
+		},
+		// Test case 5, the backoff must be valid.
+		{
+			Modify: func(c *Config) { c.Backoff.Max = 0 },
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := testConfig("http://127.0.0.1/ticker")
+		testCase.Modify(&config)
+		_, err := New(config)
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+}
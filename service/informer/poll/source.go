@@ -0,0 +1,210 @@
+package poll
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/jsonpath"
+)
+
+// maxBodySize is the maximum number of bytes read from a single response. Ticker
+// endpoints respond with small JSON documents, so anything bigger is considered
+// malformed.
+const maxBodySize = 1 << 20
+
+// source implements cleaner.Source to provide the ticks of the ticker endpoint.
+// Read blocks until the next poll is due and the endpoint responded with a
+// tick which is not stale. Read never returns io.EOF, but the error of the
+// context once it is canceled.
+type source struct {
+	ctx      context.Context
+	informer *Informer
+	next     time.Time
+}
+
+func newSource(ctx context.Context, i *Informer) *source {
+	return &source{
+		ctx:      ctx,
+		informer: i,
+		next:     time.Time{},
+	}
+}
+
+func (s *source) Close() error {
+	return nil
+}
+
+func (s *source) Read() (informer.Price, error) {
+	for {
+		err := s.sleep(s.next.Sub(time.Now()))
+		if err != nil {
+			return informer.Price{}, microerror.MaskAny(err)
+		}
+		s.next = time.Now().Add(s.informer.interval)
+
+		p, err := s.poll()
+		if err != nil {
+			return informer.Price{}, microerror.MaskAny(err)
+		}
+
+		if s.informer.maxAge != 0 && time.Since(p.Time) > s.informer.maxAge {
+			s.informer.observeStale()
+			continue
+		}
+
+		return p, nil
+	}
+}
+
+// poll requests the ticker endpoint until it responds with a valid tick.
+// Failed requests are retried according to the configured backoff. Responses
+// with client error status codes are not retried, except for 429 Too Many
+// Requests, because they are not going to succeed by retrying.
+func (s *source) poll() (informer.Price, error) {
+	for attempt := 0; ; attempt++ {
+		p, err := s.fetch()
+		if err == nil {
+			return p, nil
+		}
+		if IsUnexpectedStatus(err) || attempt >= s.informer.backoff.Retries {
+			return informer.Price{}, microerror.MaskAny(err)
+		}
+
+		err = s.sleep(s.informer.backoff.Delay(attempt))
+		if err != nil {
+			return informer.Price{}, microerror.MaskAny(err)
+		}
+	}
+}
+
+// fetch requests the ticker endpoint once and maps its response to a price
+// event.
+func (s *source) fetch() (informer.Price, error) {
+	req, err := http.NewRequest("GET", s.informer.url, nil)
+	if err != nil {
+		return informer.Price{}, microerror.MaskAny(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req = req.WithContext(s.ctx)
+
+	res, err := s.informer.client.Do(req)
+	if err != nil {
+		return informer.Price{}, microerror.MaskAnyf(requestFailedError, "%s", err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+		return informer.Price{}, microerror.MaskAnyf(requestFailedError, "%s responded with status %d", s.informer.url, res.StatusCode)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return informer.Price{}, microerror.MaskAnyf(unexpectedStatusError, "%s responded with status %d", s.informer.url, res.StatusCode)
+	}
+
+	var v interface{}
+	{
+		d := json.NewDecoder(io.LimitReader(res.Body, maxBodySize))
+		d.UseNumber()
+		err := d.Decode(&v)
+		if err != nil {
+			return informer.Price{}, s.malformedf("%s", err.Error())
+		}
+		// Drain the rest of the body so the connection can be reused.
+		io.Copy(ioutil.Discard, io.LimitReader(res.Body, maxBodySize))
+	}
+
+	b, err := s.float(v, s.informer.buy)
+	if err != nil {
+		return informer.Price{}, microerror.MaskAny(err)
+	}
+	sell, err := s.float(v, s.informer.sell)
+	if err != nil {
+		return informer.Price{}, microerror.MaskAny(err)
+	}
+	t, err := s.timestamp(v, s.informer.when)
+	if err != nil {
+		return informer.Price{}, microerror.MaskAny(err)
+	}
+
+	var vol float64
+	if s.informer.volume != nil {
+		vol, err = s.float(v, *s.informer.volume)
+		if err != nil {
+			return informer.Price{}, microerror.MaskAny(err)
+		}
+	}
+
+	price := informer.Price{
+		Buy:    b,
+		Sell:   sell,
+		Time:   t,
+		Volume: vol,
+	}
+
+	return price, nil
+}
+
+// float looks up the value of the given path and parses it as float64.
+func (s *source) float(v interface{}, p jsonpath.Path) (float64, error) {
+	str, err := p.Scalar(v)
+	if err != nil {
+		return 0, s.malformedf("%s", err.Error())
+	}
+
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, s.malformedf("%s", err.Error())
+	}
+
+	return f, nil
+}
+
+// timestamp looks up the value of the given path and parses it according to
+// the configured time format.
+func (s *source) timestamp(v interface{}, p jsonpath.Path) (time.Time, error) {
+	str, err := p.Scalar(v)
+	if err != nil {
+		return time.Time{}, s.malformedf("%s", err.Error())
+	}
+
+	t, err := s.informer.time.Parse(str)
+	if err != nil {
+		return time.Time{}, s.malformedf("%s", err.Error())
+	}
+
+	return t, nil
+}
+
+func (s *source) malformedf(f string, v ...interface{}) error {
+	return microerror.MaskAnyf(malformedResponseError, "%s: "+f, append([]interface{}{s.informer.url}, v...)...)
+}
+
+// sleep blocks for the given duration or until the context is canceled.
+func (s *source) sleep(d time.Duration) error {
+	select {
+	case <-s.ctx.Done():
+		return microerror.MaskAny(s.ctx.Err())
+	default:
+	}
+
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-s.ctx.Done():
+		return microerror.MaskAny(s.ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"os"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/spf13/viper"
+
+	"github.com/xh3b4sd/wafer/flag"
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv"
+	"github.com/xh3b4sd/wafer/service/informer/csv/column"
+)
+
+// NewCSV implements Factory to create a CSV informer. Either
+// --service.informer.csv.dir or --service.informer.csv.file has to be given.
+// The header flags are only used together with --service.informer.csv.file,
+// because CSV dirs provide their own header.yaml files.
+func NewCSV(f *flag.Flag, v *viper.Viper) (informer.Informer, error) {
+	dir := v.GetString(f.Service.Informer.CSV.Dir)
+	file := v.GetString(f.Service.Informer.CSV.File)
+
+	if dir == "" && file == "" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "either --%s or --%s must be given", f.Service.Informer.CSV.Dir, f.Service.Informer.CSV.File)
+	}
+	if dir != "" && file != "" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "only one of --%s or --%s must be given", f.Service.Informer.CSV.Dir, f.Service.Informer.CSV.File)
+	}
+
+	config := csv.DefaultConfig()
+	config.Cache = v.GetBool(f.Service.Informer.CSV.Cache)
+
+	if dir != "" {
+		err := assertPath(f.Service.Informer.CSV.Dir, dir, true)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+
+		config.Dir.Path = dir
+		config.Watch = v.GetBool(f.Service.Informer.CSV.Watch)
+		if v.IsSet(f.Service.Informer.CSV.WatchDelay) {
+			config.WatchDelay = v.GetDuration(f.Service.Informer.CSV.WatchDelay)
+		}
+	}
+
+	if file != "" {
+		err := assertPath(f.Service.Informer.CSV.File, file, false)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+
+		config.File.Header.Buy = column.Parse(v.GetString(f.Service.Informer.CSV.Header.Buy))
+		config.File.Header.Ignore = v.GetBool(f.Service.Informer.CSV.Header.Ignore)
+		config.File.Header.Sell = column.Parse(v.GetString(f.Service.Informer.CSV.Header.Sell))
+		config.File.Header.Time = column.Parse(v.GetString(f.Service.Informer.CSV.Header.Time))
+		config.File.Header.TimeFormat = v.GetString(f.Service.Informer.CSV.Header.TimeFormat)
+		config.File.Header.TimeZone = v.GetString(f.Service.Informer.CSV.Header.TimeZone)
+		if v.GetString(f.Service.Informer.CSV.Header.Volume) != "" {
+			c := column.Parse(v.GetString(f.Service.Informer.CSV.Header.Volume))
+			config.File.Header.Volume = &c
+		}
+		config.File.Path = file
+	}
+
+	newInformer, err := csv.New(config)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return newInformer, nil
+}
+
+// assertPath makes sure the given path exists and is a dir or a file, as
+// expected. The given flag is used to point to the misconfigured flag.
+func assertPath(flag, path string, isDir bool) error {
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return microerror.MaskAnyf(invalidConfigError, "--%s points to '%s' which does not exist", flag, path)
+	} else if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	if isDir && !fi.IsDir() {
+		return microerror.MaskAnyf(invalidConfigError, "--%s points to '%s' which is not a dir", flag, path)
+	}
+	if !isDir && fi.IsDir() {
+		return microerror.MaskAnyf(invalidConfigError, "--%s points to '%s' which is not a file", flag, path)
+	}
+
+	return nil
+}
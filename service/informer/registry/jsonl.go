@@ -0,0 +1,35 @@
+package registry
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/spf13/viper"
+
+	"github.com/xh3b4sd/wafer/flag"
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl"
+)
+
+// NewJSONL implements Factory to create a JSON Lines informer.
+// --service.informer.jsonl.dir has to be given.
+func NewJSONL(f *flag.Flag, v *viper.Viper) (informer.Informer, error) {
+	dir := v.GetString(f.Service.Informer.JSONL.Dir)
+
+	if dir == "" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "--%s must be given", f.Service.Informer.JSONL.Dir)
+	}
+
+	err := assertPath(f.Service.Informer.JSONL.Dir, dir, true)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	config := jsonl.DefaultConfig()
+	config.Dir.Path = dir
+
+	newInformer, err := jsonl.New(config)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return newInformer, nil
+}
@@ -0,0 +1,49 @@
+package registry
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/spf13/viper"
+
+	"github.com/xh3b4sd/wafer/flag"
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/poll"
+)
+
+// NewPoll implements Factory to create an informer polling a ticker endpoint.
+// --service.informer.poll.url has to be given, along with the JSON paths of
+// buy prices, sell prices and price times. Settings not given by flags fall
+// back to the defaults of the poll informer.
+func NewPoll(f *flag.Flag, v *viper.Viper) (informer.Informer, error) {
+	config := poll.DefaultConfig()
+
+	config.URL = v.GetString(f.Service.Informer.Poll.URL)
+	if config.URL == "" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "--%s must be given", f.Service.Informer.Poll.URL)
+	}
+
+	config.Mapping.Buy = v.GetString(f.Service.Informer.Poll.Buy)
+	config.Mapping.Sell = v.GetString(f.Service.Informer.Poll.Sell)
+	config.Mapping.Time = v.GetString(f.Service.Informer.Poll.Time)
+	config.Mapping.TimeFormat = v.GetString(f.Service.Informer.Poll.TimeFormat)
+	config.Mapping.TimeZone = v.GetString(f.Service.Informer.Poll.TimeZone)
+	config.Mapping.Volume = v.GetString(f.Service.Informer.Poll.Volume)
+
+	if v.IsSet(f.Service.Informer.Poll.Interval) {
+		config.Interval = v.GetDuration(f.Service.Informer.Poll.Interval)
+	}
+	if v.IsSet(f.Service.Informer.Poll.MaxAge) {
+		config.MaxAge = v.GetDuration(f.Service.Informer.Poll.MaxAge)
+	}
+	if v.IsSet(f.Service.Informer.Poll.Retries) {
+		config.Backoff.Retries = v.GetInt(f.Service.Informer.Poll.Retries)
+	}
+
+	newInformer, err := poll.New(config)
+	if poll.IsInvalidConfig(err) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "poll informer: %s", err.Error())
+	} else if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return newInformer, nil
+}
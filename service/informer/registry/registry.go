@@ -0,0 +1,132 @@
+// Package registry provides a registry of informer kinds. Each informer kind is
+// created by its own factory, which is responsible for reading its settings
+// from the parsed command line flags and the viper configuration. New informer
+// kinds can be added by registering their factories in DefaultFactories.
+package registry
+
+import (
+	"sort"
+	"strings"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/spf13/viper"
+
+	"github.com/xh3b4sd/wafer/flag"
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+const (
+	// KindCSV is the informer kind reading CSV files.
+	KindCSV = "csv"
+	// KindJSONL is the informer kind reading JSON Lines files.
+	KindJSONL = "jsonl"
+	// KindPoll is the informer kind polling a ticker endpoint of an exchange.
+	KindPoll = "poll"
+	// KindStream is the informer kind subscribing to a WebSocket endpoint of an
+	// exchange.
+	KindStream = "stream"
+	// KindSynthetic is the informer kind generating seeded synthetic charts.
+	KindSynthetic = "synthetic"
+)
+
+// Factory creates a new informer based on the given flags and viper
+// configuration.
+type Factory func(f *flag.Flag, v *viper.Viper) (informer.Informer, error)
+
+// DefaultFactories returns the factories of all informer kinds known by
+// default.
+func DefaultFactories() map[string]Factory {
+	return map[string]Factory{
+		KindCSV:       NewCSV,
+		KindJSONL:     NewJSONL,
+		KindPoll:      NewPoll,
+		KindStream:    NewStream,
+		KindSynthetic: NewSynthetic,
+	}
+}
+
+// Config is the configuration used to create a new registry.
+type Config struct {
+	// Settings.
+	Factories map[string]Factory
+	Flag      *flag.Flag
+	Viper     *viper.Viper
+}
+
+// DefaultConfig returns the default configuration used to create a new
+// registry by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Settings.
+		Factories: DefaultFactories(),
+		Flag:      nil,
+		Viper:     nil,
+	}
+}
+
+// New creates a new configured registry.
+func New(config Config) (*Registry, error) {
+	// Settings.
+	if len(config.Factories) == 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Factories must not be empty")
+	}
+	if config.Flag == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Flag must not be empty")
+	}
+	if config.Viper == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Viper must not be empty")
+	}
+
+	newRegistry := &Registry{
+		// Settings.
+		factories: config.Factories,
+		flag:      config.Flag,
+		viper:     config.Viper,
+	}
+
+	return newRegistry, nil
+}
+
+// Registry creates informers by their kind.
+type Registry struct {
+	// Settings.
+	factories map[string]Factory
+	flag      *flag.Flag
+	viper     *viper.Viper
+}
+
+// Informer creates the informer of the kind configured by
+// --service.informer.kind.
+func (r *Registry) Informer() (informer.Informer, error) {
+	return r.New(r.viper.GetString(r.flag.Service.Informer.Kind))
+}
+
+// Kinds returns the sorted list of all registered informer kinds.
+func (r *Registry) Kinds() []string {
+	var kinds []string
+	for k := range r.factories {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+
+	return kinds
+}
+
+// New creates an informer of the given kind.
+func (r *Registry) New(kind string) (informer.Informer, error) {
+	if kind == "" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "--%s must not be empty, use one of: %s", r.flag.Service.Informer.Kind, strings.Join(r.Kinds(), ", "))
+	}
+
+	factory, ok := r.factories[kind]
+	if !ok {
+		return nil, microerror.MaskAnyf(invalidConfigError, "unknown informer kind '%s' for --%s, use one of: %s", kind, r.flag.Service.Informer.Kind, strings.Join(r.Kinds(), ", "))
+	}
+
+	newInformer, err := factory(r.flag, r.viper)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return newInformer, nil
+}
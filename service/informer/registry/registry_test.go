@@ -0,0 +1,185 @@
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/xh3b4sd/wafer/flag"
+)
+
+func Test_Registry_Informer(t *testing.T) {
+	dir, err := filepath.Abs("../csv/fixtures/dir")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	file, err := filepath.Abs("../csv/fixtures/file/001.csv")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	jsonlDir, err := filepath.Abs("../jsonl/fixtures/dir")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	f := flag.New()
+
+	testCases := []struct {
+		Values       map[string]interface{}
+		Charts       int
+		ErrorMatcher func(err error) bool
+	}{
+		// Test case 1 makes sure a CSV informer can be created from a CSV dir.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:    KindCSV,
+				f.Service.Informer.CSV.Dir: dir,
+			},
+			Charts:       2,
+			ErrorMatcher: nil,
+		},
+		// Test case 2 makes sure a CSV informer can be created from a CSV file
+		// using the header flags.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:              KindCSV,
+				f.Service.Informer.CSV.File:          file,
+				f.Service.Informer.CSV.Header.Buy:    "buy",
+				f.Service.Informer.CSV.Header.Sell:   "10",
+				f.Service.Informer.CSV.Header.Time:   "server_time",
+				f.Service.Informer.CSV.Header.Volume: "vol_cur",
+			},
+			Charts:       1,
+			ErrorMatcher: nil,
+		},
+		// Test case 3 makes sure a JSON Lines informer can be created from a JSON
+		// Lines dir.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:      KindJSONL,
+				f.Service.Informer.JSONL.Dir: jsonlDir,
+			},
+			Charts:       2,
+			ErrorMatcher: nil,
+		},
+		// Test case 4 makes sure a synthetic informer can be created using its
+		// defaults for settings not given.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:             KindSynthetic,
+				f.Service.Informer.Synthetic.Charts: 3,
+				f.Service.Informer.Synthetic.Model:  "regime-switching",
+			},
+			Charts:       3,
+			ErrorMatcher: nil,
+		},
+		// Test case 5 makes sure invalid synthetic settings cause an error.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:            KindSynthetic,
+				f.Service.Informer.Synthetic.Model: "random-walk",
+			},
+			Charts:       0,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 6 makes sure a poll informer can be created without polling
+		// its ticker endpoint right away.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:      KindPoll,
+				f.Service.Informer.Poll.Buy:  "ticker.buy",
+				f.Service.Informer.Poll.Sell: "ticker.sell",
+				f.Service.Informer.Poll.Time: "ticker.server_time",
+				f.Service.Informer.Poll.URL:  "http://127.0.0.1/ticker",
+			},
+			Charts:       1,
+			ErrorMatcher: nil,
+		},
+		// Test case 7 makes sure a stream informer can be created without
+		// connecting to its WebSocket endpoint right away.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:        KindStream,
+				f.Service.Informer.Stream.Buy:  "data.bid",
+				f.Service.Informer.Stream.Sell: "data.ask",
+				f.Service.Informer.Stream.Time: "data.ts",
+				f.Service.Informer.Stream.URL:  "ws://127.0.0.1/ticker",
+			},
+			Charts:       1,
+			ErrorMatcher: nil,
+		},
+		// Test case 8 makes sure unknown informer kinds cause an error.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind: "foo",
+			},
+			Charts:       0,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 9 makes sure missing paths cause an error.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind: KindCSV,
+			},
+			Charts:       0,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 10 makes sure missing JSON Lines dirs cause an error.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind: KindJSONL,
+			},
+			Charts:       0,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 11 makes sure missing ticker endpoints cause an error.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind: KindPoll,
+			},
+			Charts:       0,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 12 makes sure paths which do not exist cause an error.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:    KindCSV,
+				f.Service.Informer.CSV.Dir: filepath.Join(dir, "foo"),
+			},
+			Charts:       0,
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		v := viper.New()
+		for k, val := range testCase.Values {
+			v.Set(k, val)
+		}
+
+		config := DefaultConfig()
+		config.Flag = f
+		config.Viper = v
+		newRegistry, err := New(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		newInformer, err := newRegistry.Informer()
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		charts := len(newInformer.Runtime().State.Prices)
+		if charts != testCase.Charts {
+			t.Fatal("case", i+1, "expected", testCase.Charts, "got", charts)
+		}
+	}
+}
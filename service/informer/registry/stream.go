@@ -0,0 +1,62 @@
+package registry
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/spf13/viper"
+
+	"github.com/xh3b4sd/wafer/flag"
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/mapping"
+	"github.com/xh3b4sd/wafer/service/informer/stream"
+)
+
+// NewStream implements Factory to create an informer subscribing to a
+// WebSocket endpoint. --service.informer.stream.url has to be given, along
+// with the JSON paths of buy prices, sell prices and price times. Settings not
+// given by flags fall back to the defaults of the stream informer.
+func NewStream(f *flag.Flag, v *viper.Viper) (informer.Informer, error) {
+	config := stream.DefaultConfig()
+
+	config.URL = v.GetString(f.Service.Informer.Stream.URL)
+	if config.URL == "" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "--%s must be given", f.Service.Informer.Stream.URL)
+	}
+
+	m := mapping.Mapping{
+		Buy:        v.GetString(f.Service.Informer.Stream.Buy),
+		Sell:       v.GetString(f.Service.Informer.Stream.Sell),
+		Time:       v.GetString(f.Service.Informer.Stream.Time),
+		TimeFormat: v.GetString(f.Service.Informer.Stream.TimeFormat),
+		TimeZone:   v.GetString(f.Service.Informer.Stream.TimeZone),
+		Volume:     v.GetString(f.Service.Informer.Stream.Volume),
+	}
+	decoder, err := stream.NewMappingDecoder(m)
+	if stream.IsInvalidConfig(err) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "stream informer: %s", err.Error())
+	} else if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	config.Decoder = decoder
+
+	if v.IsSet(f.Service.Informer.Stream.Origin) {
+		config.Origin = v.GetString(f.Service.Informer.Stream.Origin)
+	}
+	if v.IsSet(f.Service.Informer.Stream.Retries) {
+		config.Backoff.Retries = v.GetInt(f.Service.Informer.Stream.Retries)
+	}
+	if v.IsSet(f.Service.Informer.Stream.Subscribe) {
+		config.Subscribe = v.GetString(f.Service.Informer.Stream.Subscribe)
+	}
+	if v.IsSet(f.Service.Informer.Stream.Timeout) {
+		config.Timeout = v.GetDuration(f.Service.Informer.Stream.Timeout)
+	}
+
+	newInformer, err := stream.New(config)
+	if stream.IsInvalidConfig(err) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "stream informer: %s", err.Error())
+	} else if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return newInformer, nil
+}
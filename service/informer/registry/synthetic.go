@@ -0,0 +1,53 @@
+package registry
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+	"github.com/spf13/viper"
+
+	"github.com/xh3b4sd/wafer/flag"
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/synthetic"
+)
+
+// NewSynthetic implements Factory to create a synthetic informer. Settings not
+// given by flags fall back to the defaults of the synthetic informer.
+func NewSynthetic(f *flag.Flag, v *viper.Viper) (informer.Informer, error) {
+	config := synthetic.DefaultConfig()
+
+	if v.IsSet(f.Service.Informer.Synthetic.Charts) {
+		config.Charts = v.GetInt(f.Service.Informer.Synthetic.Charts)
+	}
+	if v.IsSet(f.Service.Informer.Synthetic.Drift) {
+		config.Drift = v.GetFloat64(f.Service.Informer.Synthetic.Drift)
+	}
+	if v.IsSet(f.Service.Informer.Synthetic.Interval) {
+		config.Interval = v.GetDuration(f.Service.Informer.Synthetic.Interval)
+	}
+	if v.IsSet(f.Service.Informer.Synthetic.Length) {
+		config.Length = v.GetInt(f.Service.Informer.Synthetic.Length)
+	}
+	if v.IsSet(f.Service.Informer.Synthetic.Model) {
+		config.Model = v.GetString(f.Service.Informer.Synthetic.Model)
+	}
+	if v.IsSet(f.Service.Informer.Synthetic.Price) {
+		config.Price = v.GetFloat64(f.Service.Informer.Synthetic.Price)
+	}
+	if v.IsSet(f.Service.Informer.Synthetic.Seed) {
+		config.Seed = v.GetInt64(f.Service.Informer.Synthetic.Seed)
+	}
+	if v.IsSet(f.Service.Informer.Synthetic.Spread) {
+		config.Spread = v.GetFloat64(f.Service.Informer.Synthetic.Spread)
+	}
+	if v.IsSet(f.Service.Informer.Synthetic.Volatility) {
+		config.Volatility = v.GetFloat64(f.Service.Informer.Synthetic.Volatility)
+	}
+
+	newInformer, err := synthetic.New(config)
+	if synthetic.IsInvalidConfig(err) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "synthetic informer: %s", err.Error())
+	} else if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return newInformer, nil
+}
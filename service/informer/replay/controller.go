@@ -0,0 +1,121 @@
+package replay
+
+import (
+	"sync"
+	"time"
+)
+
+// State describes the current state of a replay.
+type State struct {
+	// Paused is true in case the replay is paused.
+	Paused bool `json:"paused"`
+	// Position is the time of the price event replayed most recently, or the
+	// time the replay was moved to most recently.
+	Position time.Time `json:"position"`
+	// Speed is the factor by which the replay is faster than the original
+	// timing of the price events. Zero means as fast as possible.
+	Speed float64 `json:"speed"`
+}
+
+// controller holds the replay state shared by all iterators of an informer.
+// Iterators waiting for the replay clock are woken up whenever the state
+// changes, by closing the changed channel.
+type controller struct {
+	mutex sync.Mutex
+
+	changed  chan struct{}
+	paused   bool
+	position time.Time
+	seek     time.Time
+	seekGen  int
+	speed    float64
+}
+
+func newController(speed float64, start time.Time) *controller {
+	return &controller{
+		changed:  make(chan struct{}),
+		paused:   false,
+		position: start,
+		seek:     start,
+		seekGen:  0,
+		speed:    speed,
+	}
+}
+
+func (c *controller) Pause() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.paused = true
+	c.notify()
+}
+
+func (c *controller) Resume() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.paused = false
+	c.notify()
+}
+
+func (c *controller) Seek(t time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.position = t
+	c.seek = t
+	c.seekGen++
+	c.notify()
+}
+
+func (c *controller) State() State {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := State{
+		Paused:   c.paused,
+		Position: c.position,
+		Speed:    c.speed,
+	}
+
+	return s
+}
+
+// observe records the time of a price event handed out by an iterator as the
+// current position of the replay.
+func (c *controller) observe(t time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.position = t
+}
+
+// snapshot returns the parts of the state iterators act upon, along with the
+// channel being closed on the next state change.
+func (c *controller) snapshot() (snapshot, <-chan struct{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s := snapshot{
+		paused:  c.paused,
+		seek:    c.seek,
+		seekGen: c.seekGen,
+		speed:   c.speed,
+	}
+
+	return s, c.changed
+}
+
+// notify wakes up all waiting iterators. It must be called while holding the
+// mutex.
+func (c *controller) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+type snapshot struct {
+	paused  bool
+	seek    time.Time
+	seekGen int
+	speed   float64
+}
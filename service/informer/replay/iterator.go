@@ -0,0 +1,231 @@
+package replay
+
+import (
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// iterator implements informer.Iterator to replay the price events of another
+// iterator at wall clock pace. Each iterator runs its own replay clock. The
+// clock is anchored at a point in chart time and a point in wall time. From
+// there on chart time advances Speed times faster than wall time.
+type iterator struct {
+	ctx        context.Context
+	controller *controller
+	done       bool
+	err        error
+	index      int
+	informer   informer.Informer
+	iterator   informer.Iterator
+	price      informer.Price
+
+	// anchored is true as soon as the replay clock has been anchored. base is
+	// the chart time and wall the wall time of the anchor.
+	anchored bool
+	base     time.Time
+	wall     time.Time
+
+	// pending is the price event read from the wrapped iterator but not yet
+	// handed out, because the replay clock did not reach it yet.
+	hasPending bool
+	pending    informer.Price
+
+	// emitted is true as soon as the first price event has been handed out.
+	emitted bool
+	seekGen int
+	skip    time.Time
+}
+
+func newIterator(ctx context.Context, c *controller, i informer.Informer, index int, it informer.Iterator) *iterator {
+	return &iterator{
+		ctx:        ctx,
+		controller: c,
+		index:      index,
+		informer:   i,
+		iterator:   it,
+		// The seek generation starts below the initial generation of the
+		// controller. That way the configured start time, or the time of the most
+		// recent seek, is applied with the first call to Next.
+		seekGen: -1,
+	}
+}
+
+func (i *iterator) Close() error {
+	i.done = true
+
+	err := i.iterator.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+func (i *iterator) Err() error {
+	return i.err
+}
+
+func (i *iterator) Next() bool {
+	for {
+		if i.done {
+			return false
+		}
+
+		select {
+		case <-i.ctx.Done():
+			i.fail(i.ctx.Err())
+			return false
+		default:
+		}
+
+		s, changed := i.controller.snapshot()
+
+		if s.seekGen != i.seekGen {
+			err := i.seek(s.seekGen, s.seek)
+			if err != nil {
+				i.fail(err)
+				return false
+			}
+			continue
+		}
+
+		if s.paused {
+			i.wait(s.speed, changed, nil)
+			continue
+		}
+
+		if !i.hasPending {
+			if !i.iterator.Next() {
+				i.err = i.iterator.Err()
+				i.Close()
+				return false
+			}
+
+			p := i.iterator.Price()
+			if !i.skip.IsZero() && p.Time.Before(i.skip) {
+				continue
+			}
+
+			i.hasPending = true
+			i.pending = p
+		}
+
+		if !i.anchored {
+			i.anchor(i.pending.Time)
+		}
+
+		if s.speed != 0 {
+			d := time.Duration(float64(i.pending.Time.Sub(i.clock(s.speed))) / s.speed)
+			if d > 0 {
+				timer := time.NewTimer(d)
+				i.wait(s.speed, changed, timer.C)
+				timer.Stop()
+				continue
+			}
+		}
+
+		i.emitted = true
+		i.hasPending = false
+		i.price = i.pending
+		i.controller.observe(i.price.Time)
+
+		return true
+	}
+}
+
+func (i *iterator) Price() informer.Price {
+	return i.price
+}
+
+// anchor anchors the replay clock at the given chart time and the current wall
+// time.
+func (i *iterator) anchor(t time.Time) {
+	i.anchored = true
+	i.base = t
+	i.wall = time.Now()
+}
+
+// clock returns the current chart time of the replay clock.
+func (i *iterator) clock(speed float64) time.Time {
+	return i.base.Add(time.Duration(float64(time.Since(i.wall)) * speed))
+}
+
+// fail stops the iterator and remembers the given error to be returned by Err.
+func (i *iterator) fail(err error) {
+	i.err = microerror.MaskAny(err)
+	i.Close()
+}
+
+// seek moves the iterator to the given time. In case the given time is before
+// the most recent price event read from the wrapped iterator, the chart is
+// replayed again by creating a new iterator of the wrapped informer. A pending
+// price event of the replaced iterator is dropped, so that it is not handed out
+// before the replayed chart.
+func (i *iterator) seek(gen int, t time.Time) error {
+	i.seekGen = gen
+	i.skip = t
+
+	if t.IsZero() {
+		return nil
+	}
+
+	// A pending price event directly follows the most recent price event handed
+	// out. Before anything was handed out, the pending price event may follow
+	// price events skipped by a former seek.
+	rewind := i.emitted && t.Before(i.price.Time)
+	if !i.emitted && i.hasPending && t.Before(i.pending.Time) {
+		rewind = true
+	}
+
+	if rewind {
+		iterators, err := i.informer.Prices(i.ctx)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		if i.index >= len(iterators) {
+			return microerror.MaskAnyf(invalidExecutionError, "chart %d vanished from wrapped informer", i.index)
+		}
+		for j, it := range iterators {
+			if j != i.index {
+				it.Close()
+			}
+		}
+
+		i.iterator.Close()
+		i.iterator = iterators[i.index]
+		i.emitted = false
+		i.hasPending = false
+	}
+
+	if i.hasPending && i.pending.Time.Before(t) {
+		i.hasPending = false
+	}
+
+	i.anchor(t)
+
+	return nil
+}
+
+// wait blocks until the replay state changes, the given timer fires or the
+// context of the iterator is canceled. The replay clock does not advance while
+// waiting for a paused replay to be resumed.
+func (i *iterator) wait(speed float64, changed <-chan struct{}, timer <-chan time.Time) {
+	paused := timer == nil
+	if paused && i.anchored {
+		i.base = i.clock(speed)
+	}
+
+	select {
+	case <-changed:
+	case <-timer:
+	case <-i.ctx.Done():
+	}
+
+	if paused && i.anchored {
+		i.wall = time.Now()
+	}
+}
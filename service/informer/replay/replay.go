@@ -0,0 +1,132 @@
+// Package replay provides the implementation of an informer which wraps
+// another informer to replay its price events at wall clock pace. That way a
+// live style pipeline can be exercised against historical charts, and
+// downstream consumers see realistic event timing. A running replay can be
+// paused, resumed and moved to another point in time.
+package replay
+
+import (
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+)
+
+// Config is the configuration used to create a new informer.
+type Config struct {
+	// Dependencies.
+	Informer informer.Informer
+
+	// Settings.
+
+	// Speed is the factor by which the replay is faster than the original
+	// timing of the price events. A speed of 1 replays price events in real
+	// time, a speed of 60 replays one hour of price events within one minute.
+	// A speed of 0 replays price events as fast as possible.
+	Speed float64
+	// Start is the time the replay starts at. Price events before Start are
+	// skipped. In case Start is empty, the replay starts at the first price
+	// event of each chart.
+	Start time.Time
+}
+
+// DefaultConfig returns the default configuration used to create a new informer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Dependencies.
+		Informer: nil,
+
+		// Settings.
+		Speed: 1,
+		Start: time.Time{},
+	}
+}
+
+// New creates a new configured informer.
+func New(config Config) (*Informer, error) {
+	// Dependencies.
+	if config.Informer == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Informer must not be empty")
+	}
+
+	// Settings.
+	if config.Speed < 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Speed must not be negative")
+	}
+
+	newInformer := &Informer{
+		// Dependencies.
+		informer: config.Informer,
+
+		// Internals.
+		controller: newController(config.Speed, config.Start),
+	}
+
+	return newInformer, nil
+}
+
+// Informer implements informer.Informer. Pause, Resume and Seek affect all
+// iterators created by the informer.
+type Informer struct {
+	// Dependencies.
+	informer informer.Informer
+
+	// Internals.
+	controller *controller
+}
+
+// Endless returns true in case the wrapped informer is endless.
+func (i *Informer) Endless() bool {
+	return informer.IsEndless(i.informer)
+}
+
+// Pause stops all iterators from providing further price events until Resume
+// is called. The replay clock does not advance while being paused.
+func (i *Informer) Pause() {
+	i.controller.Pause()
+}
+
+// Prices returns a list of iterators replaying the price events of the wrapped
+// informer. Each iterator replays its chart on its own, starting at the
+// configured start time or at the first price event of its chart. Next blocks
+// until the replay clock of the iterator reaches the time of the next price
+// event.
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	iterators, err := i.informer.Prices(ctx)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	var replays []informer.Iterator
+	for index, it := range iterators {
+		replays = append(replays, newIterator(ctx, i.controller, i.informer, index, it))
+	}
+
+	return replays, nil
+}
+
+// Resume continues a paused replay.
+func (i *Informer) Resume() {
+	i.controller.Resume()
+}
+
+// Runtime returns the runtime of the wrapped informer.
+func (i *Informer) Runtime() runtime.Runtime {
+	return i.informer.Runtime()
+}
+
+// Seek moves the replay of all iterators to the given time. Price events
+// before the given time are skipped. Seeking backwards makes the iterators
+// replay their charts again, starting at the given time.
+func (i *Informer) Seek(t time.Time) {
+	i.controller.Seek(t)
+}
+
+// State returns the current state of the replay.
+func (i *Informer) State() State {
+	return i.controller.State()
+}
@@ -0,0 +1,250 @@
+package replay
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/memory"
+)
+
+func testInformer(t *testing.T, events int, interval time.Duration) informer.Informer {
+	var chart []informer.Price
+	for i := 0; i < events; i++ {
+		chart = append(chart, informer.Price{
+			Buy:  float64(i + 1),
+			Sell: float64(i + 1),
+			Time: time.Unix(int64(i)*int64(interval/time.Second), 0),
+		})
+	}
+
+	config := memory.DefaultConfig()
+	config.Charts = [][]informer.Price{chart}
+	newInformer, err := memory.New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	return newInformer
+}
+
+func testReplay(t *testing.T, config Config) (*Informer, informer.Iterator) {
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	return newInformer, iterators[0]
+}
+
+func testBuys(it informer.Iterator, n int) []float64 {
+	var buys []float64
+	for len(buys) < n && it.Next() {
+		buys = append(buys, it.Price().Buy)
+	}
+
+	return buys
+}
+
+// Test_Informer_Prices_Fast makes sure price events are replayed unchanged and
+// without delay in case the speed is 0.
+func Test_Informer_Prices_Fast(t *testing.T) {
+	config := DefaultConfig()
+	config.Informer = testInformer(t, 10, time.Hour)
+	config.Speed = 0
+	_, it := testReplay(t, config)
+	defer it.Close()
+
+	start := time.Now()
+	buys := testBuys(it, 100)
+	if time.Since(start) > time.Second {
+		t.Fatal("expected", "no delay", "got", time.Since(start))
+	}
+	if !reflect.DeepEqual(buys, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Fatal("expected", "all buy prices", "got", buys)
+	}
+	if it.Err() != nil {
+		t.Fatal("expected", nil, "got", it.Err())
+	}
+}
+
+// Test_Informer_Prices_Pace makes sure price events are delayed according to
+// the configured speed. At a speed of 3600, one hour between two price events
+// takes one second.
+func Test_Informer_Prices_Pace(t *testing.T) {
+	config := DefaultConfig()
+	config.Informer = testInformer(t, 4, 3*time.Minute)
+	config.Speed = 3600
+	_, it := testReplay(t, config)
+	defer it.Close()
+
+	start := time.Now()
+	buys := testBuys(it, 4)
+	elapsed := time.Since(start)
+
+	if len(buys) != 4 {
+		t.Fatal("expected", 4, "got", len(buys))
+	}
+	// Three gaps of 3 minutes each take 3 * 50ms at the configured speed.
+	if elapsed < 150*time.Millisecond || elapsed > 2*time.Second {
+		t.Fatal("expected", 150*time.Millisecond, "got", elapsed)
+	}
+}
+
+// Test_Informer_Prices_Start makes sure price events before the configured
+// start time are skipped.
+func Test_Informer_Prices_Start(t *testing.T) {
+	config := DefaultConfig()
+	config.Informer = testInformer(t, 10, time.Hour)
+	config.Speed = 0
+	config.Start = time.Unix(7*3600, 0)
+	newInformer, it := testReplay(t, config)
+	defer it.Close()
+
+	buys := testBuys(it, 100)
+	if !reflect.DeepEqual(buys, []float64{8, 9, 10}) {
+		t.Fatal("expected", []float64{8, 9, 10}, "got", buys)
+	}
+	if !newInformer.State().Position.Equal(time.Unix(9*3600, 0)) {
+		t.Fatal("expected", time.Unix(9*3600, 0), "got", newInformer.State().Position)
+	}
+}
+
+// Test_Informer_Pause makes sure a paused replay does not provide price events
+// until it is resumed.
+func Test_Informer_Pause(t *testing.T) {
+	config := DefaultConfig()
+	config.Informer = testInformer(t, 10, time.Hour)
+	config.Speed = 0
+	newInformer, it := testReplay(t, config)
+	defer it.Close()
+
+	buys := testBuys(it, 2)
+	if !reflect.DeepEqual(buys, []float64{1, 2}) {
+		t.Fatal("expected", []float64{1, 2}, "got", buys)
+	}
+
+	newInformer.Pause()
+	if !newInformer.State().Paused {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	next := make(chan bool)
+	go func() {
+		next <- it.Next()
+	}()
+
+	select {
+	case <-next:
+		t.Fatal("expected", "blocking iterator", "got", "price event")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	newInformer.Resume()
+
+	select {
+	case ok := <-next:
+		if !ok || it.Price().Buy != 3 {
+			t.Fatal("expected", 3, "got", it.Price().Buy)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected", "price event", "got", "blocking iterator")
+	}
+}
+
+// Test_Informer_Seek makes sure replays can be moved forwards and backwards.
+func Test_Informer_Seek(t *testing.T) {
+	config := DefaultConfig()
+	config.Informer = testInformer(t, 10, time.Hour)
+	config.Speed = 0
+	newInformer, it := testReplay(t, config)
+	defer it.Close()
+
+	buys := testBuys(it, 2)
+	if !reflect.DeepEqual(buys, []float64{1, 2}) {
+		t.Fatal("expected", []float64{1, 2}, "got", buys)
+	}
+
+	newInformer.Seek(time.Unix(5*3600, 0))
+	buys = testBuys(it, 2)
+	if !reflect.DeepEqual(buys, []float64{6, 7}) {
+		t.Fatal("expected", []float64{6, 7}, "got", buys)
+	}
+
+	newInformer.Seek(time.Unix(1*3600, 0))
+	buys = testBuys(it, 2)
+	if !reflect.DeepEqual(buys, []float64{2, 3}) {
+		t.Fatal("expected", []float64{2, 3}, "got", buys)
+	}
+}
+
+// Test_Informer_Cancel makes sure iterators waiting for the replay clock stop
+// as soon as their context is canceled.
+func Test_Informer_Cancel(t *testing.T) {
+	config := DefaultConfig()
+	config.Informer = testInformer(t, 10, time.Hour)
+	config.Speed = 1
+
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iterators, err := newInformer.Prices(ctx)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	it := iterators[0]
+	defer it.Close()
+
+	if !it.Next() {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	if it.Next() {
+		t.Fatal("expected", false, "got", true)
+	}
+	if it.Err() == nil {
+		t.Fatal("expected", "error", "got", nil)
+	}
+}
+
+// Test_Informer_Seek_Pace makes sure a paced replay moved backwards while
+// waiting for the replay clock does not hand out the price event it was waiting
+// for before replaying its chart again.
+func Test_Informer_Seek_Pace(t *testing.T) {
+	config := DefaultConfig()
+	config.Informer = testInformer(t, 10, time.Second)
+	config.Speed = 10
+	newInformer, it := testReplay(t, config)
+	defer it.Close()
+
+	buys := testBuys(it, 4)
+	if !reflect.DeepEqual(buys, []float64{1, 2, 3, 4}) {
+		t.Fatal("expected", []float64{1, 2, 3, 4}, "got", buys)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		newInformer.Seek(time.Unix(1, 0))
+	}()
+
+	buys = testBuys(it, 2)
+	if !reflect.DeepEqual(buys, []float64{2, 3}) {
+		t.Fatal("expected", []float64{2, 3}, "got", buys)
+	}
+}
@@ -0,0 +1,36 @@
+package connection
+
+import (
+	"time"
+)
+
+const (
+	// StateConnecting means the informer is establishing a connection to its
+	// live source.
+	StateConnecting = "connecting"
+	// StateConnected means the informer is connected to its live source and
+	// receives price events.
+	StateConnected = "connected"
+	// StateDisconnected means the connection to the live source was lost and the
+	// informer waits to reconnect.
+	StateDisconnected = "disconnected"
+	// StateClosed means the informer stopped consuming its live source, either
+	// because it was closed or because reconnecting failed for good.
+	StateClosed = "closed"
+)
+
+// Connection describes the connection of an informer to a live source of price
+// events. Connection is empty for informers reading charts from files.
+type Connection struct {
+	// Connects is the number of connections established successfully.
+	Connects int `json:"connects"`
+	// Error is the error which caused the most recent disconnect, if any.
+	Error string `json:"error"`
+	// Malformed is the number of messages which could not be decoded.
+	Malformed int `json:"malformed"`
+	// Since is the time of the most recent change of State.
+	Since time.Time `json:"since"`
+	// State is the current state of the connection. One of connecting,
+	// connected, disconnected or closed.
+	State string `json:"state"`
+}
@@ -0,0 +1,90 @@
+package slice
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// iterator implements informer.Iterator to provide only the price events of
+// another iterator which are within a slice.
+type iterator struct {
+	bounds   bounds
+	done     bool
+	err      error
+	index    int
+	iterator informer.Iterator
+	price    informer.Price
+	slice    Slice
+}
+
+func newIterator(it informer.Iterator, s Slice, b bounds) *iterator {
+	return &iterator{
+		bounds:   b,
+		index:    -1,
+		iterator: it,
+		slice:    s,
+	}
+}
+
+func (i *iterator) Close() error {
+	i.done = true
+
+	err := i.iterator.Close()
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+func (i *iterator) Err() error {
+	return i.err
+}
+
+func (i *iterator) Next() bool {
+	for {
+		if i.done {
+			return false
+		}
+
+		// Price events of fractional splits are counted, so there is no need to
+		// read the wrapped iterator any further once the upper bound is reached.
+		if !i.slice.IsWindow() && i.index+1 >= i.bounds.hi {
+			i.Close()
+			return false
+		}
+
+		if !i.iterator.Next() {
+			i.err = i.iterator.Err()
+			i.Close()
+			return false
+		}
+		i.index++
+
+		p := i.iterator.Price()
+		if i.slice.IsWindow() {
+			// Charts are ordered by time, so there is no need to read the wrapped
+			// iterator any further once the end of the time window is reached.
+			if !i.slice.To.IsZero() && !p.Time.Before(i.slice.To) {
+				i.Close()
+				return false
+			}
+			if !contains(i.slice, p.Time) {
+				continue
+			}
+		} else {
+			if i.index < i.bounds.lo {
+				continue
+			}
+		}
+
+		i.price = p
+
+		return true
+	}
+}
+
+func (i *iterator) Price() informer.Price {
+	return i.price
+}
@@ -0,0 +1,341 @@
+// Package slice provides the implementation of an informer which wraps another
+// informer to restrict each of its charts to a time window or a fractional
+// split. That way the same charts can be used to analyze on one part of their
+// price events and to validate on another part, without cutting chart files by
+// hand.
+package slice
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+)
+
+// Config is the configuration used to create a new informer.
+type Config struct {
+	// Dependencies.
+	Informer informer.Informer
+
+	// Settings.
+
+	// Slice is the slice each chart of the wrapped informer is restricted to.
+	Slice Slice
+}
+
+// DefaultConfig returns the default configuration used to create a new informer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Dependencies.
+		Informer: nil,
+
+		// Settings.
+		Slice: Slice{},
+	}
+}
+
+// New creates a new configured informer. New reads all charts of the wrapped
+// informer once to compute the bounds of each chart within the configured
+// slice, so the wrapped informer must not be endless. The charts are read
+// again whenever the wrapped informer reports a new reload generation.
+func New(config Config) (informer.Informer, error) {
+	// Dependencies.
+	if config.Informer == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Informer must not be empty")
+	}
+
+	// Settings.
+	if informer.IsEndless(config.Informer) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Informer must not be endless")
+	}
+	err := config.Slice.Validate()
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	newInformer := &Informer{
+		// Dependencies.
+		informer: config.Informer,
+
+		// Internals.
+		failed:   0,
+		mutex:    sync.Mutex{},
+		scanned:  scan{},
+		scanning: false,
+		slice:    config.Slice,
+	}
+
+	newInformer.scanned, err = newInformer.scan()
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return newInformer, nil
+}
+
+// Informer implements informer.Informer.
+type Informer struct {
+	// Dependencies.
+	informer informer.Informer
+
+	// Internals.
+
+	// failed is the reload generation scanning failed for in the background,
+	// so that it is not scanned over and over again.
+	failed int
+	mutex  sync.Mutex
+	// scanned is the result of the latest scan of the charts of the wrapped
+	// informer.
+	scanned scan
+	// scanning is true while the charts of the wrapped informer are scanned in
+	// the background.
+	scanning bool
+	slice    Slice
+}
+
+// Prices returns a list of iterators providing only the price events of the
+// wrapped informer which are within the configured slice. In case the wrapped
+// informer reloaded its charts, they are scanned again before the iterators
+// are created.
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	for {
+		s, err := i.current()
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+
+		iterators, err := i.informer.Prices(ctx)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+
+		// The wrapped informer might reload its charts while its iterators are
+		// created. Then the bounds do not belong to the iterators and the charts
+		// are scanned again.
+		if i.informer.Runtime().State.Reload.Generation != s.generation {
+			closeIterators(iterators)
+			continue
+		}
+
+		if len(iterators) != len(s.bounds) {
+			closeIterators(iterators)
+			return nil, microerror.MaskAnyf(invalidExecutionError, "wrapped informer provides %d charts instead of %d", len(iterators), len(s.bounds))
+		}
+
+		var slices []informer.Iterator
+		for index, it := range iterators {
+			slices = append(slices, newIterator(it, i.slice, s.bounds[index]))
+		}
+
+		return slices, nil
+	}
+}
+
+// Runtime returns the runtime of the wrapped informer. The prices reported by
+// its state describe the price events within the configured slice, as of the
+// reload generation reported. In case the wrapped informer reports a new
+// reload generation, its charts are scanned in the background, and the prices
+// of the previous generation are reported until the scan finished. In case the
+// charts of a new reload generation cannot be read, the prices of the previous
+// generation are kept.
+func (i *Informer) Runtime() runtime.Runtime {
+	r := i.informer.Runtime()
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	generation := r.State.Reload.Generation
+	if generation != i.scanned.generation && generation != i.failed && !i.scanning {
+		i.scanning = true
+		go i.rescan(generation)
+	}
+
+	r.State.Prices = append([]stateprice.Price{}, i.scanned.prices...) // copy
+	r.State.Reload.Generation = i.scanned.generation
+
+	return r
+}
+
+// current returns the scan of the charts of the current reload generation of
+// the wrapped informer. In case the latest scan belongs to another generation,
+// the charts are scanned right away.
+func (i *Informer) current() (scan, error) {
+	generation := i.informer.Runtime().State.Reload.Generation
+
+	i.mutex.Lock()
+	s := i.scanned
+	i.mutex.Unlock()
+
+	if s.generation == generation {
+		return s, nil
+	}
+
+	s, err := i.scan()
+	if err != nil {
+		return scan{}, microerror.MaskAny(err)
+	}
+	i.store(s)
+
+	return s, nil
+}
+
+// rescan scans the charts of the given reload generation of the wrapped
+// informer in the background.
+func (i *Informer) rescan(generation int) {
+	s, err := i.scan()
+
+	i.mutex.Lock()
+	i.scanning = false
+	if err != nil {
+		i.failed = generation
+	}
+	i.mutex.Unlock()
+
+	if err == nil {
+		i.store(s)
+	}
+}
+
+// store replaces the latest scan by the given scan, unless the latest scan
+// belongs to a newer reload generation.
+func (i *Informer) store(s scan) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if s.generation >= i.scanned.generation {
+		i.scanned = s
+	}
+}
+
+// scan is the result of scanning the charts of a reload generation of the
+// wrapped informer.
+type scan struct {
+	// bounds are the index bounds of fractional splits, one per chart.
+	bounds []bounds
+	// generation is the reload generation the charts belong to.
+	generation int
+	// prices are the price summaries of the charts within the slice.
+	prices []stateprice.Price
+}
+
+// scan reads all charts of the wrapped informer to compute the index bounds of
+// fractional splits and the prices reported by the runtime. scan must not be
+// called while holding the mutex.
+func (i *Informer) scan() (scan, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := i.informer.Runtime()
+	generation := r.State.Reload.Generation
+	wrapped := r.State.Prices
+
+	var counts []int
+	if !i.slice.IsWindow() {
+		iterators, err := i.informer.Prices(ctx)
+		if err != nil {
+			return scan{}, microerror.MaskAny(err)
+		}
+
+		for _, it := range iterators {
+			var n int
+			for it.Next() {
+				n++
+			}
+			it.Close()
+			if it.Err() != nil {
+				return scan{}, microerror.MaskAny(it.Err())
+			}
+
+			counts = append(counts, n)
+		}
+	}
+
+	iterators, err := i.informer.Prices(ctx)
+	if err != nil {
+		return scan{}, microerror.MaskAny(err)
+	}
+
+	var chartBounds []bounds
+	var prices []stateprice.Price
+	if !i.slice.IsWindow() && len(iterators) != len(counts) {
+		closeIterators(iterators)
+		return scan{}, microerror.MaskAnyf(invalidExecutionError, "wrapped informer changed while being scanned")
+	}
+
+	for index, it := range iterators {
+		b := bounds{}
+		if !i.slice.IsWindow() {
+			b.lo = int(math.Floor(i.slice.Start * float64(counts[index])))
+			b.hi = int(math.Floor(i.slice.End * float64(counts[index])))
+		}
+
+		price := stateprice.Price{
+			Slice: i.slice.Name,
+		}
+		if index < len(wrapped) {
+			price.Charts = wrapped[index].Charts
+			price.Checksum = wrapped[index].Checksum
+			price.Meta = wrapped[index].Meta
+			price.Quality = wrapped[index].Quality
+		}
+
+		s := newIterator(it, i.slice, b)
+		for s.Next() {
+			t := s.Price().Time
+			if price.Events == 0 {
+				price.Start = t
+			}
+			price.End = t
+			price.Events++
+		}
+		s.Close()
+		if s.Err() != nil {
+			closeIterators(iterators[index+1:])
+			return scan{}, microerror.MaskAny(s.Err())
+		}
+
+		chartBounds = append(chartBounds, b)
+		prices = append(prices, price)
+	}
+
+	result := scan{
+		bounds:     chartBounds,
+		generation: generation,
+		prices:     prices,
+	}
+
+	return result, nil
+}
+
+// bounds is the range of price event indizes of a chart within a fractional
+// split. lo is inclusive and hi is exclusive.
+type bounds struct {
+	lo int
+	hi int
+}
+
+// contains returns true in case the given time is within the time window of the
+// given slice.
+func contains(s Slice, t time.Time) bool {
+	if !s.From.IsZero() && t.Before(s.From) {
+		return false
+	}
+	if !s.To.IsZero() && !t.Before(s.To) {
+		return false
+	}
+
+	return true
+}
+
+func closeIterators(iterators []informer.Iterator) {
+	for _, it := range iterators {
+		it.Close()
+	}
+}
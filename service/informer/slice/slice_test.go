@@ -0,0 +1,442 @@
+package slice
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+	"github.com/xh3b4sd/wafer/service/informer/memory"
+	"github.com/xh3b4sd/wafer/service/informer/merge"
+)
+
+// testInformer returns an informer providing two charts of 10 and 4 hourly
+// price events. Buy prices are the hour of each price event.
+func testInformer(t *testing.T) informer.Informer {
+	var charts [][]informer.Price
+	for _, events := range []int{10, 4} {
+		var chart []informer.Price
+		for i := 0; i < events; i++ {
+			chart = append(chart, informer.Price{
+				Buy:  float64(i),
+				Sell: float64(i),
+				Time: time.Unix(int64(i)*3600, 0),
+			})
+		}
+		charts = append(charts, chart)
+	}
+
+	config := memory.DefaultConfig()
+	config.Charts = charts
+	newInformer, err := memory.New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	return newInformer
+}
+
+func testBuys(t *testing.T, i informer.Informer) [][]float64 {
+	iterators, err := i.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	var charts [][]float64
+	for _, it := range iterators {
+		var buys []float64
+		for it.Next() {
+			buys = append(buys, it.Price().Buy)
+		}
+		if it.Err() != nil {
+			t.Fatal("expected", nil, "got", it.Err())
+		}
+		charts = append(charts, buys)
+	}
+
+	return charts
+}
+
+func Test_Informer_Prices(t *testing.T) {
+	testCases := []struct {
+		Slice          Slice
+		ExpectedBuys   [][]float64
+		ExpectedPrices []stateprice.Price
+	}{
+		// Test case 1, the first 70% of each chart.
+		{
+			Slice: Slice{Name: "train", Start: 0, End: 0.7},
+			ExpectedBuys: [][]float64{
+				{0, 1, 2, 3, 4, 5, 6},
+				{0, 1},
+			},
+			ExpectedPrices: []stateprice.Price{
+				{End: time.Unix(6*3600, 0), Events: 7, Slice: "train", Start: time.Unix(0, 0)},
+				{End: time.Unix(1*3600, 0), Events: 2, Slice: "train", Start: time.Unix(0, 0)},
+			},
+		},
+		// Test case 2, the last 30% of each chart.
+		{
+			Slice: Slice{Name: "test", Start: 0.7, End: 1},
+			ExpectedBuys: [][]float64{
+				{7, 8, 9},
+				{2, 3},
+			},
+			ExpectedPrices: []stateprice.Price{
+				{End: time.Unix(9*3600, 0), Events: 3, Slice: "test", Start: time.Unix(7*3600, 0)},
+				{End: time.Unix(3*3600, 0), Events: 2, Slice: "test", Start: time.Unix(2*3600, 0)},
+			},
+		},
+		// Test case 3, a time window including its lower bound and excluding its
+		// upper bound.
+		{
+			Slice: Slice{Name: "window", From: time.Unix(3*3600, 0), To: time.Unix(6*3600, 0)},
+			ExpectedBuys: [][]float64{
+				{3, 4, 5},
+				{3},
+			},
+			ExpectedPrices: []stateprice.Price{
+				{End: time.Unix(5*3600, 0), Events: 3, Slice: "window", Start: time.Unix(3*3600, 0)},
+				{End: time.Unix(3*3600, 0), Events: 1, Slice: "window", Start: time.Unix(3*3600, 0)},
+			},
+		},
+		// Test case 4, a time window without upper bound. The second chart does
+		// not have any price events within the window.
+		{
+			Slice: Slice{Name: "recent", From: time.Unix(8*3600, 0)},
+			ExpectedBuys: [][]float64{
+				{8, 9},
+				nil,
+			},
+			ExpectedPrices: []stateprice.Price{
+				{End: time.Unix(9*3600, 0), Events: 2, Slice: "recent", Start: time.Unix(8*3600, 0)},
+				{Slice: "recent"},
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultConfig()
+		config.Informer = testInformer(t)
+		config.Slice = testCase.Slice
+		newInformer, err := New(config)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		buys := testBuys(t, newInformer)
+		if !reflect.DeepEqual(buys, testCase.ExpectedBuys) {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedBuys, "got", buys)
+		}
+
+		prices := newInformer.Runtime().State.Prices
+		if !reflect.DeepEqual(prices, testCase.ExpectedPrices) {
+			t.Fatal("case", i+1, "expected", testCase.ExpectedPrices, "got", prices)
+		}
+	}
+}
+
+// reloadInformer wraps an informer whose charts can be swapped, simulating an
+// informer which reloaded its charts.
+type reloadInformer struct {
+	informer.Informer
+
+	generation int
+}
+
+func (i *reloadInformer) Runtime() runtime.Runtime {
+	r := i.Informer.Runtime()
+	r.State.Reload.Generation = i.generation
+
+	return r
+}
+
+// Test_Informer_Prices_Reload makes sure the bounds of fractional splits are
+// computed again once the wrapped informer reloaded its charts.
+func Test_Informer_Prices_Reload(t *testing.T) {
+	wrapped := &reloadInformer{Informer: testInformer(t)}
+
+	config := DefaultConfig()
+	config.Informer = wrapped
+	config.Slice = Slice{Name: "train", Start: 0, End: 0.5}
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	expected := [][]float64{{0, 1, 2, 3, 4}, {0, 1}}
+	if buys := testBuys(t, newInformer); !reflect.DeepEqual(buys, expected) {
+		t.Fatal("expected", expected, "got", buys)
+	}
+
+	// The reloaded charts only provide the second chart, which grew to 10
+	// price events.
+	{
+		var chart []informer.Price
+		for i := 0; i < 10; i++ {
+			chart = append(chart, informer.Price{Buy: float64(i), Sell: float64(i), Time: time.Unix(int64(i)*3600, 0)})
+		}
+		config := memory.DefaultConfig()
+		config.Charts = [][]informer.Price{chart}
+		wrapped.Informer, err = memory.New(config)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		wrapped.generation++
+	}
+
+	expected = [][]float64{{0, 1, 2, 3, 4}}
+	if buys := testBuys(t, newInformer); !reflect.DeepEqual(buys, expected) {
+		t.Fatal("expected", expected, "got", buys)
+	}
+
+	r := newInformer.Runtime()
+	if r.State.Reload.Generation != 1 || len(r.State.Prices) != 1 || r.State.Prices[0].Events != 5 {
+		t.Fatal("expected", "generation 1 with 1 chart of 5 events", "got", r.State)
+	}
+}
+
+// Test_Informer_Runtime_Reload makes sure the runtime does not wait for the
+// charts of a new reload generation to be scanned, but reports the prices of
+// the new generation once they were scanned in the background.
+func Test_Informer_Runtime_Reload(t *testing.T) {
+	wrapped := &reloadInformer{Informer: testInformer(t)}
+
+	config := DefaultConfig()
+	config.Informer = wrapped
+	config.Slice = Slice{Name: "train", Start: 0, End: 0.5}
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// The reloaded charts only provide the first chart.
+	{
+		config := memory.DefaultConfig()
+		config.Charts = [][]informer.Price{{{Buy: 1, Sell: 1, Time: time.Unix(0, 0)}, {Buy: 2, Sell: 2, Time: time.Unix(3600, 0)}}}
+		wrapped.Informer, err = memory.New(config)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+		wrapped.generation++
+	}
+
+	r := newInformer.Runtime()
+	if r.State.Reload.Generation == 0 && len(r.State.Prices) != 2 {
+		t.Fatal("expected", 2, "got", len(r.State.Prices))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for r.State.Reload.Generation != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected", 1, "got", r.State.Reload.Generation)
+		}
+		time.Sleep(time.Millisecond)
+		r = newInformer.Runtime()
+	}
+	if len(r.State.Prices) != 1 || r.State.Prices[0].Events != 1 {
+		t.Fatal("expected", "generation 1 with 1 chart of 1 event", "got", r.State)
+	}
+}
+
+// endlessInformer wraps an informer to report it as endless.
+type endlessInformer struct {
+	informer.Informer
+}
+
+func (i *endlessInformer) Endless() bool {
+	return true
+}
+
+// Test_New_Endless makes sure endless informers are rejected, even when they
+// are wrapped by other informers, because their charts cannot be scanned.
+func Test_New_Endless(t *testing.T) {
+	mergeConfig := merge.DefaultConfig()
+	mergeConfig.Informer = &endlessInformer{Informer: testInformer(t)}
+	merged, err := merge.New(mergeConfig)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []informer.Informer{
+		&endlessInformer{Informer: testInformer(t)},
+		merged,
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultConfig()
+		config.Informer = testCase
+		config.Slice = Slice{Name: "train", Start: 0, End: 0.5}
+		_, err := New(config)
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
+
+// countInformer wraps an informer to count the price events read from its
+// iterators.
+type countInformer struct {
+	informer.Informer
+
+	reads int
+}
+
+func (i *countInformer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	iterators, err := i.Informer.Prices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var counted []informer.Iterator
+	for _, it := range iterators {
+		counted = append(counted, &countIterator{Iterator: it, informer: i})
+	}
+
+	return counted, nil
+}
+
+type countIterator struct {
+	informer.Iterator
+
+	informer *countInformer
+}
+
+func (i *countIterator) Next() bool {
+	ok := i.Iterator.Next()
+	if ok {
+		i.informer.reads++
+	}
+
+	return ok
+}
+
+// Test_Informer_Prices_Window makes sure the price events of a chart are not
+// read any further once the end of a time window is reached.
+func Test_Informer_Prices_Window(t *testing.T) {
+	wrapped := &countInformer{Informer: testInformer(t)}
+
+	config := DefaultConfig()
+	config.Informer = wrapped
+	config.Slice = Slice{Name: "window", From: time.Unix(1*3600, 0), To: time.Unix(3*3600, 0)}
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	wrapped.reads = 0
+	buys := testBuys(t, newInformer)
+	if !reflect.DeepEqual(buys, [][]float64{{1, 2}, {1, 2}}) {
+		t.Fatal("expected", [][]float64{{1, 2}, {1, 2}}, "got", buys)
+	}
+	// Each chart is read up to the first price event at the end of the window,
+	// instead of all 10 and 4 price events.
+	if wrapped.reads != 8 {
+		t.Fatal("expected", 8, "got", wrapped.reads)
+	}
+}
+
+func Test_Parse(t *testing.T) {
+	testCases := []struct {
+		Input        string
+		Expected     Slice
+		ErrorMatcher func(error) bool
+	}{
+		{
+			Input:        "train=0..0.7",
+			Expected:     Slice{Name: "train", Start: 0, End: 0.7},
+			ErrorMatcher: nil,
+		},
+		{
+			Input:        "test=0.7..",
+			Expected:     Slice{Name: "test", Start: 0.7, End: 1},
+			ErrorMatcher: nil,
+		},
+		{
+			Input:        "2016=2016-01-01T00:00:00Z..2017-01-01T00:00:00Z",
+			Expected:     Slice{Name: "2016", From: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)},
+			ErrorMatcher: nil,
+		},
+		{
+			Input:        "recent=2017-01-01T00:00:00Z..",
+			Expected:     Slice{Name: "recent", From: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)},
+			ErrorMatcher: nil,
+		},
+		{
+			Input:        "0..0.7",
+			Expected:     Slice{},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Input:        "train=0.7",
+			Expected:     Slice{},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Input:        "train=0.7..0.3",
+			Expected:     Slice{},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Input:        "train=0..1.5",
+			Expected:     Slice{},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Input:        "2016=2017-01-01T00:00:00Z..2016-01-01T00:00:00Z",
+			Expected:     Slice{},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		{
+			Input:        "2016=2016-01-01..2017-01-01",
+			Expected:     Slice{},
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		s, err := Parse(testCase.Input)
+		if testCase.ErrorMatcher == nil && err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if testCase.ErrorMatcher != nil && !testCase.ErrorMatcher(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+		if !reflect.DeepEqual(s, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", s)
+		}
+		if err == nil {
+			r, err := Parse(s.String())
+			if err != nil {
+				t.Fatal("case", i+1, "expected", nil, "got", err)
+			}
+			if !reflect.DeepEqual(r, s) {
+				t.Fatal("case", i+1, "expected", s, "got", r)
+			}
+		}
+	}
+}
+
+func Test_Find(t *testing.T) {
+	slices := []Slice{
+		{Name: "train", Start: 0, End: 0.7},
+		{Name: "test", Start: 0.7, End: 1},
+	}
+
+	s, err := Find(slices, "test")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if s.Name != "test" {
+		t.Fatal("expected", "test", "got", s.Name)
+	}
+
+	_, err = Find(slices, "validate")
+	if !IsNotFound(err) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
@@ -0,0 +1,185 @@
+package slice
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Slice describes the part of a chart price events are restricted to. A slice
+// is either a time window or a fractional split. Consider the following slices.
+//
+//     Slice{Name: "2016", From: 2016-01-01, To: 2017-01-01}
+//     Slice{Name: "train", Start: 0, End: 0.7}
+//     Slice{Name: "test", Start: 0.7, End: 1}
+//
+// The first slice restricts each chart to the price events of 2016. The other
+// slices split each chart into its first 70% and its last 30% of price events.
+type Slice struct {
+	// Name identifies the slice, e.g. to be referenced by the analyzer.
+	Name string `json:"name"`
+
+	// From is the inclusive lower bound of the time window. From can be empty
+	// in case the time window has no lower bound.
+	From time.Time `json:"from"`
+	// To is the exclusive upper bound of the time window. To can be empty in
+	// case the time window has no upper bound.
+	To time.Time `json:"to"`
+
+	// Start is the inclusive lower bound of the fractional split, as fraction
+	// of the number of price events of a chart, between 0 and 1.
+	Start float64 `json:"start"`
+	// End is the exclusive upper bound of the fractional split, as fraction of
+	// the number of price events of a chart, between 0 and 1.
+	End float64 `json:"end"`
+}
+
+// IsWindow returns true in case the slice describes a time window.
+func (s Slice) IsWindow() bool {
+	return !s.From.IsZero() || !s.To.IsZero()
+}
+
+// IsZero returns true in case the slice does not restrict charts at all.
+func (s Slice) IsZero() bool {
+	return !s.IsWindow() && s.Start == 0 && s.End == 0
+}
+
+// String renders the slice in the format understood by Parse.
+func (s Slice) String() string {
+	var from, to string
+
+	if s.IsWindow() {
+		if !s.From.IsZero() {
+			from = s.From.Format(time.RFC3339)
+		}
+		if !s.To.IsZero() {
+			to = s.To.Format(time.RFC3339)
+		}
+	} else {
+		from = strconv.FormatFloat(s.Start, 'f', -1, 64)
+		to = strconv.FormatFloat(s.End, 'f', -1, 64)
+	}
+
+	return s.Name + "=" + from + ".." + to
+}
+
+// Validate returns an error in case the slice is neither a valid time window nor
+// a valid fractional split.
+func (s Slice) Validate() error {
+	if s.IsZero() {
+		return microerror.MaskAnyf(invalidConfigError, "slice '%s' must either define a time window or a fractional split", s.Name)
+	}
+
+	if s.IsWindow() {
+		if s.Start != 0 || s.End != 0 {
+			return microerror.MaskAnyf(invalidConfigError, "slice '%s' must not define a time window and a fractional split", s.Name)
+		}
+		if !s.From.IsZero() && !s.To.IsZero() && !s.From.Before(s.To) {
+			return microerror.MaskAnyf(invalidConfigError, "slice '%s' must define From before To", s.Name)
+		}
+
+		return nil
+	}
+
+	if s.Start < 0 || s.End > 1 || s.Start >= s.End {
+		return microerror.MaskAnyf(invalidConfigError, "slice '%s' must define 0 <= Start < End <= 1", s.Name)
+	}
+
+	return nil
+}
+
+// Parse parses a named slice from the given string. The string consists of the
+// name of the slice, followed by its bounds separated by two dots. Bounds are
+// either fractions or RFC3339 times. Omitted time bounds are unbounded, while
+// omitted fractional bounds default to 0 and 1 respectively.
+//
+//     2016=2016-01-01T00:00:00Z..2017-01-01T00:00:00Z
+//     recent=2017-01-01T00:00:00Z..
+//     train=..0.7
+//     test=0.7..1
+//
+func Parse(s string) (Slice, error) {
+	i := strings.Index(s, "=")
+	if i < 1 {
+		return Slice{}, microerror.MaskAnyf(invalidConfigError, "slice '%s' must be formatted like name=from..to", s)
+	}
+	name, bounds := s[:i], s[i+1:]
+
+	j := strings.Index(bounds, "..")
+	if j < 0 {
+		return Slice{}, microerror.MaskAnyf(invalidConfigError, "slice '%s' must be formatted like name=from..to", s)
+	}
+	lower, upper := bounds[:j], bounds[j+2:]
+
+	newSlice := Slice{
+		Name: name,
+	}
+
+	if isFraction(lower) && isFraction(upper) {
+		newSlice.Start = 0
+		newSlice.End = 1
+
+		if lower != "" {
+			f, err := strconv.ParseFloat(lower, 64)
+			if err != nil {
+				return Slice{}, microerror.MaskAnyf(invalidConfigError, "slice '%s': %s", s, err.Error())
+			}
+			newSlice.Start = f
+		}
+		if upper != "" {
+			f, err := strconv.ParseFloat(upper, 64)
+			if err != nil {
+				return Slice{}, microerror.MaskAnyf(invalidConfigError, "slice '%s': %s", s, err.Error())
+			}
+			newSlice.End = f
+		}
+	} else {
+		if lower != "" {
+			t, err := time.Parse(time.RFC3339, lower)
+			if err != nil {
+				return Slice{}, microerror.MaskAnyf(invalidConfigError, "slice '%s': %s", s, err.Error())
+			}
+			newSlice.From = t
+		}
+		if upper != "" {
+			t, err := time.Parse(time.RFC3339, upper)
+			if err != nil {
+				return Slice{}, microerror.MaskAnyf(invalidConfigError, "slice '%s': %s", s, err.Error())
+			}
+			newSlice.To = t
+		}
+	}
+
+	err := newSlice.Validate()
+	if err != nil {
+		return Slice{}, microerror.MaskAny(err)
+	}
+
+	return newSlice, nil
+}
+
+// Find returns the slice having the given name.
+func Find(slices []Slice, name string) (Slice, error) {
+	var names []string
+
+	for _, s := range slices {
+		if s.Name == name {
+			return s, nil
+		}
+		names = append(names, s.Name)
+	}
+
+	return Slice{}, microerror.MaskAnyf(notFoundError, "slice '%s' not found, use one of: %s", name, strings.Join(names, ", "))
+}
+
+// isFraction returns true in case the given bound is empty or a number.
+func isFraction(bound string) bool {
+	if bound == "" {
+		return true
+	}
+
+	_, err := strconv.ParseFloat(bound, 64)
+	return err == nil
+}
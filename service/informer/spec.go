@@ -6,6 +6,8 @@ package informer
 import (
 	"time"
 
+	"golang.org/x/net/context"
+
 	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
 )
 
@@ -13,23 +15,104 @@ import (
 type Price struct {
 	// Buy is the buy price at a certain time.
 	Buy float64
+	// Candle holds the aggregated buy prices of the interval the price event
+	// represents. Candle is only set by informers resampling price events into
+	// candles. Its zero value indicates a raw price event.
+	Candle Candle
+	// Chart identifies the chart the price event originates from. Chart is only
+	// set by informers merging multiple charts into a single stream of price
+	// events. Its zero value indicates that all price events provided by an
+	// iterator originate from the same chart.
+	Chart string
 	// Sell is the sell price at a certain time.
 	Sell float64
 	// Time is the time at which a certain buy and sell price occured.
 	Time time.Time
+	// Volume is the traded volume at a certain time. Volume is optional. Its zero
+	// value indicates that the informer does not know about traded volumes.
+	Volume float64
+}
+
+// Spread returns the difference between the buy and the sell price.
+func (p Price) Spread() float64 {
+	return p.Buy - p.Sell
+}
+
+// Candle holds the aggregated buy prices of all price events which occured
+// within a certain interval.
+type Candle struct {
+	// Close is the buy price of the last price event within the interval.
+	Close float64
+	// High is the highest buy price within the interval.
+	High float64
+	// Low is the lowest buy price within the interval.
+	Low float64
+	// Open is the buy price of the first price event within the interval.
+	Open float64
+	// Ticks is the number of raw price events aggregated within the interval.
+	Ticks int
 }
 
 // Informer provides market prices.
 type Informer interface {
-	// Prices returns a list of channels which can be used to watch market prices.
-	// Each channel of the returned list represents price events from its
-	// logically own chart. E.g. the CSV informer implements consuming multiple
-	// CSV files. Each CSV file contains price events of potentially different
-	// stock markets. Also note that the returned list of channels must be
-	// consumed beginning with the first channel of the list. Consuming the last
-	// channel of the returned list at first would cause a dead lock.
-	Prices() []chan Price
+	// Prices returns a list of iterators which can be used to consume market
+	// prices. Each iterator of the returned list represents price events from
+	// its logically own chart. E.g. the CSV informer implements consuming
+	// multiple CSV files. Each CSV file contains price events of potentially
+	// different stock markets. The returned iterators are independent of each
+	// other. They can be consumed in any order or concurrently. Canceling the
+	// given context stops all of the returned iterators.
+	Prices(ctx context.Context) ([]Iterator, error)
 	// Runtime returns a copy of information about the current runtime of the
 	// informer.
 	Runtime() runtime.Runtime
 }
+
+// Endless is implemented by informers whose iterators do not end on their own,
+// e.g. because they keep receiving realtime price events of a stock market.
+// Their iterators only end once the context given to Informer.Prices is
+// canceled. Consumers reading charts to their end, like the analyzer, must not
+// use endless informers.
+type Endless interface {
+	// Endless returns true in case the iterators of the informer do not end on
+	// their own.
+	Endless() bool
+}
+
+// IsEndless returns true in case the given informer implements Endless and
+// reports to be endless.
+func IsEndless(i Informer) bool {
+	e, ok := i.(Endless)
+	return ok && e.Endless()
+}
+
+// Iterator provides the price events of a single chart. An iterator must not be
+// used concurrently. The usual way to consume an iterator looks as follows.
+//
+//     defer it.Close()
+//
+//     for it.Next() {
+//         p := it.Price()
+//         ...
+//     }
+//     if it.Err() != nil {
+//         ...
+//     }
+//
+type Iterator interface {
+	// Close releases all resources held by the iterator. Close can be called
+	// multiple times and must be called when an iterator is abandoned before
+	// Next returned false.
+	Close() error
+	// Err returns the error which caused Next to return false, if any. When the
+	// chart was consumed completely, Err returns nil. When the context given to
+	// Informer.Prices was canceled, Err returns the error of the context.
+	Err() error
+	// Next advances the iterator to the next price event, which is then
+	// available via Price. Next returns false when there are no more price
+	// events to consume, or an error occurred.
+	Next() bool
+	// Price returns the current price event the iterator was advanced to using
+	// Next.
+	Price() Price
+}
@@ -0,0 +1,151 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/jsonpath"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/mapping"
+	"github.com/xh3b4sd/wafer/service/informer/timeformat"
+)
+
+// Decoder decodes the messages received from a WebSocket endpoint into price
+// events. Exchanges use all kinds of message formats, so the decoder is
+// pluggable.
+type Decoder interface {
+	// Decode returns the price events contained in the given message. Messages
+	// not containing price events, e.g. heartbeats or confirmations of
+	// subscriptions, result in an empty list. Messages which cannot be decoded
+	// cause an error.
+	Decode(message []byte) ([]informer.Price, error)
+}
+
+// NewMappingDecoder returns a Decoder mapping JSON messages to price events
+// using the given mapping. Each message describes a single price event. The
+// quality policies of the mapping are not used by the decoder. Messages in
+// which the buy price cannot be found are not considered ticks and ignored.
+func NewMappingDecoder(m mapping.Mapping) (Decoder, error) {
+	err := m.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "mapping: %s", err.Error())
+	}
+
+	timeParser, err := timeformat.NewParser(m.TimeFormat, m.TimeZone)
+	if timeformat.IsInvalidConfig(err) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "mapping: %s", err.Error())
+	} else if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	// The paths have been validated above, so parsing them cannot fail anymore.
+	buy, _ := jsonpath.Parse(m.Buy)
+	sell, _ := jsonpath.Parse(m.Sell)
+	when, _ := jsonpath.Parse(m.Time)
+
+	var volume *jsonpath.Path
+	if m.Volume != "" {
+		p, _ := jsonpath.Parse(m.Volume)
+		volume = &p
+	}
+
+	d := &mappingDecoder{
+		buy:    buy,
+		sell:   sell,
+		time:   timeParser,
+		volume: volume,
+		when:   when,
+	}
+
+	return d, nil
+}
+
+// mappingDecoder implements Decoder.
+type mappingDecoder struct {
+	buy    jsonpath.Path
+	sell   jsonpath.Path
+	time   *timeformat.Parser
+	volume *jsonpath.Path
+	when   jsonpath.Path
+}
+
+func (d *mappingDecoder) Decode(message []byte) ([]informer.Price, error) {
+	var v interface{}
+	{
+		dec := json.NewDecoder(bytes.NewReader(message))
+		dec.UseNumber()
+		err := dec.Decode(&v)
+		if err != nil {
+			return nil, microerror.MaskAnyf(malformedMessageError, "%s", err.Error())
+		}
+	}
+
+	if _, err := d.buy.Lookup(v); jsonpath.IsNotFound(err) {
+		return nil, nil
+	}
+
+	b, err := d.float(v, d.buy)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	s, err := d.float(v, d.sell)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	t, err := d.timestamp(v, d.when)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	var vol float64
+	if d.volume != nil {
+		vol, err = d.float(v, *d.volume)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	price := informer.Price{
+		Buy:    b,
+		Sell:   s,
+		Time:   t,
+		Volume: vol,
+	}
+
+	return []informer.Price{price}, nil
+}
+
+// float looks up the value of the given path and parses it as float64.
+func (d *mappingDecoder) float(v interface{}, p jsonpath.Path) (float64, error) {
+	s, err := p.Scalar(v)
+	if err != nil {
+		return 0, microerror.MaskAnyf(malformedMessageError, "%s", err.Error())
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, microerror.MaskAnyf(malformedMessageError, "%s", err.Error())
+	}
+
+	return f, nil
+}
+
+// timestamp looks up the value of the given path and parses it according to
+// the configured time format.
+func (d *mappingDecoder) timestamp(v interface{}, p jsonpath.Path) (time.Time, error) {
+	s, err := p.Scalar(v)
+	if err != nil {
+		return time.Time{}, microerror.MaskAnyf(malformedMessageError, "%s", err.Error())
+	}
+
+	t, err := d.time.Parse(s)
+	if err != nil {
+		return time.Time{}, microerror.MaskAnyf(malformedMessageError, "%s", err.Error())
+	}
+
+	return t, nil
+}
@@ -0,0 +1,26 @@
+package stream
+
+import (
+	"github.com/juju/errgo"
+)
+
+var connectionFailedError = errgo.New("connection failed")
+
+// IsConnectionFailed asserts connectionFailedError.
+func IsConnectionFailed(err error) bool {
+	return errgo.Cause(err) == connectionFailedError
+}
+
+var invalidConfigError = errgo.New("invalid config")
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return errgo.Cause(err) == invalidConfigError
+}
+
+var malformedMessageError = errgo.New("malformed message")
+
+// IsMalformedMessage asserts malformedMessageError.
+func IsMalformedMessage(err error) bool {
+	return errgo.Cause(err) == malformedMessageError
+}
@@ -0,0 +1,189 @@
+package stream
+
+import (
+	"net"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+	"golang.org/x/net/websocket"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	stateconnection "github.com/xh3b4sd/wafer/service/informer/runtime/state/connection"
+)
+
+// source implements cleaner.Source to provide the price events received from
+// the WebSocket endpoint. Read connects on demand and reconnects whenever the
+// connection is lost. Read never returns io.EOF, but the error of the context
+// once it is canceled, or the error of the last connection attempt once
+// reconnecting failed for good.
+type source struct {
+	ctx      context.Context
+	informer *Informer
+
+	conn *websocket.Conn
+	// done is closed when conn is closed, to stop watching the context.
+	done chan struct{}
+	// failures is the number of consecutive connection failures without
+	// receiving a single message in between.
+	failures int
+	pending  []informer.Price
+}
+
+func newSource(ctx context.Context, i *Informer) *source {
+	return &source{
+		ctx:      ctx,
+		informer: i,
+	}
+}
+
+func (s *source) Close() error {
+	s.disconnect()
+	s.informer.observeState(stateconnection.StateClosed, nil)
+
+	return nil
+}
+
+func (s *source) Read() (informer.Price, error) {
+	for {
+		if len(s.pending) != 0 {
+			p := s.pending[0]
+			s.pending = s.pending[1:]
+			return p, nil
+		}
+
+		if s.conn == nil {
+			err := s.connect()
+			if err != nil {
+				return informer.Price{}, microerror.MaskAny(err)
+			}
+		}
+
+		var message []byte
+		err := websocket.Message.Receive(s.conn, &message)
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return informer.Price{}, microerror.MaskAny(s.ctx.Err())
+			}
+
+			s.disconnect()
+			s.failures++
+			s.informer.observeState(stateconnection.StateDisconnected, err)
+			continue
+		}
+		s.conn.SetReadDeadline(time.Now().Add(s.informer.timeout))
+
+		prices, err := s.informer.decoder.Decode(message)
+		if err != nil {
+			s.informer.observeMalformed()
+			continue
+		}
+
+		s.failures = 0
+		s.pending = prices
+	}
+}
+
+// connect establishes a new connection and sends the subscription. Failed
+// connection attempts are retried according to the configured backoff. The
+// delay before each attempt depends on the number of consecutive failures, so
+// that the first connection is established right away.
+func (s *source) connect() error {
+	for {
+		if s.failures > s.informer.backoff.Retries {
+			err := microerror.MaskAnyf(connectionFailedError, "%s: giving up after %d retries", s.informer.location, s.informer.backoff.Retries)
+			s.informer.observeState(stateconnection.StateClosed, err)
+			return err
+		}
+		if s.failures > 0 {
+			err := s.sleep(s.informer.backoff.Delay(s.failures - 1))
+			if err != nil {
+				return microerror.MaskAny(err)
+			}
+		}
+
+		s.informer.observeState(stateconnection.StateConnecting, nil)
+
+		err := s.dial()
+		if s.ctx.Err() != nil {
+			s.disconnect()
+			return microerror.MaskAny(s.ctx.Err())
+		}
+		if err != nil {
+			s.failures++
+			s.informer.observeState(stateconnection.StateDisconnected, err)
+			continue
+		}
+
+		s.informer.observeState(stateconnection.StateConnected, nil)
+
+		return nil
+	}
+}
+
+// dial opens the WebSocket connection and sends the subscription. The
+// connection is closed as soon as the context is canceled, which unblocks
+// pending reads.
+func (s *source) dial() error {
+	config := &websocket.Config{
+		Dialer: &net.Dialer{
+			Timeout: s.informer.timeout,
+		},
+		Location: s.informer.location,
+		Origin:   s.informer.origin,
+		Version:  websocket.ProtocolVersionHybi13,
+	}
+
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	s.conn = conn
+	s.done = done
+
+	s.conn.SetDeadline(time.Now().Add(s.informer.timeout))
+
+	if s.informer.subscribe != "" {
+		err := websocket.Message.Send(s.conn, s.informer.subscribe)
+		if err != nil {
+			s.disconnect()
+			return microerror.MaskAny(err)
+		}
+	}
+
+	return nil
+}
+
+// disconnect closes the current connection, if any.
+func (s *source) disconnect() {
+	if s.conn == nil {
+		return
+	}
+
+	close(s.done)
+	s.conn.Close()
+	s.conn = nil
+	s.done = nil
+}
+
+// sleep blocks for the given duration or until the context is canceled.
+func (s *source) sleep(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-s.ctx.Done():
+		return microerror.MaskAny(s.ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,250 @@
+// Package stream provides the implementation of an informer subscribing to a
+// ticker channel of a WebSocket endpoint. Messages are decoded into price
+// events by a pluggable decoder. Lost connections are reestablished with
+// exponential backoff and the subscription is sent again after each
+// reconnect. The informer provides a single chart which never ends, until the
+// context given to Prices is canceled or reconnecting fails for good.
+package stream
+
+import (
+	"net/url"
+	"sync"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/backoff"
+	"github.com/xh3b4sd/wafer/service/informer/cleaner"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	configquality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/config/file/header/quality"
+	runtimestate "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+	pricequality "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price/quality"
+	stateconnection "github.com/xh3b4sd/wafer/service/informer/runtime/state/connection"
+)
+
+// Config is the configuration used to create a new informer.
+type Config struct {
+	// Dependencies.
+	Decoder Decoder
+
+	// Settings.
+
+	// Backoff describes how lost connections are reestablished. Backoff.Retries
+	// is the number of consecutive reconnects tolerated without receiving a
+	// single message in between.
+	Backoff backoff.Backoff
+	// Origin is the origin sent with the WebSocket handshake.
+	Origin string
+	// Quality describes the policies applied to the received price events. The
+	// duplicate and order policies default to drop. The dedupe-keep-last and
+	// sort policies are not supported, since they require to look ahead of the
+	// most recent price event.
+	Quality configquality.Quality
+	// Subscribe is the message sent right after each connection is established,
+	// e.g. to subscribe to the ticker channel of a certain market. Subscribe can
+	// be empty in case the endpoint does not require subscriptions.
+	Subscribe string
+	// Timeout is the maximum duration to wait for a connection to be
+	// established, and the maximum duration to wait for the next message before
+	// the connection is considered lost.
+	Timeout time.Duration
+	// URL is the absolute WS or WSS address of the WebSocket endpoint.
+	URL string
+}
+
+// DefaultConfig returns the default configuration used to create a new informer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Dependencies.
+		Decoder: nil,
+
+		// Settings.
+		Backoff: backoff.Backoff{
+			Initial: time.Second,
+			Max:     time.Minute,
+			Retries: 10,
+		},
+		Origin:    "http://localhost/",
+		Quality:   configquality.Quality{},
+		Subscribe: "",
+		Timeout:   time.Minute,
+		URL:       "",
+	}
+}
+
+// New creates a new configured informer. New does not connect to the WebSocket
+// endpoint. Each iterator returned by Prices maintains its own connection.
+func New(config Config) (informer.Informer, error) {
+	// Dependencies.
+	if config.Decoder == nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Decoder must not be empty")
+	}
+
+	// Settings.
+	err := config.Backoff.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Backoff: %s", err.Error())
+	}
+	if config.Timeout <= 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Timeout must be greater than 0")
+	}
+
+	location, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.URL: %s", err.Error())
+	}
+	if location.Scheme != "ws" && location.Scheme != "wss" {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.URL must be an absolute WS or WSS address")
+	}
+	origin, err := url.Parse(config.Origin)
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Origin: %s", err.Error())
+	}
+
+	q := config.Quality
+	if q.Duplicate == "" {
+		q.Duplicate = configquality.PolicyDrop
+	}
+	if q.Order == "" {
+		q.Order = configquality.PolicyDrop
+	}
+	if q.Duplicate == configquality.PolicyDedupeKeepLast {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Quality.Duplicate must not be '%s'", q.Duplicate)
+	}
+	if q.Order == configquality.PolicySort {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Quality.Order must not be '%s'", q.Order)
+	}
+	err = q.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Quality: %s", err.Error())
+	}
+
+	newInformer := &Informer{
+		// Dependencies.
+		decoder: config.Decoder,
+
+		// Settings.
+		backoff:   config.Backoff,
+		location:  location,
+		origin:    origin,
+		quality:   q,
+		subscribe: config.Subscribe,
+		timeout:   config.Timeout,
+
+		// Internals.
+		connection: stateconnection.Connection{},
+		mutex:      sync.Mutex{},
+		price:      stateprice.Price{},
+	}
+
+	return newInformer, nil
+}
+
+// Informer implements informer.Informer.
+type Informer struct {
+	// Dependencies.
+	decoder Decoder
+
+	// Settings.
+	backoff   backoff.Backoff
+	location  *url.URL
+	origin    *url.URL
+	quality   configquality.Quality
+	subscribe string
+	timeout   time.Duration
+
+	// Internals.
+	connection stateconnection.Connection
+	mutex      sync.Mutex
+	price      stateprice.Price
+}
+
+// Endless returns true, because the informer keeps receiving price events from
+// the WebSocket endpoint until the given context is canceled.
+func (i *Informer) Endless() bool {
+	return true
+}
+
+// Prices returns a single iterator providing the price events received from
+// the WebSocket endpoint. The iterator connects with the first call to Next,
+// which then blocks until the first price event was received. The runtime
+// state of the informer describes the connection and the price events of the
+// iterator created most recently.
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	var newCleaner *cleaner.Cleaner
+	{
+		c := cleaner.DefaultConfig()
+		c.Source = newSource(ctx, i)
+		c.Path = i.location.String()
+		c.Quality = i.quality
+		var err error
+		newCleaner, err = cleaner.New(c)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	i.mutex.Lock()
+	i.connection = stateconnection.Connection{}
+	i.price = stateprice.Price{}
+	i.mutex.Unlock()
+
+	return []informer.Iterator{newIterator(newCleaner, i)}, nil
+}
+
+func (i *Informer) Runtime() runtime.Runtime {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	r := runtime.Runtime{
+		State: runtimestate.State{
+			Connection: i.connection,
+			Prices:     []stateprice.Price{i.price},
+		},
+	}
+
+	return r
+}
+
+// observe records the given price event and the data quality summary of the
+// iterator providing it in the runtime state of the informer.
+func (i *Informer) observe(p informer.Price, q pricequality.Quality) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.price.Events == 0 {
+		i.price.Start = p.Time
+	}
+	i.price.End = p.Time
+	i.price.Events++
+	i.price.Quality = q
+}
+
+// observeMalformed records a message which could not be decoded in the runtime
+// state of the informer.
+func (i *Informer) observeMalformed() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.connection.Malformed++
+}
+
+// observeState records the given connection state in the runtime state of the
+// informer. The given error is the reason of the state change, if any.
+func (i *Informer) observeState(state string, err error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if state == stateconnection.StateConnected {
+		i.connection.Connects++
+	}
+	if err != nil {
+		i.connection.Error = err.Error()
+	}
+	i.connection.Since = time.Now()
+	i.connection.State = state
+}
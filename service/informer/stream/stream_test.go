@@ -0,0 +1,352 @@
+package stream
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/net/websocket"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/backoff"
+	"github.com/xh3b4sd/wafer/service/informer/jsonl/mapping"
+	stateconnection "github.com/xh3b4sd/wafer/service/informer/runtime/state/connection"
+)
+
+const testSubscribe = `{"event":"subscribe","channel":"ticker"}`
+
+// exchange is a stub of a WebSocket endpoint. Each connection is served by the
+// next script. A script is the list of messages sent to the client. Once all
+// messages are sent, the connection is closed in case the script says so, or
+// kept open until the client disconnects.
+type exchange struct {
+	mutex      sync.Mutex
+	scripts    []script
+	subscribes []string
+}
+
+type script struct {
+	Close    bool
+	Messages []string
+}
+
+func (e *exchange) Handler() websocket.Handler {
+	return func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		var subscribe string
+		err := websocket.Message.Receive(conn, &subscribe)
+		if err != nil {
+			return
+		}
+
+		e.mutex.Lock()
+		s := e.scripts[len(e.scripts)-1]
+		if len(e.subscribes) < len(e.scripts) {
+			s = e.scripts[len(e.subscribes)]
+		}
+		e.subscribes = append(e.subscribes, subscribe)
+		e.mutex.Unlock()
+
+		for _, m := range s.Messages {
+			err := websocket.Message.Send(conn, m)
+			if err != nil {
+				return
+			}
+		}
+
+		if s.Close {
+			return
+		}
+
+		// Block until the client goes away.
+		var message string
+		websocket.Message.Receive(conn, &message)
+	}
+}
+
+func (e *exchange) Subscribes() []string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return append([]string{}, e.subscribes...)
+}
+
+func testTick(buy float64, t int64) string {
+	return fmt.Sprintf(`{"type":"ticker","data":{"bid":"%.2f","ask":"%.2f","ts":%d}}`, buy, buy, t)
+}
+
+func testServer(e *exchange) (*httptest.Server, string) {
+	s := httptest.NewServer(e.Handler())
+	return s, "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func testConfig(t *testing.T, url string) Config {
+	d, err := NewMappingDecoder(mapping.Mapping{
+		Buy:  "data.bid",
+		Sell: "data.ask",
+		Time: "data.ts",
+	})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	config := DefaultConfig()
+	config.Backoff = backoff.Backoff{
+		Initial: time.Millisecond,
+		Max:     4 * time.Millisecond,
+		Retries: 2,
+	}
+	config.Decoder = d
+	config.Subscribe = testSubscribe
+	config.Timeout = 5 * time.Second
+	config.URL = url
+
+	return config
+}
+
+func testIterator(t *testing.T, config Config) (informer.Informer, informer.Iterator) {
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if len(iterators) != 1 {
+		t.Fatal("expected", 1, "got", len(iterators))
+	}
+
+	return newInformer, iterators[0]
+}
+
+// Test_Informer_Prices_Reconnect makes sure the informer reconnects and
+// resubscribes after the endpoint dropped the connection, while malformed
+// messages, heartbeats and duplicate ticks are skipped.
+func Test_Informer_Prices_Reconnect(t *testing.T) {
+	e := &exchange{
+		scripts: []script{
+			{
+				Close: true,
+				Messages: []string{
+					`{"event":"subscribed","channel":"ticker"}`,
+					testTick(10, 1),
+					`{"type":"ticker","data":{`,
+					testTick(11, 2),
+					testTick(11, 2),
+				},
+			},
+			{
+				Close: false,
+				Messages: []string{
+					`{"event":"heartbeat"}`,
+					testTick(12, 3),
+				},
+			},
+		},
+	}
+	s, url := testServer(e)
+	defer s.Close()
+
+	newInformer, it := testIterator(t, testConfig(t, url))
+	defer it.Close()
+
+	var buys []float64
+	for len(buys) < 3 && it.Next() {
+		buys = append(buys, it.Price().Buy)
+	}
+	if it.Err() != nil {
+		t.Fatal("expected", nil, "got", it.Err())
+	}
+	if !reflect.DeepEqual(buys, []float64{10, 11, 12}) {
+		t.Fatal("expected", []float64{10, 11, 12}, "got", buys)
+	}
+
+	subscribes := e.Subscribes()
+	if !reflect.DeepEqual(subscribes, []string{testSubscribe, testSubscribe}) {
+		t.Fatal("expected", 2, "subscriptions", "got", subscribes)
+	}
+
+	r := newInformer.Runtime()
+	if r.State.Connection.State != stateconnection.StateConnected {
+		t.Fatal("expected", stateconnection.StateConnected, "got", r.State.Connection.State)
+	}
+	if r.State.Connection.Connects != 2 {
+		t.Fatal("expected", 2, "got", r.State.Connection.Connects)
+	}
+	if r.State.Connection.Malformed != 1 {
+		t.Fatal("expected", 1, "got", r.State.Connection.Malformed)
+	}
+	if r.State.Connection.Error == "" {
+		t.Fatal("expected", "disconnect error", "got", "")
+	}
+	if r.State.Prices[0].Events != 3 || r.State.Prices[0].Quality.Duplicates != 1 {
+		t.Fatal("expected", "3 events and 1 duplicate", "got", r.State.Prices[0])
+	}
+
+	it.Close()
+	r = newInformer.Runtime()
+	if r.State.Connection.State != stateconnection.StateClosed {
+		t.Fatal("expected", stateconnection.StateClosed, "got", r.State.Connection.State)
+	}
+}
+
+// Test_Informer_Prices_GiveUp makes sure the iterator stops once reconnecting
+// failed for the configured number of retries.
+func Test_Informer_Prices_GiveUp(t *testing.T) {
+	e := &exchange{
+		scripts: []script{
+			{
+				Close:    true,
+				Messages: nil,
+			},
+		},
+	}
+	s, url := testServer(e)
+	defer s.Close()
+
+	newInformer, it := testIterator(t, testConfig(t, url))
+	defer it.Close()
+
+	if it.Next() {
+		t.Fatal("expected", false, "got", true)
+	}
+	if !IsConnectionFailed(it.Err()) {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	// The first connection and two retries are accepted but closed right away.
+	if len(e.Subscribes()) != 3 {
+		t.Fatal("expected", 3, "got", len(e.Subscribes()))
+	}
+	if newInformer.Runtime().State.Connection.State != stateconnection.StateClosed {
+		t.Fatal("expected", stateconnection.StateClosed, "got", newInformer.Runtime().State.Connection.State)
+	}
+}
+
+// Test_Informer_Prices_Unreachable makes sure endpoints which cannot be reached
+// are retried and eventually cause an error.
+func Test_Informer_Prices_Unreachable(t *testing.T) {
+	s, url := testServer(&exchange{})
+	s.Close()
+
+	_, it := testIterator(t, testConfig(t, url))
+	defer it.Close()
+
+	if it.Next() {
+		t.Fatal("expected", false, "got", true)
+	}
+	if !IsConnectionFailed(it.Err()) {
+		t.Fatal("expected", true, "got", false)
+	}
+}
+
+// Test_Informer_Prices_Cancel makes sure iterators waiting for the next
+// message stop as soon as their context is canceled.
+func Test_Informer_Prices_Cancel(t *testing.T) {
+	e := &exchange{
+		scripts: []script{
+			{
+				Close: false,
+				Messages: []string{
+					testTick(10, 1),
+				},
+			},
+		},
+	}
+	s, url := testServer(e)
+	defer s.Close()
+
+	newInformer, err := New(testConfig(t, url))
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	iterators, err := newInformer.Prices(ctx)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	it := iterators[0]
+	defer it.Close()
+
+	if !it.Next() {
+		t.Fatal("expected", true, "got", false)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	if it.Next() {
+		t.Fatal("expected", false, "got", true)
+	}
+	if it.Err() == nil {
+		t.Fatal("expected", "error", "got", nil)
+	}
+}
+
+func Test_MappingDecoder_Decode(t *testing.T) {
+	d, err := NewMappingDecoder(mapping.Mapping{
+		Buy:  "data.bid",
+		Sell: "data.ask",
+		Time: "data.ts",
+	})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Message      string
+		Expected     []informer.Price
+		ErrorMatcher func(error) bool
+	}{
+		// Test case 1, ticks are decoded into a single price event.
+		{
+			Message: testTick(10, 1),
+			Expected: []informer.Price{
+				{Buy: 10, Sell: 10, Time: time.Unix(1, 0)},
+			},
+			ErrorMatcher: nil,
+		},
+		// Test case 2, messages not containing ticks are ignored.
+		{
+			Message:      `{"event":"heartbeat"}`,
+			Expected:     nil,
+			ErrorMatcher: nil,
+		},
+		// Test case 3, messages which are no JSON cause an error.
+		{
+			Message:      `ping`,
+			Expected:     nil,
+			ErrorMatcher: IsMalformedMessage,
+		},
+		// Test case 4, ticks with invalid values cause an error.
+		{
+			Message:      `{"data":{"bid":"10","ask":true,"ts":1}}`,
+			Expected:     nil,
+			ErrorMatcher: IsMalformedMessage,
+		},
+	}
+
+	for i, testCase := range testCases {
+		prices, err := d.Decode([]byte(testCase.Message))
+		if testCase.ErrorMatcher == nil && err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if testCase.ErrorMatcher != nil && !testCase.ErrorMatcher(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+		if !reflect.DeepEqual(prices, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", prices)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package synthetic
+
+import (
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// iterator implements informer.Iterator for a single synthetic chart. Price
+// events are generated one after another, so charts of any length can be
+// generated without holding them in memory.
+type iterator struct {
+	ctx       context.Context
+	config    Config
+	done      bool
+	err       error
+	generator *generator
+	index     int
+	price     informer.Price
+}
+
+func newIterator(ctx context.Context, config Config, seed int64) *iterator {
+	return &iterator{
+		ctx:       ctx,
+		config:    config,
+		generator: newGenerator(config, seed),
+		index:     -1,
+	}
+}
+
+func (i *iterator) Close() error {
+	i.done = true
+
+	return nil
+}
+
+func (i *iterator) Err() error {
+	return i.err
+}
+
+func (i *iterator) Next() bool {
+	if i.done {
+		return false
+	}
+
+	select {
+	case <-i.ctx.Done():
+		i.err = microerror.MaskAny(i.ctx.Err())
+		i.done = true
+		return false
+	default:
+	}
+
+	i.index++
+	if i.index >= i.config.Length {
+		i.done = true
+		return false
+	}
+
+	b := i.generator.Next()
+
+	i.price = informer.Price{
+		Buy:  b,
+		Sell: b * (1 - i.config.Spread/100),
+		Time: i.config.Start.Add(time.Duration(i.index) * i.config.Interval),
+	}
+
+	return true
+}
+
+func (i *iterator) Price() informer.Price {
+	return i.price
+}
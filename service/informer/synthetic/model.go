@@ -0,0 +1,184 @@
+package synthetic
+
+import (
+	"math"
+	"math/rand"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+const (
+	// ModelGBM generates buy prices following a geometric Brownian motion. The
+	// logarithmic buy price changes by Drift plus normally distributed noise
+	// scaled by Volatility with each tick.
+	ModelGBM = "gbm"
+	// ModelMeanReverting generates buy prices being pulled back towards a mean
+	// price, as described by an Ornstein-Uhlenbeck process on the logarithmic
+	// buy price.
+	ModelMeanReverting = "mean-reverting"
+	// ModelRegimeSwitching generates buy prices following a geometric Brownian
+	// motion, which switches randomly between regimes having their own drift and
+	// volatility.
+	ModelRegimeSwitching = "regime-switching"
+)
+
+// MeanReverting configures the ModelMeanReverting model.
+type MeanReverting struct {
+	// Mean is the buy price the chart is pulled back to. Defaults to the buy
+	// price of the first price event.
+	Mean float64
+	// Speed is the fraction of the distance to the mean the logarithmic buy
+	// price is pulled back with each tick. It has to be between 0 and 1.
+	Speed float64
+}
+
+func (m MeanReverting) Validate() error {
+	if m.Mean < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "MeanReverting.Mean must not be negative")
+	}
+	if m.Speed < 0 || m.Speed > 1 {
+		return microerror.MaskAnyf(invalidConfigError, "MeanReverting.Speed must be between 0 and 1")
+	}
+
+	return nil
+}
+
+// RegimeSwitching configures the ModelRegimeSwitching model.
+type RegimeSwitching struct {
+	// Probability is the probability of switching to another regime with each
+	// tick. It has to be between 0 and 1.
+	Probability float64
+	// Regimes is the list of regimes to switch between. The first regime is the
+	// one the chart starts with. Defaults to a bull and a bear regime, having a
+	// drift of plus and minus Volatility respectively.
+	Regimes []Regime
+}
+
+func (r RegimeSwitching) Validate() error {
+	if r.Probability < 0 || r.Probability > 1 {
+		return microerror.MaskAnyf(invalidConfigError, "RegimeSwitching.Probability must be between 0 and 1")
+	}
+	for _, regime := range r.Regimes {
+		if regime.Volatility < 0 {
+			return microerror.MaskAnyf(invalidConfigError, "Regime.Volatility must not be negative")
+		}
+	}
+
+	return nil
+}
+
+// Regime is a market regime of the ModelRegimeSwitching model.
+type Regime struct {
+	// Drift is the expected change of the logarithmic buy price per tick.
+	Drift float64
+	// Volatility is the standard deviation of the logarithmic buy price change
+	// per tick.
+	Volatility float64
+}
+
+// Surge is a scripted price movement. The buy price changes by Change percent
+// over Length ticks, starting right after the price event at Offset. Consider
+// the following surge.
+//
+//     Change    10
+//     Length    5
+//     Offset    100
+//
+// This surge means the buy price rises by 10% between the price events at
+// index 100 and 105, in addition to the movement generated by the model.
+type Surge struct {
+	// Change is the price change in percent. Negative changes describe drops.
+	Change float64
+	// Length is the number of ticks the price change is spread across.
+	Length int
+	// Offset is the index of the price event the surge starts at.
+	Offset int
+}
+
+func (s Surge) Validate() error {
+	if s.Change <= -100 {
+		return microerror.MaskAnyf(invalidConfigError, "Surge.Change must be greater than -100")
+	}
+	if s.Length < 1 {
+		return microerror.MaskAnyf(invalidConfigError, "Surge.Length must be greater than 0")
+	}
+	if s.Offset < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "Surge.Offset must not be negative")
+	}
+
+	return nil
+}
+
+// generator generates the buy prices of a single chart tick by tick.
+type generator struct {
+	config  Config
+	index   int
+	log     float64
+	rand    *rand.Rand
+	regime  int
+	regimes []Regime
+}
+
+func newGenerator(config Config, seed int64) *generator {
+	regimes := config.RegimeSwitching.Regimes
+	if len(regimes) == 0 {
+		regimes = []Regime{
+			{Drift: config.Volatility, Volatility: config.Volatility},
+			{Drift: -config.Volatility, Volatility: config.Volatility},
+		}
+	}
+
+	newGenerator := &generator{
+		config:  config,
+		index:   -1,
+		log:     math.Log(config.Price),
+		rand:    rand.New(rand.NewSource(seed)),
+		regime:  0,
+		regimes: regimes,
+	}
+
+	return newGenerator
+}
+
+// Next returns the buy price of the next price event.
+func (g *generator) Next() float64 {
+	g.index++
+
+	if g.index == 0 {
+		return g.config.Price
+	}
+
+	g.log += g.change()
+
+	for _, s := range g.config.Surges {
+		if g.index > s.Offset && g.index <= s.Offset+s.Length {
+			g.log += math.Log(1+s.Change/100) / float64(s.Length)
+		}
+	}
+
+	return math.Exp(g.log)
+}
+
+// change returns the change of the logarithmic buy price generated by the
+// configured model for the current tick.
+func (g *generator) change() float64 {
+	c := g.config
+
+	switch c.Model {
+	case ModelMeanReverting:
+		mean := c.MeanReverting.Mean
+		if mean == 0 {
+			mean = c.Price
+		}
+		return c.MeanReverting.Speed*(math.Log(mean)-g.log) + c.Volatility*g.rand.NormFloat64()
+	case ModelRegimeSwitching:
+		r := g.regimes[g.regime]
+		change := r.Drift - r.Volatility*r.Volatility/2 + r.Volatility*g.rand.NormFloat64()
+		if len(g.regimes) > 1 && g.rand.Float64() < c.RegimeSwitching.Probability {
+			g.regime = (g.regime + 1 + g.rand.Intn(len(g.regimes)-1)) % len(g.regimes)
+		}
+		return change
+	default:
+		return c.Drift - c.Volatility*c.Volatility/2 + c.Volatility*g.rand.NormFloat64()
+	}
+}
@@ -0,0 +1,171 @@
+// Package synthetic provides the implementation of an informer generating
+// seeded synthetic charts. Charts generated with the same configuration are
+// always equal. That way strategies can be tested reproducibly against well
+// known market regimes, without depending on recorded chart data.
+package synthetic
+
+import (
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+)
+
+// Config is the configuration used to create a new informer.
+type Config struct {
+	// Settings.
+
+	// Charts is the number of charts to generate. Each chart is generated using
+	// its own seed, which is derived from Seed.
+	Charts int
+	// Drift is the expected change of the logarithmic buy price per tick, e.g.
+	// 0.0001. Drift is only used by the ModelGBM model.
+	Drift float64
+	// Interval is the duration between two consecutive price events.
+	Interval time.Duration
+	// Length is the number of price events of each chart.
+	Length int
+	// MeanReverting configures the ModelMeanReverting model.
+	MeanReverting MeanReverting
+	// Model is the model used to generate the buy prices of the charts. One of
+	// ModelGBM, ModelMeanReverting or ModelRegimeSwitching.
+	Model string
+	// Price is the buy price of the first price event of each chart.
+	Price float64
+	// RegimeSwitching configures the ModelRegimeSwitching model.
+	RegimeSwitching RegimeSwitching
+	// Seed is the seed of the random number generator. Different seeds result
+	// in different charts.
+	Seed int64
+	// Spread is the distance of the sell price below the buy price, in percent
+	// of the buy price.
+	Spread float64
+	// Start is the time of the first price event of each chart.
+	Start time.Time
+	// Surges is a list of scripted price movements applied on top of the
+	// configured model. Surges make it possible to test the reaction of
+	// strategies to sudden price movements at well known times.
+	Surges []Surge
+	// Volatility is the standard deviation of the logarithmic buy price change
+	// per tick, e.g. 0.001. Zero volatility results in charts without noise.
+	Volatility float64
+}
+
+// DefaultConfig returns the default configuration used to create a new informer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Settings.
+		Charts:          1,
+		Drift:           0,
+		Interval:        time.Minute,
+		Length:          1000,
+		MeanReverting:   MeanReverting{},
+		Model:           ModelGBM,
+		Price:           100,
+		RegimeSwitching: RegimeSwitching{},
+		Seed:            1,
+		Spread:          0.1,
+		Start:           time.Unix(1391212800, 0),
+		Surges:          nil,
+		Volatility:      0.001,
+	}
+}
+
+// New creates a new configured informer.
+func New(config Config) (informer.Informer, error) {
+	// Settings.
+	if config.Charts < 1 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Charts must be greater than 0")
+	}
+	if config.Interval <= 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Interval must be greater than 0")
+	}
+	if config.Length < 2 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Length must be at least 2")
+	}
+	if config.Price <= 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Price must be greater than 0")
+	}
+	if config.Spread < 0 || config.Spread >= 100 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Spread must be between 0 and 100")
+	}
+	if config.Start.IsZero() {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Start must not be empty")
+	}
+	if config.Volatility < 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Volatility must not be negative")
+	}
+
+	switch config.Model {
+	case ModelGBM:
+	case ModelMeanReverting:
+		err := config.MeanReverting.Validate()
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	case ModelRegimeSwitching:
+		err := config.RegimeSwitching.Validate()
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	default:
+		return nil, microerror.MaskAnyf(invalidConfigError, "config.Model must be one of %s, %s or %s", ModelGBM, ModelMeanReverting, ModelRegimeSwitching)
+	}
+
+	for _, s := range config.Surges {
+		err := s.Validate()
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	newInformer := &Informer{
+		// Settings.
+		config: config,
+
+		// Internals.
+		runtime: runtime.Runtime{},
+	}
+
+	for i := 0; i < config.Charts; i++ {
+		price := stateprice.Price{
+			End:    config.Start.Add(time.Duration(config.Length-1) * config.Interval),
+			Events: config.Length,
+			Start:  config.Start,
+		}
+		newInformer.runtime.State.Prices = append(newInformer.runtime.State.Prices, price)
+	}
+
+	return newInformer, nil
+}
+
+// Informer implements informer.Informer.
+type Informer struct {
+	// Settings.
+	config Config
+
+	// Internals.
+	runtime runtime.Runtime
+}
+
+// Prices returns a list of iterators generating price events, one for each
+// chart. Each call of Prices creates new iterators, which generate exactly the
+// same price events as the iterators of previous calls.
+func (i *Informer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	var iterators []informer.Iterator
+
+	for c := 0; c < i.config.Charts; c++ {
+		iterators = append(iterators, newIterator(ctx, i.config, i.config.Seed+int64(c)))
+	}
+
+	return iterators, nil
+}
+
+func (i *Informer) Runtime() runtime.Runtime {
+	return i.runtime
+}
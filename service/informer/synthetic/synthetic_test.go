@@ -0,0 +1,229 @@
+package synthetic
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+func testPrices(t *testing.T, config Config) [][]informer.Price {
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	iterators, err := newInformer.Prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	var charts [][]informer.Price
+	for _, it := range iterators {
+		var prices []informer.Price
+		for it.Next() {
+			prices = append(prices, it.Price())
+		}
+		if it.Err() != nil {
+			t.Fatal("expected", nil, "got", it.Err())
+		}
+		charts = append(charts, prices)
+	}
+
+	return charts
+}
+
+// Test_Informer_Prices_Deterministic makes sure charts generated with the same
+// seed are equal, and charts generated with different seeds are not.
+func Test_Informer_Prices_Deterministic(t *testing.T) {
+	for _, model := range []string{ModelGBM, ModelMeanReverting, ModelRegimeSwitching} {
+		config := DefaultConfig()
+		config.Charts = 2
+		config.Model = model
+		config.MeanReverting.Speed = 0.1
+		config.RegimeSwitching.Probability = 0.05
+
+		c1 := testPrices(t, config)
+		c2 := testPrices(t, config)
+		if !reflect.DeepEqual(c1, c2) {
+			t.Fatal("model", model, "expected", true, "got", false)
+		}
+		if reflect.DeepEqual(c1[0], c1[1]) {
+			t.Fatal("model", model, "expected", false, "got", true)
+		}
+
+		config.Seed = 2
+		c3 := testPrices(t, config)
+		if reflect.DeepEqual(c1, c3) {
+			t.Fatal("model", model, "expected", false, "got", true)
+		}
+	}
+}
+
+func Test_Informer_Prices_Shape(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+
+	config := DefaultConfig()
+	config.Interval = 10 * time.Second
+	config.Length = 100
+	config.Price = 200
+	config.Spread = 1
+	config.Start = start
+
+	charts := testPrices(t, config)
+	if len(charts) != 1 {
+		t.Fatal("expected", 1, "got", len(charts))
+	}
+	prices := charts[0]
+	if len(prices) != 100 {
+		t.Fatal("expected", 100, "got", len(prices))
+	}
+	if prices[0].Buy != 200 {
+		t.Fatal("expected", 200, "got", prices[0].Buy)
+	}
+
+	for i, p := range prices {
+		if !p.Time.Equal(start.Add(time.Duration(i) * 10 * time.Second)) {
+			t.Fatal("event", i, "expected", start.Add(time.Duration(i)*10*time.Second), "got", p.Time)
+		}
+		if p.Buy <= 0 {
+			t.Fatal("event", i, "expected", "positive buy price", "got", p.Buy)
+		}
+		if math.Abs(p.Sell-p.Buy*0.99) > 1e-9 {
+			t.Fatal("event", i, "expected", p.Buy*0.99, "got", p.Sell)
+		}
+	}
+
+	newInformer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	runtimePrices := newInformer.Runtime().State.Prices
+	if runtimePrices[0].Events != 100 || !runtimePrices[0].End.Equal(prices[99].Time) {
+		t.Fatal("expected", prices[99].Time, "got", runtimePrices[0])
+	}
+}
+
+// Test_Informer_Prices_Surge makes sure surges move the buy price exactly as
+// scripted in case there is no noise.
+func Test_Informer_Prices_Surge(t *testing.T) {
+	config := DefaultConfig()
+	config.Length = 20
+	config.Surges = []Surge{
+		{Change: 10, Length: 5, Offset: 5},
+		{Change: -50, Length: 1, Offset: 15},
+	}
+	config.Volatility = 0
+
+	prices := testPrices(t, config)[0]
+
+	testCases := []struct {
+		Index    int
+		Expected float64
+	}{
+		{Index: 0, Expected: 100},
+		{Index: 5, Expected: 100},
+		{Index: 10, Expected: 110},
+		{Index: 15, Expected: 110},
+		{Index: 16, Expected: 55},
+		{Index: 19, Expected: 55},
+	}
+
+	for i, testCase := range testCases {
+		b := prices[testCase.Index].Buy
+		if math.Abs(b-testCase.Expected) > 1e-9 {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", b)
+		}
+	}
+
+	// The surge is spread evenly across its ticks.
+	if !(prices[7].Buy > prices[6].Buy && prices[7].Buy < prices[8].Buy) {
+		t.Fatal("expected", "rising buy prices", "got", prices[6:9])
+	}
+}
+
+// Test_Informer_Prices_MeanReverting makes sure charts of the mean reverting
+// model stay around the mean, even when starting far away from it.
+func Test_Informer_Prices_MeanReverting(t *testing.T) {
+	config := DefaultConfig()
+	config.Length = 5000
+	config.MeanReverting = MeanReverting{
+		Mean:  50,
+		Speed: 0.05,
+	}
+	config.Model = ModelMeanReverting
+	config.Price = 100
+
+	prices := testPrices(t, config)[0]
+
+	var sum float64
+	for _, p := range prices[1000:] {
+		sum += p.Buy
+	}
+	avg := sum / float64(len(prices[1000:]))
+
+	if math.Abs(avg-50) > 1 {
+		t.Fatal("expected", 50, "got", avg)
+	}
+}
+
+// Test_Informer_Prices_RegimeSwitching makes sure charts of the regime
+// switching model follow the drift of their regimes.
+func Test_Informer_Prices_RegimeSwitching(t *testing.T) {
+	config := DefaultConfig()
+	config.Length = 200
+	config.Model = ModelRegimeSwitching
+	config.RegimeSwitching = RegimeSwitching{
+		Probability: 0,
+		Regimes: []Regime{
+			{Drift: -0.01, Volatility: 0},
+			{Drift: 0.01, Volatility: 0},
+		},
+	}
+
+	prices := testPrices(t, config)[0]
+	for i := 1; i < len(prices); i++ {
+		if prices[i].Buy >= prices[i-1].Buy {
+			t.Fatal("event", i, "expected", "falling buy prices", "got", prices[i].Buy)
+		}
+	}
+
+	config.RegimeSwitching.Probability = 1
+	prices = testPrices(t, config)[0]
+	for i := 2; i < len(prices); i++ {
+		rising := prices[i].Buy > prices[i-1].Buy
+		if rising == (prices[i-1].Buy > prices[i-2].Buy) {
+			t.Fatal("event", i, "expected", "alternating buy prices", "got", prices[i].Buy)
+		}
+	}
+}
+
+func Test_New_Error(t *testing.T) {
+	testCases := []func(c *Config){
+		func(c *Config) { c.Charts = 0 },
+		func(c *Config) { c.Interval = 0 },
+		func(c *Config) { c.Length = 1 },
+		func(c *Config) { c.Price = 0 },
+		func(c *Config) { c.Spread = 100 },
+		func(c *Config) { c.Start = time.Time{} },
+		func(c *Config) { c.Volatility = -1 },
+		func(c *Config) { c.Model = "random-walk" },
+		func(c *Config) { c.Model = ModelMeanReverting; c.MeanReverting.Speed = 2 },
+		func(c *Config) { c.Model = ModelRegimeSwitching; c.RegimeSwitching.Probability = -1 },
+		func(c *Config) { c.Surges = []Surge{{Change: 10, Length: 0}} },
+	}
+
+	for i, testCase := range testCases {
+		config := DefaultConfig()
+		testCase(&config)
+
+		_, err := New(config)
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Package timeformat provides the parsing of price times as they are found in
+// charts of different informers.
+package timeformat
+
+import (
+	"strconv"
+	"time"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+const (
+	// Unix is the time format of usual unix timestamps in seconds. This is the
+	// default in case no time format is configured.
+	Unix = "unix"
+	// UnixMilli is the time format of unix timestamps in milliseconds.
+	UnixMilli = "unixmilli"
+	// UnixNano is the time format of unix timestamps in nanoseconds.
+	UnixNano = "unixnano"
+	// RFC3339 is the time format of timestamps as described in RFC 3339, e.g.
+	// 2017-05-21T14:03:29Z.
+	RFC3339 = "rfc3339"
+)
+
+// Parser parses the time values of a chart according to a configured time
+// format and time zone.
+type Parser struct {
+	format   string
+	location *time.Location
+}
+
+// NewParser creates a new time parser for the given time format and time zone.
+// The format may be one of the Unix, UnixMilli, UnixNano or RFC3339 constants.
+// Any other non empty format is treated as layout as understood by time.Parse.
+// The zone is the name of a location as understood by time.LoadLocation, e.g.
+// UTC or Europe/Berlin. Values parsed using a layout without zone information are
+// interpreted in the configured location. Values of all other formats are
+// converted into the configured location. In case no zone is configured, the
+// local time zone is used.
+func NewParser(format, zone string) (*Parser, error) {
+	if format == "" {
+		format = Unix
+	}
+
+	location := time.Local
+	if zone != "" {
+		l, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, "unknown time zone '%s'", zone)
+		}
+		location = l
+	}
+
+	newParser := &Parser{
+		format:   format,
+		location: location,
+	}
+
+	return newParser, nil
+}
+
+// Parse parses the given value into a time.
+func (p *Parser) Parse(value string) (time.Time, error) {
+	switch p.format {
+	case Unix:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, microerror.MaskAny(err)
+		}
+		return p.In(time.Unix(i, 0)), nil
+	case UnixMilli:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, microerror.MaskAny(err)
+		}
+		return p.In(time.Unix(i/1e3, (i%1e3)*1e6)), nil
+	case UnixNano:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, microerror.MaskAny(err)
+		}
+		return p.In(time.Unix(0, i)), nil
+	case RFC3339:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return time.Time{}, microerror.MaskAny(err)
+		}
+		return p.In(t), nil
+	default:
+		t, err := time.ParseInLocation(p.format, value, p.location)
+		if err != nil {
+			return time.Time{}, microerror.MaskAny(err)
+		}
+		return t, nil
+	}
+}
+
+// In converts the given time into the configured location. Times are left
+// untouched when the local time zone is used, to keep them equal to the times
+// created by time.Unix.
+func (p *Parser) In(t time.Time) time.Time {
+	if p.location == time.Local {
+		return t
+	}
+
+	return t.In(p.location)
+}
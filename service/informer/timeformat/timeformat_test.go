@@ -0,0 +1,109 @@
+package timeformat
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Parser_Parse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	testCases := []struct {
+		Format       string
+		Zone         string
+		Value        string
+		Expected     time.Time
+		ErrorMatcher func(err error) bool
+	}{
+		// Test case 1 makes sure unix seconds are parsed by default.
+		{
+			Format:       "",
+			Zone:         "",
+			Value:        "1391212802",
+			Expected:     time.Unix(1391212802, 0),
+			ErrorMatcher: nil,
+		},
+		// Test case 2 makes sure unix milliseconds are parsed.
+		{
+			Format:       UnixMilli,
+			Zone:         "",
+			Value:        "1391212802123",
+			Expected:     time.Unix(1391212802, 123000000),
+			ErrorMatcher: nil,
+		},
+		// Test case 3 makes sure unix nanoseconds are parsed.
+		{
+			Format:       UnixNano,
+			Zone:         "",
+			Value:        "1391212802123456789",
+			Expected:     time.Unix(1391212802, 123456789),
+			ErrorMatcher: nil,
+		},
+		// Test case 4 makes sure RFC3339 timestamps are parsed and converted into
+		// the configured location.
+		{
+			Format:       RFC3339,
+			Zone:         "UTC",
+			Value:        "2014-02-01T00:00:02Z",
+			Expected:     time.Unix(1391212802, 0).UTC(),
+			ErrorMatcher: nil,
+		},
+		// Test case 5 makes sure arbitrary layouts without zone information are
+		// interpreted in the configured location.
+		{
+			Format:       "2006-01-02 15:04:05",
+			Zone:         "Europe/Berlin",
+			Value:        "2014-02-01 01:00:02",
+			Expected:     time.Unix(1391212802, 0).In(berlin),
+			ErrorMatcher: nil,
+		},
+		// Test case 6 makes sure the etherchain time format can be used as is.
+		{
+			Format:       "2006-01-02T15:04:05.000Z",
+			Zone:         "UTC",
+			Value:        "2014-02-01T00:00:02.000Z",
+			Expected:     time.Unix(1391212802, 0).UTC(),
+			ErrorMatcher: nil,
+		},
+		// Test case 7 makes sure values not matching the format cause an error.
+		{
+			Format:       Unix,
+			Zone:         "",
+			Value:        "2014-02-01T00:00:02Z",
+			Expected:     time.Time{},
+			ErrorMatcher: func(err error) bool { return err != nil },
+		},
+		// Test case 8 makes sure unknown time zones cause an error.
+		{
+			Format:       Unix,
+			Zone:         "Mars/Olympus",
+			Value:        "1391212802",
+			Expected:     time.Time{},
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		p, err := NewParser(testCase.Format, testCase.Zone)
+		var parsed time.Time
+		if err == nil {
+			parsed, err = p.Parse(testCase.Value)
+		}
+
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !parsed.Equal(testCase.Expected) || parsed.Location().String() != testCase.Expected.Location().String() {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", parsed)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package rsi
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// RSI describes the configuration of the relative strength index required for
+// sell events to happen. Consider the following configuration.
+//
+//     Indicator     rsi14
+//     Min           60
+//
+// This configuration means that sell events are not allowed to happen in case
+// the indicator named rsi14 is below 60, which means the market still has room
+// to rise. Instead of naming an indicator, the indicator can be described by
+// its period.
+//
+//     Min           60
+//     Period        14
+//
+type RSI struct {
+	// Indicator is the name of the RSI indicator to read from. Empty disables
+	// the check, unless Period is given.
+	Indicator string `json:"indicator"`
+	// Min is the minimum RSI required.
+	Min float64 `json:"min"`
+	// Period is the number of price events the RSI indicator covers. In case
+	// Period is given, the indicator is declared on behalf of the check.
+	Period int `json:"period"`
+}
+
+func (r RSI) Validate() error {
+	if r.Period < 0 {
+		return microerror.MaskAnyf(invalidConfigError, "RSI.Period must not be negative")
+	}
+	if (r.Indicator != "" || r.Period != 0) && (r.Min < 0 || r.Min >= 100) {
+		return microerror.MaskAnyf(invalidConfigError, "RSI.Min must be within [0, 100)")
+	}
+
+	return nil
+}
@@ -6,12 +6,14 @@ import (
 	"github.com/xh3b4sd/wafer/service/seller/runtime/config/trade/duration"
 	"github.com/xh3b4sd/wafer/service/seller/runtime/config/trade/fee"
 	"github.com/xh3b4sd/wafer/service/seller/runtime/config/trade/revenue"
+	"github.com/xh3b4sd/wafer/service/seller/runtime/config/trade/rsi"
 )
 
 type Trade struct {
 	Duration duration.Duration `json:"duration"`
 	Fee      fee.Fee           `json:"fee"`
 	Revenue  revenue.Revenue   `json:"revenue"`
+	RSI      rsi.RSI           `json:"rsi"`
 }
 
 func (t Trade) Validate() error {
@@ -29,6 +31,10 @@ func (t Trade) Validate() error {
 	if err != nil {
 		return microerror.MaskAnyf(invalidConfigError, err.Error())
 	}
+	err = t.RSI.Validate()
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
 
 	return nil
 }
@@ -1,9 +1,14 @@
 package state
 
 import (
+	"github.com/xh3b4sd/wafer/service/indicator"
 	"github.com/xh3b4sd/wafer/service/seller/runtime/state/trade"
 )
 
 type State struct {
-	Trade trade.Trade
+	// Indicators holds the configured indicators. Indicators is nil as long as
+	// the seller did not track any price event. Note that copies of the runtime
+	// share the indicators.
+	Indicators *indicator.Set
+	Trade      trade.Trade
 }
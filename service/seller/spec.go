@@ -5,12 +5,17 @@ package seller
 
 import (
 	"github.com/xh3b4sd/wafer/service/informer"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
 	"github.com/xh3b4sd/wafer/service/seller/runtime"
 )
 
 // Seller judges based on events to qualify if the situation at some stock
 // market is suited to sell commodities.
 type Seller interface {
+	// Observe takes every incoming price event, regardless of open trades, to
+	// keep the state of the seller up to date which does not depend on the
+	// judged trade, e.g. indicators.
+	Observe(currentPrice informer.Price) error
 	// Runtime returns a copy of information about the current runtime of the
 	// seller.
 	Runtime() runtime.Runtime
@@ -18,6 +23,8 @@ type Seller interface {
 	// to analyze the stock market situation to identify probabilities of sell
 	// events. In case Sell returns true, a sell event is intended to happen. A
 	// sell event indicates that the watched stock market is suitable to sell
-	// commodities.
-	Sell(currentPrice, buyPrice informer.Price) (bool, error)
+	// commodities. The given meta describes the market of the chart the price
+	// events belong to. In case it declares a fee schedule, the fees of the
+	// market are respected instead of the configured fees.
+	Sell(currentPrice, buyPrice informer.Price, meta statemeta.Meta) (bool, error)
 }
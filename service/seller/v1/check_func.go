@@ -23,3 +23,21 @@ func IsBelowMinTradeRevenue(r runtime.Runtime) (bool, error) {
 
 	return isBelowMinTradeRevenue, nil
 }
+
+// IsBelowMinRSI implements CheckFunc to make sure sell events do not happen
+// while the market still has momentum. E.g. when the relative strength index
+// is low, prices are not considered overbought yet and may rise further.
+func IsBelowMinRSI(r runtime.Runtime) (bool, error) {
+	name := r.Config.Trade.RSI.Indicator
+	if name == "" || r.State.Indicators == nil {
+		return false, nil
+	}
+	i, ok := r.State.Indicators.Get(name)
+	if !ok || !i.Ready() {
+		return false, nil
+	}
+
+	isBelowMinRSI := i.Value() < r.Config.Trade.RSI.Min
+
+	return isBelowMinRSI, nil
+}
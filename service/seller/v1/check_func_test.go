@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xh3b4sd/wafer/service/indicator"
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/seller/runtime"
+	"github.com/xh3b4sd/wafer/service/seller/runtime/config/trade/rsi"
+)
+
+func Test_IsBelowMinRSI(t *testing.T) {
+	testCases := []struct {
+		Prices   []float64
+		RSI      rsi.RSI
+		Expected bool
+	}{
+		// Test case 1 makes sure the check is disabled by default.
+		{
+			Prices:   []float64{5, 4, 3, 2},
+			Expected: false,
+		},
+		// Test case 2 makes sure indicators which are not ready do not prevent
+		// sell events.
+		{
+			Prices:   []float64{5, 4},
+			RSI:      rsi.RSI{Indicator: "rsi", Min: 60},
+			Expected: false,
+		},
+		// Test case 3 makes sure falling prices prevent sell events.
+		{
+			Prices:   []float64{5, 4, 3, 2},
+			RSI:      rsi.RSI{Indicator: "rsi", Min: 60},
+			Expected: true,
+		},
+		// Test case 4 makes sure rising prices allow sell events, even though
+		// the seller judges each price event multiple times.
+		{
+			Prices:   []float64{2, 3, 4, 5},
+			RSI:      rsi.RSI{Indicator: "rsi", Min: 60},
+			Expected: false,
+		},
+	}
+
+	for i, testCase := range testCases {
+		r := runtime.Runtime{}
+		r.Config.Indicators = map[string]indicator.Config{
+			"rsi": {Kind: indicator.KindRSI, Period: 3},
+		}
+		r.Config.Trade.RSI = testCase.RSI
+
+		for j, p := range testCase.Prices {
+			price := informer.Price{Buy: p, Time: time.Unix(int64(j), 0)}
+			for k := 0; k < 2; k++ {
+				var err error
+				r, err = NewUpdateIndicators(price)(r)
+				if err != nil {
+					t.Fatal("case", i+1, "expected", nil, "got", err)
+				}
+			}
+		}
+
+		ok, err := IsBelowMinRSI(r)
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ok)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"github.com/xh3b4sd/wafer/service/seller/runtime"
+)
+
+// InputFunc returns the values a check function bases its verdict on. Input
+// functions are only executed for decisions being traced.
+type InputFunc func(r runtime.Runtime) map[string]interface{}
+
+func belowMinRSIInputs(r runtime.Runtime) map[string]interface{} {
+	inputs := map[string]interface{}{
+		"indicator": r.Config.Trade.RSI.Indicator,
+		"min":       r.Config.Trade.RSI.Min,
+		"ready":     false,
+	}
+
+	name := r.Config.Trade.RSI.Indicator
+	if name == "" || r.State.Indicators == nil {
+		return inputs
+	}
+	i, ok := r.State.Indicators.Get(name)
+	if !ok || !i.Ready() {
+		return inputs
+	}
+	inputs["ready"] = true
+	inputs["rsi"] = i.Value()
+
+	return inputs
+}
+
+func belowMinTradeDurationInputs(r runtime.Runtime) map[string]interface{} {
+	return map[string]interface{}{
+		"duration": r.State.Trade.Duration.String(),
+		"min":      r.Config.Trade.Duration.Min.String(),
+	}
+}
+
+func belowMinTradeRevenueInputs(r runtime.Runtime) map[string]interface{} {
+	return map[string]interface{}{
+		"min":     r.Config.Trade.Revenue.Min,
+		"revenue": r.State.Trade.Revenue,
+	}
+}
@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"reflect"
+
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+	"github.com/xh3b4sd/wafer/service/seller/runtime/config"
+	"github.com/xh3b4sd/wafer/service/strategy"
+)
+
+// Check is a check function strategies can refer to by name.
+type Check struct {
+	// Func is the check function.
+	Func CheckFunc
+	// Inputs returns the values the check function bases its verdict on.
+	Inputs InputFunc
+	// Params returns a pointer to the section of the given config the check
+	// function reads its parameters from. Params is nil in case the check
+	// function does not accept params.
+	Params func(c *config.Config) interface{}
+	// Track are the names of the track functions the check function depends
+	// on.
+	Track []string
+}
+
+// Track is a track function check functions can depend on by name. Most track
+// functions of the seller depend on the price events of the judged trade, so
+// they are created for each sell event.
+type Track struct {
+	Name string
+	New  func(currentPrice, buyPrice informer.Price, meta statemeta.Meta) TrackFunc
+	// Observe is true for track functions which do not depend on the judged
+	// trade. They are created for each observed price event instead, regardless
+	// of open trades, and are given empty buy prices and metas.
+	Observe bool
+}
+
+// Checks are the check functions strategies can refer to by name.
+var Checks = map[string]Check{
+	"IsBelowMinRSI": {
+		Func:   IsBelowMinRSI,
+		Inputs: belowMinRSIInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.RSI },
+		Track:  []string{"UpdateIndicators"},
+	},
+	"IsBelowMinTradeDuration": {
+		Func:   IsBelowMinTradeDuration,
+		Inputs: belowMinTradeDurationInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.Duration },
+		Track:  []string{"SetCurrentDuration"},
+	},
+	"IsBelowMinTradeRevenue": {
+		Func:   IsBelowMinTradeRevenue,
+		Inputs: belowMinTradeRevenueInputs,
+		Params: func(c *config.Config) interface{} { return &c.Trade.Revenue },
+		Track:  []string{"SetCurrentRevenue"},
+	},
+}
+
+// Tracks are the track functions check functions can depend on, in the order
+// they are executed.
+var Tracks = []Track{
+	{
+		Name: "SetCurrentDuration",
+		New: func(currentPrice, buyPrice informer.Price, meta statemeta.Meta) TrackFunc {
+			return NewSetCurrentDuration(currentPrice, buyPrice)
+		},
+	},
+	{
+		Name: "SetCurrentRevenue",
+		New:  NewSetCurrentRevenue,
+	},
+	{
+		Name: "UpdateIndicators",
+		New: func(currentPrice, buyPrice informer.Price, meta statemeta.Meta) TrackFunc {
+			return NewUpdateIndicators(currentPrice)
+		},
+		Observe: true,
+	},
+}
+
+// DefaultRule returns the rule blocking sell events in case no strategy is
+// configured. The default rule blocks sell events as soon as any of the check
+// functions blocks them.
+func DefaultRule() strategy.Rule {
+	return strategy.Rule{
+		Any: []strategy.Rule{
+			{Check: "IsBelowMinTradeDuration"},
+			{Check: "IsBelowMinTradeRevenue"},
+			{Check: "IsBelowMinRSI"},
+		},
+	}
+}
+
+// ValidateRule returns an error in case the given rule refers to unknown check
+// functions or defines params the check functions do not accept.
+func ValidateRule(rule strategy.Rule) error {
+	_, _, err := newPipeline(rule, config.Config{})
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	return nil
+}
+
+// newPipeline returns the track functions the check functions of the given
+// rule depend on, in the order of Tracks. The returned config is the given
+// config having the params of the rule applied and the indicators described
+// by the params declared. Check functions sharing a config section must not be
+// given different params.
+func newPipeline(rule strategy.Rule, c config.Config) ([]Track, config.Config, error) {
+	names := map[string]bool{}
+	params := map[interface{}]map[string]interface{}{}
+
+	for _, l := range rule.Leaves() {
+		check, ok := Checks[l.Check]
+		if !ok {
+			return nil, config.Config{}, microerror.MaskAnyf(invalidConfigError, "unknown check '%s'", l.Check)
+		}
+		for _, t := range check.Track {
+			names[t] = true
+		}
+
+		if l.Params == nil {
+			continue
+		}
+		if check.Params == nil {
+			return nil, config.Config{}, microerror.MaskAnyf(invalidConfigError, "check '%s' must not define params", l.Check)
+		}
+		section := check.Params(&c)
+		if p, ok := params[section]; ok {
+			if !reflect.DeepEqual(p, l.Params) {
+				return nil, config.Config{}, microerror.MaskAnyf(invalidConfigError, "check '%s' must not define params conflicting with other checks", l.Check)
+			}
+			continue
+		}
+		err := strategy.Decode(l.Params, section)
+		if err != nil {
+			return nil, config.Config{}, microerror.MaskAnyf(invalidConfigError, "check '%s': %s", l.Check, err.Error())
+		}
+		params[section] = l.Params
+	}
+
+	c, err := c.DeclareIndicators()
+	if err != nil {
+		return nil, config.Config{}, microerror.MaskAny(err)
+	}
+
+	var tracks []Track
+	for _, t := range Tracks {
+		if names[t.Name] {
+			tracks = append(tracks, t)
+		}
+	}
+
+	return tracks, c, nil
+}
@@ -1,7 +1,11 @@
 package v1
 
 import (
+	microerror "github.com/giantswarm/microkit/error"
+
+	"github.com/xh3b4sd/wafer/service/indicator"
 	"github.com/xh3b4sd/wafer/service/informer"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
 	"github.com/xh3b4sd/wafer/service/seller/runtime"
 )
 
@@ -18,10 +22,37 @@ func NewSetCurrentDuration(currentPrice, buyPrice informer.Price) TrackFunc {
 }
 
 // NewSetCurrentRevenue returns a new function which implements TrackFunc to set
-// the current revenue to the runtime state.
-func NewSetCurrentRevenue(currentPrice, buyPrice informer.Price) TrackFunc {
+// the current revenue to the runtime state. In case the given meta declares a
+// fee schedule, the taker fee is respected for the buy and the sell event.
+// Otherwise the configured minimum fee is respected.
+func NewSetCurrentRevenue(currentPrice, buyPrice informer.Price, meta statemeta.Meta) TrackFunc {
 	return func(r runtime.Runtime) (runtime.Runtime, error) {
-		r.State.Trade.Revenue = calculateRevenue(buyPrice.Buy, currentPrice.Sell, r.Config.Trade.Fee.Min)
+		fee := r.Config.Trade.Fee.Min
+		if meta.Fee != nil {
+			fee = 2 * meta.Fee.Taker
+		}
+
+		r.State.Trade.Revenue = calculateRevenue(buyPrice.Buy, currentPrice.Sell, fee)
+
+		return r, nil
+	}
+}
+
+// NewUpdateIndicators returns a new function which implements TrackFunc to
+// update the configured indicators with the current price. The indicators are
+// updated with every observed price event, so that they cover the price events
+// between trades as well.
+func NewUpdateIndicators(currentPrice informer.Price) TrackFunc {
+	return func(r runtime.Runtime) (runtime.Runtime, error) {
+		if r.State.Indicators == nil {
+			s, err := indicator.NewSet(r.Config.Indicators)
+			if err != nil {
+				return runtime.Runtime{}, microerror.MaskAny(err)
+			}
+			r.State.Indicators = s
+		}
+
+		r.State.Indicators.Update(currentPrice)
 
 		return r, nil
 	}
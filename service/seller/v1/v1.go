@@ -5,16 +5,21 @@ import (
 	micrologger "github.com/giantswarm/microkit/logger"
 
 	"github.com/xh3b4sd/wafer/service/informer"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
 	"github.com/xh3b4sd/wafer/service/seller"
 	"github.com/xh3b4sd/wafer/service/seller/runtime"
 	"github.com/xh3b4sd/wafer/service/seller/runtime/config"
 	"github.com/xh3b4sd/wafer/service/seller/runtime/state"
+	"github.com/xh3b4sd/wafer/service/strategy"
+	"github.com/xh3b4sd/wafer/service/trace"
 )
 
 // Config is the configuration used to create a new seller.
 type Config struct {
 	// Dependencies.
 	Logger micrologger.Logger
+	// Tracer is optional. In case Tracer is nil, decisions are not recorded.
+	Tracer *trace.Tracer
 
 	// Settings.
 	Runtime config.Config
@@ -29,6 +34,7 @@ func DefaultConfig() Config {
 	config := Config{
 		// Dependencies.
 		Logger: nil,
+		Tracer: nil,
 
 		// Settings.
 		Runtime: runtimeConfig,
@@ -50,15 +56,36 @@ func New(config Config) (seller.Seller, error) {
 		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
 	}
 
+	// The check functions of the rule blocking sell events decide which track
+	// functions have to be executed. The params of the rule are applied to the
+	// runtime config, so they are validated like any other configuration.
+	block := DefaultRule()
+	if config.Runtime.Block != nil {
+		block = *config.Runtime.Block
+	}
+	tracks, runtimeConfig, err := newPipeline(block, config.Runtime)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+	err = runtimeConfig.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+
 	newSeller := &Seller{
 		// Dependencies.
 		logger: config.Logger,
+		tracer: config.Tracer,
 
 		// Internals.
+		block:  block,
+		chart:  "",
+		charts: map[string]state.State{},
 		runtime: runtime.Runtime{
-			Config: config.Runtime,
+			Config: runtimeConfig,
 			State:  state.State{},
 		},
+		tracks: tracks,
 	}
 
 	return newSeller, nil
@@ -68,45 +95,118 @@ func New(config Config) (seller.Seller, error) {
 type Seller struct {
 	// Dependencies.
 	logger micrologger.Logger
+	tracer *trace.Tracer
 
 	// Internals.
+	block strategy.Rule
+	// chart is the ID of the chart the state of the runtime belongs to.
+	chart string
+	// charts holds the states of the charts other than chart.
+	charts  map[string]state.State
 	runtime runtime.Runtime
+	tracks  []Track
 }
 
+// Observe updates the state of the seller which does not depend on open
+// trades, e.g. its indicators, with the given price event.
+func (s *Seller) Observe(currentPrice informer.Price) error {
+	s.switchChart(currentPrice.Chart)
+
+	for _, t := range s.tracks {
+		if !t.Observe {
+			continue
+		}
+
+		r, err := t.New(currentPrice, informer.Price{}, statemeta.Meta{})(s.runtime)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+		s.runtime = r
+	}
+
+	return nil
+}
+
+// Runtime returns the runtime of the seller. The state of the runtime is the
+// state of the chart of the price event the seller judged last.
 func (s *Seller) Runtime() runtime.Runtime {
 	return s.runtime
 }
 
-func (s *Seller) Sell(currentPrice, buyPrice informer.Price) (bool, error) {
+func (s *Seller) Sell(currentPrice, buyPrice informer.Price, meta statemeta.Meta) (bool, error) {
+	s.switchChart(currentPrice.Chart)
+
 	// Here we want to track the state of the current situation before we execute
-	// the check functions.
-	beforeTrackFuncs := []TrackFunc{
-		NewSetCurrentDuration(currentPrice, buyPrice),
-		NewSetCurrentRevenue(currentPrice, buyPrice),
-	}
+	// the check functions. The track functions are the ones the check functions
+	// of the rule depend on. Track functions observing every price event are
+	// executed by Observe.
+	for _, t := range s.tracks {
+		if t.Observe {
+			continue
+		}
 
-	for _, t := range beforeTrackFuncs {
-		r, err := t(s.runtime)
+		r, err := t.New(currentPrice, buyPrice, meta)(s.runtime)
 		if err != nil {
 			return false, microerror.MaskAny(err)
 		}
 		s.runtime = r
 	}
 
-	checkFuns := []CheckFunc{
-		IsBelowMinTradeDuration,
-		IsBelowMinTradeRevenue,
+	check := func(name string) (bool, error) {
+		return Checks[name].Func(s.runtime)
 	}
 
-	for _, c := range checkFuns {
-		ok, err := c(s.runtime)
+	// In case the decision is traced, all check functions of the rule are
+	// evaluated upfront to record their verdicts. The rule is then evaluated
+	// on the recorded verdicts.
+	var checks []trace.Check
+	traced := s.tracer != nil && s.tracer.Sample(trace.KindSell)
+	if traced {
+		var verdicts map[string]bool
+		var err error
+		checks, verdicts, err = traceChecks(s.block, s.runtime)
 		if err != nil {
 			return false, microerror.MaskAny(err)
 		}
-		if ok {
-			return false, nil
+		check = func(name string) (bool, error) {
+			return verdicts[name], nil
 		}
 	}
 
+	ok, err := s.block.Eval(check)
+	if err != nil {
+		return false, microerror.MaskAny(err)
+	}
+	if traced {
+		s.tracer.Add(trace.Record{
+			Checks: checks,
+			Fired:  !ok,
+			Kind:   trace.KindSell,
+			Price:  currentPrice,
+		})
+	}
+	if ok {
+		return false, nil
+	}
+
 	return true, nil
 }
+
+// switchChart makes the state of the runtime the state of the chart having the
+// given ID. The state of each chart is tracked on its own, so that the price
+// events of charts merged into a single stream do not affect each other. E.g.
+// the indicators of one market must not be updated with the price events of
+// another market.
+func (s *Seller) switchChart(chart string) {
+	if chart == s.chart {
+		return
+	}
+
+	s.charts[s.chart] = s.runtime.State
+
+	state := s.charts[chart]
+	delete(s.charts, chart)
+
+	s.chart = chart
+	s.runtime.State = state
+}
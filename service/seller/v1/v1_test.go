@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	micrologger "github.com/giantswarm/microkit/logger"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// Test_Seller_Observe makes sure the indicators of the seller are updated with
+// price events observed while no trade is open.
+func Test_Seller_Observe(t *testing.T) {
+	newLogger, err := micrologger.New(micrologger.DefaultConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	config := DefaultConfig()
+	config.Logger = newLogger
+	config.Runtime.Trade.Duration.Min = time.Minute
+	config.Runtime.Trade.Revenue.Min = 1
+	config.Runtime.Trade.RSI.Min = 60
+	config.Runtime.Trade.RSI.Period = 2
+	newSeller, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	for i, p := range []float64{5, 4, 3, 2} {
+		err := newSeller.Observe(informer.Price{Buy: p, Sell: p, Time: time.Unix(int64(i), 0)})
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	r := newSeller.Runtime()
+	if r.State.Indicators == nil {
+		t.Fatal("expected", "indicators", "got", nil)
+	}
+	i, ok := r.State.Indicators.Get(r.Config.Trade.RSI.Indicator)
+	if !ok || !i.Ready() {
+		t.Fatal("expected", "ready indicator", "got", i)
+	}
+	if i.Value() >= 60 {
+		t.Fatal("expected", "RSI below 60", "got", i.Value())
+	}
+
+	isBelowMinRSI, err := IsBelowMinRSI(r)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if !isBelowMinRSI {
+		t.Fatal("expected", true, "got", false)
+	}
+}
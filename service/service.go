@@ -3,6 +3,9 @@
 package service
 
 import (
+	"strings"
+	"time"
+
 	microerror "github.com/giantswarm/microkit/error"
 	micrologger "github.com/giantswarm/microkit/logger"
 	"github.com/spf13/viper"
@@ -11,7 +14,12 @@ import (
 	"github.com/xh3b4sd/wafer/service/analyzer"
 	v1analyzer "github.com/xh3b4sd/wafer/service/analyzer/v1"
 	"github.com/xh3b4sd/wafer/service/informer"
-	"github.com/xh3b4sd/wafer/service/informer/csv"
+	"github.com/xh3b4sd/wafer/service/informer/candle"
+	"github.com/xh3b4sd/wafer/service/informer/merge"
+	"github.com/xh3b4sd/wafer/service/informer/registry"
+	"github.com/xh3b4sd/wafer/service/informer/replay"
+	"github.com/xh3b4sd/wafer/service/informer/slice"
+	"github.com/xh3b4sd/wafer/service/strategy"
 	"github.com/xh3b4sd/wafer/service/version"
 )
 
@@ -60,26 +68,133 @@ func New(config Config) (*Service, error) {
 
 	var err error
 
+	var informerRegistry *registry.Registry
+	{
+		registryConfig := registry.DefaultConfig()
+		registryConfig.Flag = config.Flag
+		registryConfig.Viper = config.Viper
+		informerRegistry, err = registry.New(registryConfig)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
 	var informerService informer.Informer
 	{
-		informerConfig := csv.DefaultConfig()
-		//		informerConfig.File.Header.Buy = 1
-		//		informerConfig.File.Header.Ignore = false
-		//		informerConfig.File.Header.Sell = 2
-		//		informerConfig.File.Header.Time = 0
-		//		informerConfig.File.Path = "/Users/xh3b4sd/go/src/github.com/xh3b4sd/wafer/charts/001/chart.csv"
-		informerConfig.Dir.Path = "/Users/xh3b4sd/go/src/github.com/xh3b4sd/wafer/charts/"
-		informerService, err = csv.New(informerConfig)
+		informerService, err = informerRegistry.Informer()
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	// The analyzer is the only consumer of the informer and reads all charts to
+	// their end. Endless informer kinds like poll and stream need a consumer
+	// other than the analyzer.
+	if informer.IsEndless(informerService) {
+		return nil, microerror.MaskAnyf(invalidConfigError, "--%s=%s provides endless charts, which need a consumer other than the analyzer", config.Flag.Service.Informer.Kind, config.Viper.GetString(config.Flag.Service.Informer.Kind))
+	}
+
+	// The candle informer is only used in case it is enabled. It then wraps the
+	// informer created above, so that each chart is resampled into candles
+	// before charts are merged.
+	if config.Viper.GetBool(config.Flag.Service.Informer.Candle.Enabled) {
+		candleConfig := candle.DefaultConfig()
+		candleConfig.Informer = informerService
+		candleConfig.Interval = config.Viper.GetDuration(config.Flag.Service.Informer.Candle.Interval)
+		informerService, err = candle.New(candleConfig)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	// The merge informer is only used in case it is enabled. It then wraps the
+	// informer created above, so that all charts are consumed as a single stream
+	// of price events.
+	if config.Viper.GetBool(config.Flag.Service.Informer.Merge.Enabled) {
+		mergeConfig := merge.DefaultConfig()
+		mergeConfig.Informer = informerService
+		for _, id := range strings.Split(config.Viper.GetString(config.Flag.Service.Informer.Merge.IDs), ",") {
+			if strings.TrimSpace(id) == "" {
+				continue
+			}
+			mergeConfig.IDs = append(mergeConfig.IDs, strings.TrimSpace(id))
+		}
+		informerService, err = merge.New(mergeConfig)
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+	}
+
+	// The replay informer is only used in case it is enabled. It then wraps the
+	// informer created above, so the price events of any informer kind can be
+	// replayed at wall clock pace for live style consumers. The analyzer reads
+	// the charts as fast as possible instead, so neither creating its slice nor
+	// analyzing its permutations is bound to wall time.
+	var replayService *replay.Informer
+	if config.Viper.GetBool(config.Flag.Service.Informer.Replay.Enabled) {
+		replayConfig := replay.DefaultConfig()
+		replayConfig.Informer = informerService
+		replayConfig.Speed = config.Viper.GetFloat64(config.Flag.Service.Informer.Replay.Speed)
+		if s := config.Viper.GetString(config.Flag.Service.Informer.Replay.Start); s != "" {
+			replayConfig.Start, err = time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, microerror.MaskAnyf(invalidConfigError, "--%s must be RFC3339 formatted: %s", config.Flag.Service.Informer.Replay.Start, err.Error())
+			}
+		}
+		replayService, err = replay.New(replayConfig)
 		if err != nil {
 			return nil, microerror.MaskAny(err)
 		}
 	}
 
+	var slices []slice.Slice
+	for _, s := range strings.Split(config.Viper.GetString(config.Flag.Service.Analyzer.Slices), ",") {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		newSlice, err := slice.Parse(strings.TrimSpace(s))
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, "--%s: %s", config.Flag.Service.Analyzer.Slices, err.Error())
+		}
+		slices = append(slices, newSlice)
+	}
+
+	var features []string
+	for _, f := range strings.Split(config.Viper.GetString(config.Flag.Service.Analyzer.Features), ",") {
+		if strings.TrimSpace(f) == "" {
+			continue
+		}
+		features = append(features, strings.TrimSpace(f))
+	}
+
+	var strategies []strategy.Strategy
+	if p := config.Viper.GetString(config.Flag.Service.Analyzer.Strategy); p != "" {
+		strategies, err = strategy.Read(p)
+		if err != nil {
+			return nil, microerror.MaskAnyf(invalidConfigError, "--%s: %s", config.Flag.Service.Analyzer.Strategy, err.Error())
+		}
+	}
+
 	var analyzerService analyzer.Analyzer
 	{
 		analyzerConfig := v1analyzer.DefaultConfig()
 		analyzerConfig.Informer = informerService
 		analyzerConfig.Logger = config.Logger
+		analyzerConfig.Buyer.Trade.Bollinger.K = config.Viper.GetFloat64(config.Flag.Service.Analyzer.Buyer.Bollinger.K)
+		analyzerConfig.Buyer.Trade.Bollinger.Period = config.Viper.GetInt(config.Flag.Service.Analyzer.Buyer.Bollinger.Period)
+		analyzerConfig.Buyer.Trade.RSI.Max = config.Viper.GetFloat64(config.Flag.Service.Analyzer.Buyer.RSI.Max)
+		analyzerConfig.Buyer.Trade.RSI.Period = config.Viper.GetInt(config.Flag.Service.Analyzer.Buyer.RSI.Period)
+		analyzerConfig.Features = features
+		analyzerConfig.Seller.Trade.RSI.Min = config.Viper.GetFloat64(config.Flag.Service.Analyzer.Seller.RSI.Min)
+		analyzerConfig.Seller.Trade.RSI.Period = config.Viper.GetInt(config.Flag.Service.Analyzer.Seller.RSI.Period)
+		analyzerConfig.Slice = config.Viper.GetString(config.Flag.Service.Analyzer.Slice)
+		analyzerConfig.Slices = slices
+		analyzerConfig.Strategies = strategies
+		analyzerConfig.Trace.Check = config.Viper.GetString(config.Flag.Service.Analyzer.Trace.Check)
+		analyzerConfig.Trace.Every = config.Viper.GetInt(config.Flag.Service.Analyzer.Trace.Every)
+		analyzerConfig.Trace.Limit = config.Viper.GetInt(config.Flag.Service.Analyzer.Trace.Limit)
+		analyzerConfig.Trace.Outcome = config.Viper.GetString(config.Flag.Service.Analyzer.Trace.Outcome)
+		analyzerConfig.TraceExport = config.Viper.GetString(config.Flag.Service.Analyzer.Trace.Export)
 		analyzerService, err = v1analyzer.New(analyzerConfig)
 		if err != nil {
 			return nil, microerror.MaskAny(err)
@@ -105,6 +220,7 @@ func New(config Config) (*Service, error) {
 
 	newService := &Service{
 		Analyzer: analyzerService,
+		Replay:   replayService,
 		Version:  versionService,
 	}
 
@@ -113,5 +229,9 @@ func New(config Config) (*Service, error) {
 
 type Service struct {
 	Analyzer analyzer.Analyzer
-	Version  *version.Service
+	// Replay provides the price events of the configured informer at wall
+	// clock pace to live style consumers and controls their replay. Replay is
+	// nil in case the replay is not enabled.
+	Replay  *replay.Informer
+	Version *version.Service
 }
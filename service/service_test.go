@@ -0,0 +1,116 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	micrologger "github.com/giantswarm/microkit/logger"
+	"github.com/spf13/viper"
+
+	"github.com/xh3b4sd/wafer/flag"
+	"github.com/xh3b4sd/wafer/service/informer/registry"
+)
+
+func Test_Service_New(t *testing.T) {
+	newLogger, err := micrologger.New(micrologger.DefaultConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	f := flag.New()
+
+	testCases := []struct {
+		Values       map[string]interface{}
+		Replay       bool
+		ErrorMatcher func(err error) bool
+	}{
+		// Test case 1 makes sure the service is created without waiting for the
+		// replay of the charts in case replay and a slice are both configured.
+		// The synthetic chart covers 1000 minutes of price events, which would be
+		// replayed in real time.
+		{
+			Values: map[string]interface{}{
+				f.Service.Analyzer.Slice:           "train",
+				f.Service.Analyzer.Slices:          "train=0..0.7,test=0.7..1",
+				f.Service.Informer.Kind:            registry.KindSynthetic,
+				f.Service.Informer.Replay.Enabled:  true,
+				f.Service.Informer.Replay.Speed:    1,
+				f.Service.Informer.Synthetic.Model: "gbm",
+			},
+			Replay:       true,
+			ErrorMatcher: nil,
+		},
+		// Test case 2 makes sure the service is created without replay in case it
+		// is not enabled.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind: registry.KindSynthetic,
+			},
+			Replay:       false,
+			ErrorMatcher: nil,
+		},
+		// Test case 3 makes sure the endless poll informer is rejected, because
+		// the analyzer never reaches the end of its charts.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:      registry.KindPoll,
+				f.Service.Informer.Poll.Buy:  "ticker.buy",
+				f.Service.Informer.Poll.Sell: "ticker.sell",
+				f.Service.Informer.Poll.Time: "ticker.server_time",
+				f.Service.Informer.Poll.URL:  "http://127.0.0.1/ticker",
+			},
+			Replay:       false,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 4 makes sure the endless stream informer is rejected, even in
+		// case its price events are replayed.
+		{
+			Values: map[string]interface{}{
+				f.Service.Informer.Kind:           registry.KindStream,
+				f.Service.Informer.Replay.Enabled: true,
+				f.Service.Informer.Stream.Buy:     "data.bid",
+				f.Service.Informer.Stream.Sell:    "data.ask",
+				f.Service.Informer.Stream.Time:    "data.ts",
+				f.Service.Informer.Stream.URL:     "ws://127.0.0.1/ticker",
+			},
+			Replay:       false,
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		v := viper.New()
+		for k, val := range testCase.Values {
+			v.Set(k, val)
+		}
+
+		config := DefaultConfig()
+		config.Flag = f
+		config.Logger = newLogger
+		config.Viper = v
+
+		config.Description = "description"
+		config.GitCommit = "commit"
+		config.Name = "name"
+		config.Source = "source"
+
+		start := time.Now()
+		newService, err := New(config)
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("case", i+1, "expected", "no delay", "got", time.Since(start))
+		}
+
+		if (newService.Replay != nil) != testCase.Replay {
+			t.Fatal("case", i+1, "expected", testCase.Replay, "got", newService.Replay != nil)
+		}
+	}
+}
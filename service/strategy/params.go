@@ -0,0 +1,106 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+
+	microerror "github.com/giantswarm/microkit/error"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Decode decodes the given params into the config section out points to.
+// Params are named like the lower cased fields of the config section, e.g.
+// the params of the corridor of the buyer look like the following. Durations
+// are given in the format understood by time.ParseDuration.
+//
+//     percentile:
+//       max: 80
+//     window: 168h
+//
+// Fields of the config section not named by params keep their values. Decode
+// returns an error in case params name fields the config section does not
+// have.
+func Decode(params map[string]interface{}, out interface{}) error {
+	b, err := yaml.Marshal(out)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+	var fields map[string]interface{}
+	err = yaml.Unmarshal(b, &fields)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+	err = unknownParams("", params, fields)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+
+	b, err = yaml.Marshal(params)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+	err = yaml.Unmarshal(b, out)
+	if err != nil {
+		return microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+
+	return nil
+}
+
+// normalize converts the maps decoded from YAML documents, which are keyed by
+// arbitrary values, into maps keyed by strings, so that params can be encoded
+// as JSON, e.g. as part of the runtime of the analyzer.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, v := range t {
+			m[fmt.Sprintf("%v", k)] = normalize(v)
+		}
+		return m
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, v := range t {
+			m[k] = normalize(v)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, v := range t {
+			l[i] = normalize(v)
+		}
+		return l
+	}
+
+	return v
+}
+
+func unknownParams(prefix string, params map[string]interface{}, fields map[string]interface{}) error {
+	var keys []string
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		f, ok := fields[k]
+		if !ok {
+			return microerror.MaskAnyf(invalidConfigError, "unknown param '%s%s'", prefix, k)
+		}
+
+		p, ok := normalize(params[k]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		n, ok := normalize(f).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		err := unknownParams(prefix+k+".", p, n)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package strategy
+
+import (
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+// Rule composes named check functions. A rule is either a single check
+// function, the negation of a rule, or the conjunction or disjunction of a
+// list of rules. Check functions return true in case they block a trade, so
+// does a rule. Consider the following rule.
+//
+//     any:
+//     - check: IsAboveMaxBuys
+//     - all:
+//       - check: IsAboveMaxRSI
+//       - not:
+//           check: IsBelowMinSurge
+//
+// The rule blocks buy events in case too many buy events happened already or
+// in case the market is overbought, unless the market surges.
+type Rule struct {
+	// All is the list of rules which all have to hold for the rule to hold.
+	All []Rule `json:"all,omitempty" yaml:"all,omitempty"`
+	// Any is the list of rules of which any has to hold for the rule to hold.
+	Any []Rule `json:"any,omitempty" yaml:"any,omitempty"`
+	// Check is the name of the check function the rule evaluates.
+	Check string `json:"check,omitempty" yaml:"check,omitempty"`
+	// Not is the rule which must not hold for the rule to hold.
+	Not *Rule `json:"not,omitempty" yaml:"not,omitempty"`
+	// Params are the parameters of the check function. Params can only be
+	// given together with Check. See Decode.
+	Params map[string]interface{} `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// Eval evaluates the rule. The given function evaluates the check function
+// having the given name. Conjunctions and disjunctions are short circuited, so
+// check functions are only evaluated as long as their result matters.
+func (r Rule) Eval(check func(name string) (bool, error)) (bool, error) {
+	switch {
+	case r.Check != "":
+		ok, err := check(r.Check)
+		if err != nil {
+			return false, microerror.MaskAny(err)
+		}
+		return ok, nil
+	case r.Not != nil:
+		ok, err := r.Not.Eval(check)
+		if err != nil {
+			return false, microerror.MaskAny(err)
+		}
+		return !ok, nil
+	case len(r.All) != 0:
+		for _, a := range r.All {
+			ok, err := a.Eval(check)
+			if err != nil {
+				return false, microerror.MaskAny(err)
+			}
+			if !ok {
+				return false, nil
+			}
+		}
+		return true, nil
+	case len(r.Any) != 0:
+		for _, a := range r.Any {
+			ok, err := a.Eval(check)
+			if err != nil {
+				return false, microerror.MaskAny(err)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return false, nil
+}
+
+// Leaves returns the rules of the rule evaluating check functions, in the
+// order they appear in.
+func (r Rule) Leaves() []Rule {
+	if r.Check != "" {
+		return []Rule{r}
+	}
+	if r.Not != nil {
+		return r.Not.Leaves()
+	}
+
+	var leaves []Rule
+	for _, a := range r.All {
+		leaves = append(leaves, a.Leaves()...)
+	}
+	for _, a := range r.Any {
+		leaves = append(leaves, a.Leaves()...)
+	}
+
+	return leaves
+}
+
+// Validate returns an error in case the rule is not exactly one of a check
+// function, a negation, a conjunction or a disjunction.
+func (r Rule) Validate() error {
+	var n int
+	if r.All != nil {
+		n++
+	}
+	if r.Any != nil {
+		n++
+	}
+	if r.Check != "" {
+		n++
+	}
+	if r.Not != nil {
+		n++
+	}
+	if n != 1 {
+		return microerror.MaskAnyf(invalidConfigError, "rule must define exactly one of all, any, check or not")
+	}
+
+	if r.Params != nil && r.Check == "" {
+		return microerror.MaskAnyf(invalidConfigError, "rule must not define params without check")
+	}
+	if r.All != nil && len(r.All) == 0 {
+		return microerror.MaskAnyf(invalidConfigError, "rule must not define empty all")
+	}
+	if r.Any != nil && len(r.Any) == 0 {
+		return microerror.MaskAnyf(invalidConfigError, "rule must not define empty any")
+	}
+
+	for _, a := range r.All {
+		err := a.Validate()
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+	}
+	for _, a := range r.Any {
+		err := a.Validate()
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+	}
+	if r.Not != nil {
+		err := r.Not.Validate()
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+	}
+
+	return nil
+}
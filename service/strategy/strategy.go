@@ -0,0 +1,157 @@
+// Package strategy implements documents composing the named check functions
+// of the buyer and the seller into rules. Strategies make it possible to try
+// different rule sets without writing new buyer or seller versions.
+package strategy
+
+import (
+	"io/ioutil"
+
+	microerror "github.com/giantswarm/microkit/error"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Strategy is a named composition of the check functions of the buyer and the
+// seller. Strategies are declared in strategy documents, which are YAML or
+// JSON encoded. Consider the following strategy document.
+//
+//     strategies:
+//     - name: corridor
+//       buyer:
+//         any:
+//         - check: IsAboveMaxBuys
+//         - check: IsOutsidePercentileCorridor
+//           params:
+//             percentile:
+//               max: 80
+//             window: 168h
+//         - check: IsInsideMinTradePause
+//       seller:
+//         any:
+//         - check: IsBelowMinTradeDuration
+//         - check: IsBelowMinTradeRevenue
+//     - name: momentum
+//       buyer:
+//         any:
+//         - check: IsAboveMaxBuys
+//         - check: IsAboveMaxRSI
+//           params:
+//             max: 70
+//             period: 14
+//         - check: IsBelowMinSurge
+//
+// Each strategy can be analyzed on its own. The analyzer permutes the
+// strategies of a document together with the parameters of the check
+// functions.
+type Strategy struct {
+	// Name identifies the strategy within its document.
+	Name string `json:"name" yaml:"name"`
+	// Buyer is the rule blocking buy events. In case Buyer is nil, the default
+	// rule of the buyer is used.
+	Buyer *Rule `json:"buyer,omitempty" yaml:"buyer,omitempty"`
+	// Seller is the rule blocking sell events. In case Seller is nil, the
+	// default rule of the seller is used.
+	Seller *Rule `json:"seller,omitempty" yaml:"seller,omitempty"`
+}
+
+// Validate returns an error in case the strategy is not named or any of its
+// rules is invalid.
+func (s Strategy) Validate() error {
+	if s.Name == "" {
+		return microerror.MaskAnyf(invalidConfigError, "strategy name must not be empty")
+	}
+
+	if s.Buyer != nil {
+		err := s.Buyer.Validate()
+		if err != nil {
+			return microerror.MaskAnyf(invalidConfigError, "strategy '%s': buyer: %s", s.Name, err.Error())
+		}
+	}
+	if s.Seller != nil {
+		err := s.Seller.Validate()
+		if err != nil {
+			return microerror.MaskAnyf(invalidConfigError, "strategy '%s': seller: %s", s.Name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+type document struct {
+	Strategies []Strategy `yaml:"strategies"`
+}
+
+// Parse parses the given strategy document. Parse returns an error in case
+// the document does not declare any strategy, declares strategies sharing
+// their name or declares invalid strategies.
+func Parse(b []byte) ([]Strategy, error) {
+	var d document
+	err := yaml.Unmarshal(b, &d)
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+
+	if len(d.Strategies) == 0 {
+		return nil, microerror.MaskAnyf(invalidConfigError, "strategies must not be empty")
+	}
+
+	names := map[string]bool{}
+	for i, s := range d.Strategies {
+		err := s.Validate()
+		if err != nil {
+			return nil, microerror.MaskAny(err)
+		}
+		if names[s.Name] {
+			return nil, microerror.MaskAnyf(invalidConfigError, "strategy '%s' must be unique", s.Name)
+		}
+		names[s.Name] = true
+
+		d.Strategies[i].Buyer = normalizeRule(s.Buyer)
+		d.Strategies[i].Seller = normalizeRule(s.Seller)
+	}
+
+	return d.Strategies, nil
+}
+
+// Read reads and parses the strategy document at the given path. See Parse.
+func Read(path string) ([]Strategy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	strategies, err := Parse(b)
+	if err != nil {
+		return nil, microerror.MaskAny(err)
+	}
+
+	return strategies, nil
+}
+
+func normalizeRule(r *Rule) *Rule {
+	if r == nil {
+		return nil
+	}
+
+	n := *r
+	if r.Params != nil {
+		n.Params = normalize(r.Params).(map[string]interface{})
+	}
+	n.Not = normalizeRule(r.Not)
+	n.All = normalizeRules(r.All)
+	n.Any = normalizeRules(r.Any)
+
+	return &n
+}
+
+func normalizeRules(rules []Rule) []Rule {
+	if rules == nil {
+		return nil
+	}
+
+	n := make([]Rule, len(rules))
+	for i := range rules {
+		n[i] = *normalizeRule(&rules[i])
+	}
+
+	return n
+}
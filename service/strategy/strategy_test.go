@@ -0,0 +1,229 @@
+package strategy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Test_Parse makes sure YAML and JSON strategy documents are parsed and
+// invalid documents are rejected.
+func Test_Parse(t *testing.T) {
+	testCases := []struct {
+		Input        string
+		Expected     []Strategy
+		ErrorMatcher func(err error) bool
+	}{
+		// Test case 1, a YAML document having params.
+		{
+			Input: `
+strategies:
+- name: corridor
+  buyer:
+    any:
+    - check: IsAboveMaxBuys
+    - check: IsOutsidePercentileCorridor
+      params:
+        percentile:
+          max: 80
+        window: 168h
+`,
+			Expected: []Strategy{
+				{
+					Name: "corridor",
+					Buyer: &Rule{
+						Any: []Rule{
+							{Check: "IsAboveMaxBuys"},
+							{
+								Check: "IsOutsidePercentileCorridor",
+								Params: map[string]interface{}{
+									"percentile": map[string]interface{}{"max": 80},
+									"window":     "168h",
+								},
+							},
+						},
+					},
+				},
+			},
+			ErrorMatcher: nil,
+		},
+		// Test case 2, a JSON document.
+		{
+			Input: `{"strategies":[{"name":"a","seller":{"not":{"check":"IsBelowMinRSI"}}},{"name":"b"}]}`,
+			Expected: []Strategy{
+				{Name: "a", Seller: &Rule{Not: &Rule{Check: "IsBelowMinRSI"}}},
+				{Name: "b"},
+			},
+			ErrorMatcher: nil,
+		},
+		// Test case 3, strategies must be declared.
+		{
+			Input:        `strategies: []`,
+			Expected:     nil,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 4, strategy names must be unique.
+		{
+			Input:        `{"strategies":[{"name":"a"},{"name":"a"}]}`,
+			Expected:     nil,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 5, rules must define exactly one of all, any, check or not.
+		{
+			Input:        `{"strategies":[{"name":"a","buyer":{"check":"IsAboveMaxBuys","not":{"check":"IsAboveMaxRSI"}}}]}`,
+			Expected:     nil,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 6, params must be given together with checks.
+		{
+			Input:        `{"strategies":[{"name":"a","buyer":{"any":[{"check":"IsAboveMaxBuys"}],"params":{"max":1}}}]}`,
+			Expected:     nil,
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 7, conjunctions must not be empty.
+		{
+			Input:        `{"strategies":[{"name":"a","buyer":{"all":[]}}]}`,
+			Expected:     nil,
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		strategies, err := Parse([]byte(testCase.Input))
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if !reflect.DeepEqual(strategies, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", strategies)
+		}
+	}
+}
+
+// Test_Rule_Eval makes sure rules compose check functions by conjunction,
+// disjunction and negation.
+func Test_Rule_Eval(t *testing.T) {
+	checks := map[string]bool{
+		"a": true,
+		"b": false,
+	}
+
+	testCases := []struct {
+		Rule     Rule
+		Expected bool
+	}{
+		{Rule: Rule{Check: "a"}, Expected: true},
+		{Rule: Rule{Check: "b"}, Expected: false},
+		{Rule: Rule{Not: &Rule{Check: "a"}}, Expected: false},
+		{Rule: Rule{All: []Rule{{Check: "a"}, {Check: "b"}}}, Expected: false},
+		{Rule: Rule{All: []Rule{{Check: "a"}, {Not: &Rule{Check: "b"}}}}, Expected: true},
+		{Rule: Rule{Any: []Rule{{Check: "b"}, {Check: "a"}}}, Expected: true},
+		{Rule: Rule{Any: []Rule{{Check: "b"}, {Not: &Rule{Check: "a"}}}}, Expected: false},
+	}
+
+	for i, testCase := range testCases {
+		ok, err := testCase.Rule.Eval(func(name string) (bool, error) {
+			return checks[name], nil
+		})
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if ok != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", ok)
+		}
+	}
+}
+
+// Test_Rule_Eval_ShortCircuit makes sure check functions are not evaluated
+// once the result of a rule is known.
+func Test_Rule_Eval_ShortCircuit(t *testing.T) {
+	rule := Rule{
+		Any: []Rule{
+			{Check: "a"},
+			{Check: "b"},
+		},
+	}
+
+	var evaluated []string
+	_, err := rule.Eval(func(name string) (bool, error) {
+		evaluated = append(evaluated, name)
+		return true, nil
+	})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if !reflect.DeepEqual(evaluated, []string{"a"}) {
+		t.Fatal("expected", []string{"a"}, "got", evaluated)
+	}
+}
+
+// Test_Decode makes sure params are decoded into config sections and unknown
+// params are rejected.
+func Test_Decode(t *testing.T) {
+	type percentile struct {
+		Max float64 `json:"max"`
+		Min float64 `json:"min"`
+	}
+	type section struct {
+		Max        float64       `json:"max"`
+		Percentile percentile    `json:"percentile"`
+		Window     time.Duration `json:"window"`
+	}
+
+	testCases := []struct {
+		Params       map[string]interface{}
+		Expected     section
+		ErrorMatcher func(err error) bool
+	}{
+		// Test case 1, fields not named by params keep their values.
+		{
+			Params: map[string]interface{}{
+				"percentile": map[string]interface{}{"max": 80},
+				"window":     "168h",
+			},
+			Expected: section{
+				Max:        95,
+				Percentile: percentile{Max: 80, Min: 10},
+				Window:     168 * time.Hour,
+			},
+			ErrorMatcher: nil,
+		},
+		// Test case 2, unknown params are rejected.
+		{
+			Params:       map[string]interface{}{"maximum": 80},
+			ErrorMatcher: IsInvalidConfig,
+		},
+		// Test case 3, unknown nested params are rejected.
+		{
+			Params: map[string]interface{}{
+				"percentile": map[string]interface{}{"maximum": 80},
+			},
+			ErrorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for i, testCase := range testCases {
+		s := section{
+			Max:        95,
+			Percentile: percentile{Min: 10},
+		}
+		err := Decode(testCase.Params, &s)
+		if testCase.ErrorMatcher != nil {
+			if !testCase.ErrorMatcher(err) {
+				t.Fatal("case", i+1, "expected", true, "got", false)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+		if s != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", s)
+		}
+	}
+}
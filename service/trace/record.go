@@ -0,0 +1,49 @@
+package trace
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+const (
+	// KindBuy is the kind of records describing decisions of buyers.
+	KindBuy = "buy"
+	// KindSell is the kind of records describing decisions of sellers.
+	KindSell = "sell"
+)
+
+// Record describes the decision of a buyer or a seller on a single price
+// event. Consider the following record of a blocked buy event.
+//
+//     {
+//       "checks": [
+//         {"blocked": false, "inputs": {"concurrent": 1, "max": 3}, "name": "IsAboveMaxBuys"},
+//         {"blocked": true, "inputs": {"last": "...", "min": "2h0m0s", "time": "..."}, "name": "IsInsideMinTradePause"}
+//       ],
+//       "fired": false,
+//       "kind": "buy",
+//       "price": {...}
+//     }
+//
+type Record struct {
+	// Checks are the check functions of the rule of the buyer or the seller, in
+	// the order they appear in the rule.
+	Checks []Check `json:"checks"`
+	// Fired is true in case the price event triggered a buy or sell event.
+	Fired bool `json:"fired"`
+	// Kind is either KindBuy or KindSell.
+	Kind string `json:"kind"`
+	// Price is the price event the decision was made on.
+	Price informer.Price `json:"price"`
+}
+
+// Check describes the verdict of a single check function.
+type Check struct {
+	// Blocked is the verdict of the check function. Blocked is true in case the
+	// check function blocked the buy or sell event on its own.
+	Blocked bool `json:"blocked"`
+	// Inputs are the values the check function based its verdict on, e.g. the
+	// configured maximum and the currently observed value.
+	Inputs map[string]interface{} `json:"inputs"`
+	// Name is the name of the check function.
+	Name string `json:"name"`
+}
@@ -0,0 +1,168 @@
+// Package trace implements decision records of buyers and sellers. Records
+// explain which check functions blocked or allowed buy and sell events, so
+// strategies can be debugged without adding log lines.
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	microerror "github.com/giantswarm/microkit/error"
+)
+
+const (
+	// OutcomeBlocked restricts records to decisions not triggering buy or sell
+	// events.
+	OutcomeBlocked = "blocked"
+	// OutcomeFired restricts records to decisions triggering buy or sell
+	// events.
+	OutcomeFired = "fired"
+)
+
+// Config is the configuration used to create a new tracer.
+type Config struct {
+	// Settings.
+
+	// Check restricts records to decisions in which the check function having
+	// the given name blocked. Empty to not restrict records by check functions.
+	Check string
+	// Every is the sample rate of decisions. Only every nth decision of each
+	// kind is recorded. Decisions not being sampled do not cost anything.
+	Every int
+	// Limit is the maximum number of records being kept. Once the limit is
+	// reached, the oldest records are dropped.
+	Limit int
+	// Outcome restricts records to decisions having the given outcome, either
+	// OutcomeBlocked or OutcomeFired. Empty to not restrict records by outcome.
+	Outcome string
+}
+
+// DefaultConfig returns the default configuration used to create a new tracer
+// by best effort.
+func DefaultConfig() Config {
+	return Config{
+		// Settings.
+		Check:   "",
+		Every:   1,
+		Limit:   1000,
+		Outcome: "",
+	}
+}
+
+// Validate returns an error in case the config cannot be used to create a new
+// tracer.
+func (c Config) Validate() error {
+	if c.Every < 1 {
+		return microerror.MaskAnyf(invalidConfigError, "Every must be greater than 0")
+	}
+	if c.Limit < 1 {
+		return microerror.MaskAnyf(invalidConfigError, "Limit must be greater than 0")
+	}
+	if c.Outcome != "" && c.Outcome != OutcomeBlocked && c.Outcome != OutcomeFired {
+		return microerror.MaskAnyf(invalidConfigError, "Outcome must be one of '%s' or '%s'", OutcomeBlocked, OutcomeFired)
+	}
+
+	return nil
+}
+
+// New creates a new configured tracer.
+func New(config Config) (*Tracer, error) {
+	// Settings.
+	err := config.Validate()
+	if err != nil {
+		return nil, microerror.MaskAnyf(invalidConfigError, err.Error())
+	}
+
+	newTracer := &Tracer{
+		// Settings.
+		config: config,
+
+		// Internals.
+		decisions: map[string]int{},
+		mutex:     sync.Mutex{},
+		records:   nil,
+	}
+
+	return newTracer, nil
+}
+
+// Tracer collects the decision records of buyers and sellers. A single tracer
+// can be shared by the buyer and the seller of a trader, so their records are
+// kept in the order the decisions were made in.
+type Tracer struct {
+	// Settings.
+	config Config
+
+	// Internals.
+	decisions map[string]int
+	mutex     sync.Mutex
+	records   []Record
+}
+
+// Add adds the given record, in case it passes the configured filters.
+func (t *Tracer) Add(r Record) {
+	if t.config.Outcome == OutcomeBlocked && r.Fired {
+		return
+	}
+	if t.config.Outcome == OutcomeFired && !r.Fired {
+		return
+	}
+	if t.config.Check != "" && !blocked(r, t.config.Check) {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.records) >= t.config.Limit {
+		t.records = append(t.records[:0], t.records[len(t.records)-t.config.Limit+1:]...)
+	}
+	t.records = append(t.records, r)
+}
+
+// Records returns a copy of the records being kept, oldest first.
+func (t *Tracer) Records() []Record {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return append([]Record{}, t.records...)
+}
+
+// Sample counts a decision of the given kind and returns true in case the
+// decision is sampled. Buyers and sellers only build records for sampled
+// decisions.
+func (t *Tracer) Sample(kind string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	n := t.decisions[kind]
+	t.decisions[kind]++
+
+	return n%t.config.Every == 0
+}
+
+// Export writes the given records to the given writer as JSON lines, one
+// record per line.
+func Export(w io.Writer, records []Record) error {
+	e := json.NewEncoder(w)
+
+	for _, r := range records {
+		err := e.Encode(r)
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
+	}
+
+	return nil
+}
+
+func blocked(r Record, check string) bool {
+	for _, c := range r.Checks {
+		if c.Name == check && c.Blocked {
+			return true
+		}
+	}
+
+	return false
+}
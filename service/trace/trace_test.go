@@ -0,0 +1,166 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xh3b4sd/wafer/service/informer"
+)
+
+// Test_Tracer makes sure decisions are sampled, filtered and limited as
+// configured.
+func Test_Tracer(t *testing.T) {
+	testCases := []struct {
+		Config   func(config Config) Config
+		Expected []float64
+	}{
+		// Test case 1, all decisions are recorded.
+		{
+			Config: func(config Config) Config {
+				return config
+			},
+			Expected: []float64{1, 2, 3, 4, 5, 6},
+		},
+		// Test case 2, every 2nd decision is recorded.
+		{
+			Config: func(config Config) Config {
+				config.Every = 2
+				return config
+			},
+			Expected: []float64{1, 3, 5},
+		},
+		// Test case 3, only fired decisions are recorded.
+		{
+			Config: func(config Config) Config {
+				config.Outcome = OutcomeFired
+				return config
+			},
+			Expected: []float64{3, 6},
+		},
+		// Test case 4, only decisions blocked by the given check function are
+		// recorded.
+		{
+			Config: func(config Config) Config {
+				config.Check = "a"
+				return config
+			},
+			Expected: []float64{1, 5},
+		},
+		// Test case 5, only the latest decisions are kept.
+		{
+			Config: func(config Config) Config {
+				config.Limit = 2
+				return config
+			},
+			Expected: []float64{5, 6},
+		},
+	}
+
+	for i, testCase := range testCases {
+		newTracer, err := New(testCase.Config(DefaultConfig()))
+		if err != nil {
+			t.Fatal("case", i+1, "expected", nil, "got", err)
+		}
+
+		for n := 1; n <= 6; n++ {
+			if !newTracer.Sample(KindBuy) {
+				continue
+			}
+			newTracer.Add(testRecord(n))
+		}
+
+		var prices []float64
+		for _, r := range newTracer.Records() {
+			prices = append(prices, r.Price.Buy)
+		}
+		if !reflect.DeepEqual(prices, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", prices)
+		}
+	}
+}
+
+// Test_Tracer_Sample makes sure decisions of buyers and sellers are sampled
+// independently.
+func Test_Tracer_Sample(t *testing.T) {
+	config := DefaultConfig()
+	config.Every = 2
+	newTracer, err := New(config)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	var sampled []bool
+	for _, kind := range []string{KindBuy, KindSell, KindBuy, KindSell, KindBuy} {
+		sampled = append(sampled, newTracer.Sample(kind))
+	}
+
+	expected := []bool{true, true, false, false, true}
+	if !reflect.DeepEqual(sampled, expected) {
+		t.Fatal("expected", expected, "got", sampled)
+	}
+}
+
+func Test_Tracer_New(t *testing.T) {
+	testCases := []func(config Config) Config{
+		func(config Config) Config {
+			config.Every = 0
+			return config
+		},
+		func(config Config) Config {
+			config.Limit = 0
+			return config
+		},
+		func(config Config) Config {
+			config.Outcome = "unknown"
+			return config
+		},
+	}
+
+	for i, testCase := range testCases {
+		_, err := New(testCase(DefaultConfig()))
+		if !IsInvalidConfig(err) {
+			t.Fatal("case", i+1, "expected", true, "got", false)
+		}
+	}
+}
+
+// Test_Export makes sure records are exported as JSON lines.
+func Test_Export(t *testing.T) {
+	var b bytes.Buffer
+	err := Export(&b, []Record{testRecord(1), testRecord(2)})
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatal("expected", 2, "got", len(lines))
+	}
+
+	var r Record
+	err = json.Unmarshal([]byte(lines[1]), &r)
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if r.Kind != KindBuy || r.Price.Buy != 2 || len(r.Checks) != 2 || r.Checks[0].Name != "a" || r.Checks[0].Inputs["max"] != 2.0 {
+		t.Fatal("expected", testRecord(2), "got", r)
+	}
+}
+
+// testRecord returns the record of the nth decision. Every 3rd decision fires.
+// Other decisions are blocked by the check function a in case n is odd, and by
+// the check function b otherwise.
+func testRecord(n int) Record {
+	return Record{
+		Checks: []Check{
+			{Blocked: n%3 != 0 && n%2 != 0, Inputs: map[string]interface{}{"max": float64(n)}, Name: "a"},
+			{Blocked: n%3 != 0 && n%2 == 0, Inputs: map[string]interface{}{}, Name: "b"},
+		},
+		Fired: n%3 == 0,
+		Kind:  KindBuy,
+		Price: informer.Price{Buy: float64(n)},
+	}
+}
@@ -0,0 +1,12 @@
+package informer
+
+import (
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+)
+
+type Informer struct {
+	// Generation is the reload generation of the charts the trader executed on.
+	Generation int
+	// Prices are the price summaries of the charts the trader executed on.
+	Prices []price.Price
+}
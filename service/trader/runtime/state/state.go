@@ -1,9 +1,17 @@
 package state
 
 import (
+	"github.com/xh3b4sd/wafer/service/trace"
+	"github.com/xh3b4sd/wafer/service/trader/runtime/state/informer"
 	"github.com/xh3b4sd/wafer/service/trader/runtime/state/trade"
 )
 
 type State struct {
-	Trade trade.Trade
+	// Informer is the snapshot of the informer runtime the charts the trader
+	// executed on belong to. Charts reloaded during execution do not change it.
+	Informer informer.Informer
+	Trade    trade.Trade
+	// Trace are the decision records of the buyer and the seller. Trace is only
+	// set in case the trader is configured with a tracer.
+	Trace []trace.Record
 }
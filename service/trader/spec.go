@@ -7,6 +7,8 @@
 package trader
 
 import (
+	"golang.org/x/net/context"
+
 	"github.com/xh3b4sd/wafer/service/trader/runtime"
 )
 
@@ -19,8 +21,9 @@ import (
 // analyzer exchange or some real stock exchange API.
 type Trader interface {
 	// Execute runs the trader continuously and blocks until the configured
-	// informer does not provide any further price events.
-	Execute() error
+	// informer does not provide any further price events, or the given context
+	// is canceled.
+	Execute(ctx context.Context) error
 	// Runtime returns a copy of the current statistical information about the
 	// current trader process.
 	Runtime() runtime.Runtime
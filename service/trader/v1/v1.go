@@ -6,15 +6,20 @@ import (
 
 	microerror "github.com/giantswarm/microkit/error"
 	micrologger "github.com/giantswarm/microkit/logger"
+	"golang.org/x/net/context"
 
 	"github.com/xh3b4sd/wafer/service/buyer"
 	"github.com/xh3b4sd/wafer/service/client"
 	"github.com/xh3b4sd/wafer/service/informer"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
 	"github.com/xh3b4sd/wafer/service/seller"
+	"github.com/xh3b4sd/wafer/service/trace"
 	"github.com/xh3b4sd/wafer/service/trader"
 	"github.com/xh3b4sd/wafer/service/trader/runtime"
 	"github.com/xh3b4sd/wafer/service/trader/runtime/config"
 	"github.com/xh3b4sd/wafer/service/trader/runtime/state"
+	stateinformer "github.com/xh3b4sd/wafer/service/trader/runtime/state/informer"
 )
 
 // Config is the configuration used to create a new trader.
@@ -25,6 +30,10 @@ type Config struct {
 	Informer informer.Informer
 	Logger   micrologger.Logger
 	Seller   seller.Seller
+	// Tracer is optional. It is the tracer the buyer and the seller record their
+	// decisions with. In case Tracer is nil, the runtime of the trader does not
+	// provide decision records.
+	Tracer *trace.Tracer
 
 	// Settings.
 	Runtime config.Config
@@ -43,6 +52,7 @@ func DefaultConfig() Config {
 		Informer: nil,
 		Logger:   nil,
 		Seller:   nil,
+		Tracer:   nil,
 
 		// Settings.
 		Runtime: runtimeConfig,
@@ -83,6 +93,7 @@ func New(config Config) (trader.Trader, error) {
 		informer: config.Informer,
 		logger:   config.Logger,
 		seller:   config.Seller,
+		tracer:   config.Tracer,
 
 		// Internals.
 		runtime: runtime.Runtime{
@@ -102,23 +113,56 @@ type Trader struct {
 	informer informer.Informer
 	logger   micrologger.Logger
 	seller   seller.Seller
+	tracer   *trace.Tracer
 
 	// Internals.
 	runtime runtime.Runtime
 }
 
-func (t *Trader) Execute() error {
+func (t *Trader) Execute(ctx context.Context) error {
 	var buys []informer.Price
 
-	informerPrices := t.informer.Prices()
-	t.runtime.State.Trade.Cycles = make([]int64, len(informerPrices))
-	t.runtime.State.Trade.Revenues = make([]float64, len(informerPrices))
+	iterators, snapshot, err := t.prices(ctx)
+	if err != nil {
+		return microerror.MaskAny(err)
+	}
+	defer closeIterators(iterators)
+
+	prices := snapshot.Prices
+	t.runtime.State.Informer = snapshot
+
+	t.runtime.State.Trade.Cycles = make([]int64, len(iterators))
+	t.runtime.State.Trade.Revenues = make([]float64, len(iterators))
+
+	for i, it := range iterators {
+		var price stateprice.Price
+		if i < len(prices) {
+			price = prices[i]
+		}
+
+		for it.Next() {
+			p := it.Price()
+
+			// The market each chart belongs to decides about lot sizes and fees.
+			// Charts not declaring their market fall back to the defaults.
+			meta := metaFor(price, p.Chart)
+
+			// The seller observes every price event, so that its indicators do not
+			// only cover the price events of open trades.
+			err := t.seller.Observe(p)
+			if err != nil {
+				return microerror.MaskAny(err)
+			}
 
-	for i, c := range informerPrices {
-		for p := range c {
-			// Manage sell events.
+			// Manage sell events. Buy events can only be sold within the chart they
+			// originate from. This matters for informers merging multiple charts into
+			// a single iterator.
 			for _, b := range buys {
-				isSell, err := t.seller.Sell(p, b)
+				if b.Chart != p.Chart {
+					continue
+				}
+
+				isSell, err := t.seller.Sell(p, b, meta)
 				if err != nil {
 					return microerror.MaskAny(err)
 				}
@@ -126,7 +170,7 @@ func (t *Trader) Execute() error {
 				if !isSell {
 					continue
 				}
-				v := calculateVolume(p.Sell, t.runtime.Config.Trade.Budget)
+				v := calculateVolume(p.Sell, t.runtime.Config.Trade.Budget, meta.LotSize)
 				err = t.client.Sell(p, v)
 				if err != nil {
 					return microerror.MaskAny(err)
@@ -134,7 +178,7 @@ func (t *Trader) Execute() error {
 				t.logger.Log("event", "sell", "price", fmt.Sprintf("%.2f", p.Sell))
 
 				t.runtime.State.Trade.Cycles[i]++
-				t.runtime.State.Trade.Revenues[i] += (p.Sell * v) - (b.Buy * v)
+				t.runtime.State.Trade.Revenues[i] += calculateRevenue(b.Buy, p.Sell, v, meta.Fee)
 				t.buyer.DecrTradeConcurrent()
 				buys = removePrice(buys, b)
 			}
@@ -150,21 +194,75 @@ func (t *Trader) Execute() error {
 					continue
 				}
 				buys = append(buys, p)
-				err = t.client.Buy(p, calculateVolume(p.Buy, t.runtime.Config.Trade.Budget))
+				err = t.client.Buy(p, calculateVolume(p.Buy, t.runtime.Config.Trade.Budget, meta.LotSize))
 				if err != nil {
 					return microerror.MaskAny(err)
-					continue
 				}
 				t.logger.Log("event", "buy", "price", fmt.Sprintf("%.2f", p.Buy))
 			}
 		}
+
+		err := it.Err()
+		if err != nil {
+			return microerror.MaskAny(err)
+		}
 	}
 
 	return nil
 }
 
+// prices returns the iterators of the informer together with the reload
+// generation and the price summaries of its runtime. In case the informer
+// reloads its charts while the iterators are created, iterators and price
+// summaries might belong to different generations. Then the iterators are
+// created again.
+func (t *Trader) prices(ctx context.Context) ([]informer.Iterator, stateinformer.Informer, error) {
+	for {
+		generation := t.informer.Runtime().State.Reload.Generation
+
+		iterators, err := t.informer.Prices(ctx)
+		if err != nil {
+			return nil, stateinformer.Informer{}, microerror.MaskAny(err)
+		}
+
+		r := t.informer.Runtime()
+		if r.State.Reload.Generation == generation {
+			snapshot := stateinformer.Informer{
+				Generation: generation,
+				Prices:     r.State.Prices,
+			}
+
+			return iterators, snapshot, nil
+		}
+
+		closeIterators(iterators)
+	}
+}
+
 func (t *Trader) Runtime() runtime.Runtime {
-	return t.runtime
+	r := t.runtime
+	if t.tracer != nil {
+		r.State.Trace = t.tracer.Records()
+	}
+
+	return r
+}
+
+func closeIterators(iterators []informer.Iterator) {
+	for _, it := range iterators {
+		it.Close()
+	}
+}
+
+// metaFor returns the market of the chart identified by the given chart ID. In
+// case the chart is not known by its ID, the market of the price summary is
+// returned.
+func metaFor(price stateprice.Price, chart string) statemeta.Meta {
+	if meta, ok := price.Charts[chart]; ok {
+		return meta
+	}
+
+	return price.Meta
 }
 
 func removePrice(buys []informer.Price, price informer.Price) []informer.Price {
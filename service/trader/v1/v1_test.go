@@ -1,7 +1,24 @@
 package v1
 
 import (
+	"reflect"
 	"testing"
+	"time"
+
+	micrologger "github.com/giantswarm/microkit/logger"
+	"golang.org/x/net/context"
+
+	"github.com/xh3b4sd/wafer/service/buyer"
+	v1buyer "github.com/xh3b4sd/wafer/service/buyer/v1"
+	"github.com/xh3b4sd/wafer/service/informer"
+	"github.com/xh3b4sd/wafer/service/informer/csv/runtime"
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+	stateprice "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/price"
+	"github.com/xh3b4sd/wafer/service/informer/memory"
+	"github.com/xh3b4sd/wafer/service/informer/merge"
+	"github.com/xh3b4sd/wafer/service/seller"
+	v1seller "github.com/xh3b4sd/wafer/service/seller/v1"
+	"github.com/xh3b4sd/wafer/service/trader"
 )
 
 // Test_Trader_Runtime_Copy makes sure the provided runtime information cannot
@@ -25,6 +42,198 @@ func Test_Trader_Runtime_Copy(t *testing.T) {
 	}
 }
 
+// Test_Trader_Execute_Merge makes sure the charts merged into a single stream
+// of price events are judged independently of each other, even though their
+// prices are at different levels.
+func Test_Trader_Execute_Merge(t *testing.T) {
+	var newInformer informer.Informer
+	{
+		config := memory.DefaultConfig()
+		config.Charts = [][]informer.Price{
+			{
+				{Buy: 10000, Sell: 10000, Time: time.Unix(0, 0)},
+				{Buy: 9000, Sell: 9000, Time: time.Unix(120, 0)},
+			},
+			{
+				{Buy: 100, Sell: 100, Time: time.Unix(60, 0)},
+				{Buy: 90, Sell: 90, Time: time.Unix(180, 0)},
+			},
+		}
+		memoryInformer, err := memory.New(config)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+
+		mergeConfig := merge.DefaultConfig()
+		mergeConfig.IDs = []string{"btc", "eth"}
+		mergeConfig.Informer = memoryInformer
+		newInformer, err = merge.New(mergeConfig)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	newLogger, err := micrologger.New(micrologger.DefaultConfig())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	var newBuyer buyer.Buyer
+	{
+		config := v1buyer.DefaultConfig()
+		config.Logger = newLogger
+		config.Runtime.Trade.Corridor.Max = 95
+		config.Runtime.Trade.Pause.Min = time.Hour
+		newBuyer, err = v1buyer.New(config)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	var newSeller seller.Seller
+	{
+		config := v1seller.DefaultConfig()
+		config.Logger = newLogger
+		config.Runtime.Trade.Duration.Min = 24 * time.Hour
+		config.Runtime.Trade.Revenue.Min = 1
+		newSeller, err = v1seller.New(config)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	newClient := &testClient{}
+
+	var newTrader trader.Trader
+	{
+		config := DefaultConfig()
+		config.Buyer = newBuyer
+		config.Client = newClient
+		config.Informer = newInformer
+		config.Logger = newLogger
+		config.Seller = newSeller
+		newTrader, err = New(config)
+		if err != nil {
+			t.Fatal("expected", nil, "got", err)
+		}
+	}
+
+	err = newTrader.Execute(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+
+	// The first price event of each chart is the highest price of its chart, so
+	// it is outside the corridor. The second price event of each chart is inside
+	// the corridor of its chart. The buy event on one chart does not pause buy
+	// events on the other chart.
+	expected := []string{"btc", "eth"}
+	if !reflect.DeepEqual(newClient.buys, expected) {
+		t.Fatal("expected", expected, "got", newClient.buys)
+	}
+}
+
+func Test_Trader_metaFor(t *testing.T) {
+	btc := statemeta.Meta{Symbol: "BTC-EUR"}
+	eth := statemeta.Meta{Symbol: "ETH-EUR"}
+
+	testCases := []struct {
+		Price    stateprice.Price
+		Chart    string
+		Expected statemeta.Meta
+	}{
+		// Test case 1, the market of a single chart is used.
+		{
+			Price:    stateprice.Price{Meta: btc},
+			Chart:    "",
+			Expected: btc,
+		},
+		// Test case 2, the market of a merged chart is looked up by its ID.
+		{
+			Price:    stateprice.Price{Charts: map[string]statemeta.Meta{"btc": btc, "eth": eth}},
+			Chart:    "eth",
+			Expected: eth,
+		},
+		// Test case 3, unknown charts fall back to the market of the summary.
+		{
+			Price:    stateprice.Price{Charts: map[string]statemeta.Meta{"btc": btc}, Meta: eth},
+			Chart:    "xrp",
+			Expected: eth,
+		},
+	}
+
+	for i, testCase := range testCases {
+		meta := metaFor(testCase.Price, testCase.Chart)
+		if !reflect.DeepEqual(meta, testCase.Expected) {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", meta)
+		}
+	}
+}
+
+// Test_Trader_prices_Generation makes sure the iterators and the price
+// summaries of the informer belong to the same reload generation.
+func Test_Trader_prices_Generation(t *testing.T) {
+	newInformer := &testInformer{}
+	tr := &Trader{informer: newInformer}
+
+	_, snapshot, err := tr.prices(context.Background())
+	if err != nil {
+		t.Fatal("expected", nil, "got", err)
+	}
+	if newInformer.calls != 2 {
+		t.Fatal("expected", 2, "got", newInformer.calls)
+	}
+	if snapshot.Generation != 1 {
+		t.Fatal("expected", 1, "got", snapshot.Generation)
+	}
+	if len(snapshot.Prices) != 1 || snapshot.Prices[0].Events != 2 {
+		t.Fatal("expected", 2, "got", snapshot.Prices)
+	}
+}
+
+// testInformer implements informer.Informer to reload its charts while the
+// first iterators are created. Its price summary reports the number of calls
+// to Prices as number of price events.
+type testInformer struct {
+	calls      int
+	generation int
+}
+
+func (i *testInformer) Prices(ctx context.Context) ([]informer.Iterator, error) {
+	i.calls++
+	if i.calls == 1 {
+		i.generation++
+	}
+
+	return nil, nil
+}
+
+func (i *testInformer) Runtime() runtime.Runtime {
+	var r runtime.Runtime
+	r.State.Reload.Generation = i.generation
+	r.State.Prices = []stateprice.Price{{Events: i.calls}}
+
+	return r
+}
+
+// testClient implements client.Client to record the charts of buy events.
+type testClient struct {
+	buys []string
+}
+
+func (c *testClient) Buy(price informer.Price, volume float64) error {
+	c.buys = append(c.buys, price.Chart)
+	return nil
+}
+
+func (c *testClient) Close() error {
+	return nil
+}
+
+func (c *testClient) Sell(price informer.Price, volume float64) error {
+	return nil
+}
+
 func testSum(list []float64) float64 {
 	var s float64
 
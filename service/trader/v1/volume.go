@@ -2,13 +2,36 @@ package v1
 
 import (
 	"math"
+
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
 )
 
-func calculateVolume(price, budget float64) float64 {
+// calculateVolume returns the volume the given budget buys at the given price.
+// The volume is rounded down to the given lot size. In case no lot size is
+// given, the volume is rounded down to 2 decimal places.
+func calculateVolume(price, budget, lotSize float64) float64 {
 	f := budget / price
-	v := roundDown(f, 2)
 
-	return v
+	if lotSize > 0 {
+		return roundDownTo(f, lotSize)
+	}
+
+	return roundDown(f, 2)
+}
+
+// calculateRevenue returns the absolute revenue of buying and selling the given
+// volume at the given prices. In case the given fee schedule is not nil, the
+// taker fee is charged for the buy and the sell event.
+func calculateRevenue(buyPrice, sellPrice, volume float64, fee *statemeta.Fee) float64 {
+	buy := buyPrice * volume
+	sell := sellPrice * volume
+
+	if fee != nil {
+		buy += buy * fee.Taker / 100
+		sell -= sell * fee.Taker / 100
+	}
+
+	return sell - buy
 }
 
 func roundDown(f float64, places int) float64 {
@@ -18,3 +41,12 @@ func roundDown(f float64, places int) float64 {
 
 	return d
 }
+
+// roundDownTo rounds the given number down to a multiple of the given step.
+// Steps are usually no exact binary fractions, so the result is rounded to 12
+// decimal places to get rid of floating point artifacts.
+func roundDownTo(f, step float64) float64 {
+	n := math.Floor(f/step + 1e-9)
+
+	return math.Round(n*step*1e12) / 1e12
+}
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"math"
+	"testing"
+
+	statemeta "github.com/xh3b4sd/wafer/service/informer/csv/runtime/state/file/header/meta"
+)
+
+func Test_calculateVolume(t *testing.T) {
+	testCases := []struct {
+		Price    float64
+		Budget   float64
+		LotSize  float64
+		Expected float64
+	}{
+		// Test case 1 makes sure volumes are rounded down to 2 decimal places in
+		// case no lot size is given.
+		{
+			Price:    float64(3),
+			Budget:   float64(500),
+			LotSize:  float64(0),
+			Expected: float64(166.66),
+		},
+		// Test case 2 makes sure volumes are rounded down to the given lot size.
+		{
+			Price:    float64(3),
+			Budget:   float64(500),
+			LotSize:  float64(0.001),
+			Expected: float64(166.666),
+		},
+		// Test case 3 makes sure lot sizes greater than 1 are respected.
+		{
+			Price:    float64(3),
+			Budget:   float64(500),
+			LotSize:  float64(5),
+			Expected: float64(165),
+		},
+		// Test case 4 makes sure volumes matching the lot size exactly are not
+		// rounded down any further.
+		{
+			Price:    float64(100),
+			Budget:   float64(30),
+			LotSize:  float64(0.1),
+			Expected: float64(0.3),
+		},
+	}
+
+	for i, testCase := range testCases {
+		v := calculateVolume(testCase.Price, testCase.Budget, testCase.LotSize)
+		if v != testCase.Expected {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", v)
+		}
+	}
+}
+
+func Test_calculateRevenue(t *testing.T) {
+	testCases := []struct {
+		BuyPrice  float64
+		SellPrice float64
+		Volume    float64
+		Fee       *statemeta.Fee
+		Expected  float64
+	}{
+		// Test case 1 makes sure no fees are charged in case the market does not
+		// declare them.
+		{
+			BuyPrice:  float64(100),
+			SellPrice: float64(110),
+			Volume:    float64(2),
+			Fee:       nil,
+			Expected:  float64(20),
+		},
+		// Test case 2 makes sure the taker fee is charged for buying and selling.
+		{
+			BuyPrice:  float64(100),
+			SellPrice: float64(110),
+			Volume:    float64(2),
+			Fee:       &statemeta.Fee{Maker: 0, Taker: 1},
+			Expected:  float64(15.8),
+		},
+	}
+
+	for i, testCase := range testCases {
+		r := calculateRevenue(testCase.BuyPrice, testCase.SellPrice, testCase.Volume, testCase.Fee)
+		if math.Abs(r-testCase.Expected) > 1e-9 {
+			t.Fatal("case", i+1, "expected", testCase.Expected, "got", r)
+		}
+	}
+}